@@ -0,0 +1,143 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Aliases maps alternative chromosome names to the names used in a genome
+// binning, for example "1" to "chr1" or "MT" to "chrM".
+type Aliases map[string]string
+
+// WithAliases returns a copy of the genome binning in which each chromosome
+// can also be referred to by its aliases. It returns an error if an alias
+// refers to an unknown chromosome or is already in use for another
+// chromosome.
+func (g GenomeBinning) WithAliases(aliases Aliases) (GenomeBinning, error) {
+	index := make(map[string]int, len(g.index)+len(aliases))
+	for name, i := range g.index {
+		index[name] = i
+	}
+	for alias, name := range aliases {
+		i, ok := g.index[name]
+		if !ok {
+			return GenomeBinning{}, errors.New(fmt.Sprintf("alias %q for unknown chromosome: %q", alias, name))
+		}
+		if j, ok := index[alias]; ok && j != i {
+			return GenomeBinning{}, errors.New(fmt.Sprintf("alias %q for chromosome %q is already used for chromosome %q",
+				alias, name, g.chromosomes[j].Name))
+		}
+		index[alias] = i
+	}
+	g.index = index
+	return g, nil
+}
+
+// Aliases returns the aliases for chromosomes in the genome binning.
+func (g GenomeBinning) Aliases() Aliases {
+	aliases := make(Aliases)
+	for name, i := range g.index {
+		if name != g.chromosomes[i].Name {
+			aliases[name] = g.chromosomes[i].Name
+		}
+	}
+	return aliases
+}
+
+// chromosomeAliases returns the aliases per chromosome, sorted by name.
+func (g GenomeBinning) chromosomeAliases() [][]string {
+	aliases := make([][]string, len(g.chromosomes))
+	for name, i := range g.index {
+		if name != g.chromosomes[i].Name {
+			aliases[i] = append(aliases[i], name)
+		}
+	}
+	for i := range aliases {
+		slices.Sort(aliases[i])
+	}
+	return aliases
+}
+
+// Canonical returns the name of chromosome chrom as given to
+// NewGenomeBinning, resolving aliases.
+func (g GenomeBinning) Canonical(chrom string) (string, error) {
+	i, err := g.lookup(chrom)
+	if err != nil {
+		return "", err
+	}
+	return g.chromosomes[i].Name, nil
+}
+
+// assemblyAliases returns aliases for chromosomes with UCSC-style names:
+// the name without "chr" prefix (with "MT" for "chrM") and the RefSeq
+// accession from refseq at the same position.
+func assemblyAliases(chromosomes []Chromosome, refseq []string) Aliases {
+	aliases := make(Aliases, 2*len(chromosomes))
+	for i, c := range chromosomes {
+		if c.Name == "chrM" {
+			aliases["MT"] = c.Name
+		} else {
+			aliases[strings.TrimPrefix(c.Name, "chr")] = c.Name
+		}
+		aliases[refseq[i]] = c.Name
+	}
+	return aliases
+}
+
+var grch37RefSeq = []string{
+	"NC_000001.10", "NC_000002.11", "NC_000003.11", "NC_000004.11", "NC_000005.9",
+	"NC_000006.11", "NC_000007.13", "NC_000008.10", "NC_000009.11", "NC_000010.10",
+	"NC_000011.9", "NC_000012.11", "NC_000013.10", "NC_000014.8", "NC_000015.9",
+	"NC_000016.9", "NC_000017.10", "NC_000018.9", "NC_000019.9", "NC_000020.10",
+	"NC_000021.8", "NC_000022.10", "NC_000023.10", "NC_000024.9", "NC_012920.1",
+}
+
+var grch38RefSeq = []string{
+	"NC_000001.11", "NC_000002.12", "NC_000003.12", "NC_000004.12", "NC_000005.10",
+	"NC_000006.12", "NC_000007.14", "NC_000008.11", "NC_000009.12", "NC_000010.11",
+	"NC_000011.10", "NC_000012.12", "NC_000013.11", "NC_000014.9", "NC_000015.10",
+	"NC_000016.10", "NC_000017.11", "NC_000018.10", "NC_000019.10", "NC_000020.11",
+	"NC_000021.9", "NC_000022.11", "NC_000023.11", "NC_000024.10", "NC_012920.1",
+}
+
+var mm10RefSeq = []string{
+	"NC_000067.6", "NC_000068.7", "NC_000069.6", "NC_000070.6", "NC_000071.6",
+	"NC_000072.6", "NC_000073.6", "NC_000074.6", "NC_000075.6", "NC_000076.6",
+	"NC_000077.6", "NC_000078.6", "NC_000079.6", "NC_000080.6", "NC_000081.6",
+	"NC_000082.6", "NC_000083.6", "NC_000084.6", "NC_000085.6", "NC_000086.7",
+	"NC_000087.7", "NC_005089.1",
+}
+
+var mm39RefSeq = []string{
+	"NC_000067.7", "NC_000068.8", "NC_000069.7", "NC_000070.7", "NC_000071.7",
+	"NC_000072.7", "NC_000073.7", "NC_000074.7", "NC_000075.7", "NC_000076.7",
+	"NC_000077.7", "NC_000078.7", "NC_000079.7", "NC_000080.7", "NC_000081.7",
+	"NC_000082.7", "NC_000083.7", "NC_000084.7", "NC_000085.7", "NC_000086.8",
+	"NC_000087.8", "NC_005089.1",
+}
+
+// GRCh37Aliases returns Ensembl-style names (1, ..., X, Y, MT) and RefSeq
+// accessions as aliases for the chromosomes in GRCh37.
+func GRCh37Aliases() Aliases {
+	return assemblyAliases(grch37Chromosomes, grch37RefSeq)
+}
+
+// GRCh38Aliases returns Ensembl-style names (1, ..., X, Y, MT) and RefSeq
+// accessions as aliases for the chromosomes in GRCh38.
+func GRCh38Aliases() Aliases {
+	return assemblyAliases(grch38Chromosomes, grch38RefSeq)
+}
+
+// MM10Aliases returns Ensembl-style names (1, ..., X, Y, MT) and RefSeq
+// accessions as aliases for the chromosomes in MM10.
+func MM10Aliases() Aliases {
+	return assemblyAliases(mm10Chromosomes, mm10RefSeq)
+}
+
+// MM39Aliases returns Ensembl-style names (1, ..., X, Y, MT) and RefSeq
+// accessions as aliases for the chromosomes in MM39.
+func MM39Aliases() Aliases {
+	return assemblyAliases(mm39Chromosomes, mm39RefSeq)
+}
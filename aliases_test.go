@@ -0,0 +1,69 @@
+package binning
+
+import "testing"
+
+func TestWithAliases(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	g, error := g.WithAliases(Aliases{"1": "chr1", "21": "chr21", "MT": "chrM"})
+	if error != nil {
+		t.Fatalf("WithAliases() returned error: %v", error)
+	}
+	for _, v := range []struct{ alias, chrom string }{
+		{"1", "chr1"},
+		{"chr1", "chr1"},
+		{"MT", "chrM"},
+	} {
+		if name, error := g.Canonical(v.alias); error != nil {
+			t.Errorf("Canonical(%q) returned error: %v", v.alias, error)
+		} else if name != v.chrom {
+			t.Errorf("Canonical(%q) = %q, expected %q", v.alias, name, v.chrom)
+		}
+	}
+	if bin, error := g.Assign("21", 74012, 173034); error != nil {
+		t.Errorf("Assign(%q, %d, %d) returned error: %v", "21", 74012, 173034, error)
+	} else if bin != 73 {
+		t.Errorf("Assign(%q, %d, %d) = %d, expected %d", "21", 74012, 173034, bin, 73)
+	}
+	if bin, error := g.Assign("MT", 0, 16570); error == nil {
+		t.Errorf("Assign(%q, %d, %d) = %d, expected error", "MT", 0, 16570, bin)
+	}
+	if name, error := g.Canonical("M"); error == nil {
+		t.Errorf("Canonical(%q) = %q, expected error", "M", name)
+	}
+}
+
+func TestWithAliasesInvalid(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	for _, aliases := range []Aliases{
+		{"2": "chr2"},
+		{"chr1": "chr21"},
+	} {
+		if _, error := g.WithAliases(aliases); error == nil {
+			t.Errorf("WithAliases(%v) did not return error", aliases)
+		}
+	}
+}
+
+func TestAssemblyAliases(t *testing.T) {
+	for _, v := range []struct {
+		name          string
+		g             GenomeBinning
+		refseq, chrom string
+	}{
+		{"GRCh37", GRCh37(), "NC_000001.10", "chr1"},
+		{"GRCh38", GRCh38(), "NC_000023.11", "chrX"},
+		{"MM10", MM10(), "NC_005089.1", "chrM"},
+		{"MM39", MM39(), "NC_000085.7", "chr19"},
+	} {
+		for _, alias := range []string{v.refseq, v.chrom[3:], v.chrom} {
+			if v.chrom == "chrM" && alias == "M" {
+				alias = "MT"
+			}
+			if name, error := v.g.Canonical(alias); error != nil {
+				t.Errorf("%s().Canonical(%q) returned error: %v", v.name, alias, error)
+			} else if name != v.chrom {
+				t.Errorf("%s().Canonical(%q) = %q, expected %q", v.name, alias, name, v.chrom)
+			}
+		}
+	}
+}
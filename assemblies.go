@@ -0,0 +1,91 @@
+package binning
+
+// This file provides genome binnings for common assemblies, using primary
+// chromosomes with UCSC-style names (chr1, ..., chrX, chrY, chrM). Ensembl-style
+// names and RefSeq accessions are accepted as aliases, see aliases.go.
+
+var grch37Chromosomes = []Chromosome{
+	{"chr1", 249250621}, {"chr2", 243199373}, {"chr3", 198022430},
+	{"chr4", 191154276}, {"chr5", 180915260}, {"chr6", 171115067},
+	{"chr7", 159138663}, {"chr8", 146364022}, {"chr9", 141213431},
+	{"chr10", 135534747}, {"chr11", 135006516}, {"chr12", 133851895},
+	{"chr13", 115169878}, {"chr14", 107349540}, {"chr15", 102531392},
+	{"chr16", 90354753}, {"chr17", 81195210}, {"chr18", 78077248},
+	{"chr19", 59128983}, {"chr20", 63025520}, {"chr21", 48129895},
+	{"chr22", 51304566}, {"chrX", 155270560}, {"chrY", 59373566},
+	{"chrM", 16569},
+}
+
+var grch38Chromosomes = []Chromosome{
+	{"chr1", 248956422}, {"chr2", 242193529}, {"chr3", 198295559},
+	{"chr4", 190214555}, {"chr5", 181538259}, {"chr6", 170805979},
+	{"chr7", 159345973}, {"chr8", 145138636}, {"chr9", 138394717},
+	{"chr10", 133797422}, {"chr11", 135086622}, {"chr12", 133275309},
+	{"chr13", 114364328}, {"chr14", 107043718}, {"chr15", 101991189},
+	{"chr16", 90338345}, {"chr17", 83257441}, {"chr18", 80373285},
+	{"chr19", 58617616}, {"chr20", 64444167}, {"chr21", 46709983},
+	{"chr22", 50818468}, {"chrX", 156040895}, {"chrY", 57227415},
+	{"chrM", 16569},
+}
+
+var mm10Chromosomes = []Chromosome{
+	{"chr1", 195471971}, {"chr2", 182113224}, {"chr3", 160039680},
+	{"chr4", 156508116}, {"chr5", 151834684}, {"chr6", 149736546},
+	{"chr7", 145441459}, {"chr8", 129401213}, {"chr9", 124595110},
+	{"chr10", 130694993}, {"chr11", 122082543}, {"chr12", 120129022},
+	{"chr13", 120421639}, {"chr14", 124902244}, {"chr15", 104043685},
+	{"chr16", 98207768}, {"chr17", 94987271}, {"chr18", 90702639},
+	{"chr19", 61431566}, {"chrX", 171031299}, {"chrY", 91744698},
+	{"chrM", 16299},
+}
+
+var mm39Chromosomes = []Chromosome{
+	{"chr1", 195154279}, {"chr2", 181755017}, {"chr3", 159745316},
+	{"chr4", 156860686}, {"chr5", 151758149}, {"chr6", 149588044},
+	{"chr7", 144995196}, {"chr8", 130127694}, {"chr9", 124359700},
+	{"chr10", 130530862}, {"chr11", 121973369}, {"chr12", 120092757},
+	{"chr13", 120883175}, {"chr14", 125139656}, {"chr15", 104073951},
+	{"chr16", 98008968}, {"chr17", 95294699}, {"chr18", 90720763},
+	{"chr19", 61420004}, {"chrX", 169476592}, {"chrY", 91455967},
+	{"chrM", 16299},
+}
+
+// mustGenomeBinning is like NewGenomeBinning followed by WithAliases but
+// panics if the chromosomes or aliases are invalid. It is only used for the
+// chromosomes defined in this file.
+func mustGenomeBinning(chromosomes []Chromosome, aliases Aliases) GenomeBinning {
+	g, err := NewGenomeBinning(chromosomes)
+	if err != nil {
+		panic(err)
+	}
+	g, err = g.WithAliases(aliases)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// GRCh37 returns a genome binning for the primary chromosomes of the human
+// GRCh37 (hg19) assembly. The mitochondrial chromosome is the GRCh37 rCRS
+// sequence (16569 bases), not the hg19 chrM sequence.
+func GRCh37() GenomeBinning {
+	return mustGenomeBinning(grch37Chromosomes, GRCh37Aliases())
+}
+
+// GRCh38 returns a genome binning for the primary chromosomes of the human
+// GRCh38 (hg38) assembly.
+func GRCh38() GenomeBinning {
+	return mustGenomeBinning(grch38Chromosomes, GRCh38Aliases())
+}
+
+// MM10 returns a genome binning for the primary chromosomes of the mouse
+// GRCm38 (mm10) assembly.
+func MM10() GenomeBinning {
+	return mustGenomeBinning(mm10Chromosomes, MM10Aliases())
+}
+
+// MM39 returns a genome binning for the primary chromosomes of the mouse
+// GRCm39 (mm39) assembly.
+func MM39() GenomeBinning {
+	return mustGenomeBinning(mm39Chromosomes, MM39Aliases())
+}
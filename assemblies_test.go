@@ -0,0 +1,30 @@
+package binning
+
+import "testing"
+
+func TestAssemblies(t *testing.T) {
+	for _, v := range []struct {
+		name        string
+		g           GenomeBinning
+		chromosomes int
+		chr1        int
+	}{
+		{"GRCh37", GRCh37(), 25, 249250621},
+		{"GRCh38", GRCh38(), 25, 248956422},
+		{"MM10", MM10(), 22, 195471971},
+		{"MM39", MM39(), 22, 195154279},
+	} {
+		chromosomes := v.g.Chromosomes()
+		if len(chromosomes) != v.chromosomes {
+			t.Errorf("len(%s().Chromosomes()) = %d, expected %d", v.name, len(chromosomes), v.chromosomes)
+		}
+		if bin, error := v.g.Assign("chr1", v.chr1-1, v.chr1); error != nil {
+			t.Errorf("%s().Assign(%q, %d, %d) returned error: %v", v.name, "chr1", v.chr1-1, v.chr1, error)
+		} else if bin != 585+(v.chr1-1)>>17 {
+			t.Errorf("%s().Assign(%q, %d, %d) = %d, expected %d", v.name, "chr1", v.chr1-1, v.chr1, bin, 585+(v.chr1-1)>>17)
+		}
+		if bin, error := v.g.Assign("chr1", v.chr1, v.chr1+1); error == nil {
+			t.Errorf("%s().Assign(%q, %d, %d) = %d, expected error", v.name, "chr1", v.chr1, v.chr1+1, bin)
+		}
+	}
+}
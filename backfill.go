@@ -0,0 +1,127 @@
+package binning
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// An Execer runs SQL queries and statements, such as *sql.DB or *sql.Tx.
+type Execer interface {
+	Queryer
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// BackfillBins sets the bin column of rows in table without a bin to the
+// bins assigned by binning scheme b, in batches of size rows in the order of
+// the integer key column. Only rows with a key after after are updated.
+// After each batch, progress is called with the number of rows updated so
+// far and the last key of the batch, if progress is not nil. The last key
+// updated is returned, also on error, so an interrupted backfill can resume
+// from there. Rows already having a bin are skipped, so resuming from the
+// start is also safe. Column names are set with WithRegionColumns.
+// ClickHouse is not supported, because it only updates rows asynchronously.
+func BackfillBins(ctx context.Context, db Execer, b Binning, table, key string, after int64, size int, progress func(rows int, last int64), options ...SQLOption) (int64, error) {
+	c := sqlOptions(options)
+	if size < 1 {
+		return after, errors.New(fmt.Sprintf("invalid batch size: %d", size))
+	}
+	if c.dialect == ClickHouse {
+		return after, errors.New("dialect does not support updates")
+	}
+	table, err := c.dialect.Quote(table)
+	if err != nil {
+		return after, err
+	}
+	key, err = c.dialect.Quote(key)
+	if err != nil {
+		return after, err
+	}
+	var columns [4]string
+	for i, column := range c.columns {
+		if columns[i], err = c.dialect.Quote(column); err != nil {
+			return after, err
+		}
+	}
+	// Without a cast, PostgreSQL resolves a CASE of untyped parameters to
+	// text, which cannot be assigned to the bin column.
+	cast := ""
+	if c.dialect == PostgreSQL || c.dialect == DuckDB {
+		cast = "::bigint"
+	}
+	selectBatch := fmt.Sprintf("SELECT %s, %s, %s FROM %s WHERE %s > %s AND %s IS NULL ORDER BY %s LIMIT %d",
+		key, columns[1], columns[2], table, key, c.placeholder(1), columns[3], key, size)
+
+	rows := 0
+	for {
+		keys, bins, err := backfillBatch(ctx, db, b, selectBatch, after)
+		if err != nil {
+			return after, err
+		}
+		if len(keys) == 0 {
+			return after, nil
+		}
+
+		var s strings.Builder
+		args := make([]any, 0, 3*len(keys))
+		arg := func(value any) string {
+			args = append(args, value)
+			return c.placeholder(len(args))
+		}
+		s.WriteString("UPDATE " + table + " SET " + columns[3] + " = CASE " + key)
+		for i, k := range keys {
+			s.WriteString(" WHEN " + arg(k) + " THEN " + arg(int64(bins[i])) + cast)
+		}
+		s.WriteString(" END WHERE " + key + " IN (")
+		for i, k := range keys {
+			if i > 0 {
+				s.WriteByte(',')
+			}
+			s.WriteString(arg(k))
+		}
+		s.WriteByte(')')
+		if _, err := db.ExecContext(ctx, s.String(), args...); err != nil {
+			return after, err
+		}
+
+		rows += len(keys)
+		after = keys[len(keys)-1]
+		if progress != nil {
+			progress(rows, after)
+		}
+	}
+}
+
+// backfillBatch returns the keys and assigned bins of the rows selected by
+// query for keys after after.
+func backfillBatch(ctx context.Context, db Queryer, b Binning, query string, after int64) ([]int64, []int, error) {
+	rows, err := db.QueryContext(ctx, query, after)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var keys []int64
+	var bins []int
+	for rows.Next() {
+		var key, start, stop int64
+		if err := rows.Scan(&key, &start, &stop); err != nil {
+			return nil, nil, err
+		}
+		bin, err := b.Assign(int(start), int(stop))
+		if int64(int(start)) != start || int64(int(stop)) != stop {
+			err = errors.New(fmt.Sprintf("interval %d-%d out of range", start, stop))
+		}
+		if err != nil {
+			return nil, nil, errors.New(fmt.Sprintf("row with key %d: %v", key, err))
+		}
+		keys = append(keys, key)
+		bins = append(bins, bin)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return keys, bins, rows.Close()
+}
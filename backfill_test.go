@@ -0,0 +1,100 @@
+package binning
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"slices"
+	"testing"
+)
+
+func TestBackfillBins(t *testing.T) {
+	db, error := sql.Open("binning-fake", "")
+	if error != nil {
+		t.Fatalf("sql.Open error: %v", error)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		dialect          Dialect
+		query, statement string
+	}{
+		{
+			PostgreSQL,
+			`SELECT "id", "start", "stop" FROM "genes" WHERE "id" > $1 AND "bin" IS NULL ORDER BY "id" LIMIT 100`,
+			`UPDATE "genes" SET "bin" = CASE "id" WHEN $1 THEN $2::bigint WHEN $3 THEN $4::bigint END WHERE "id" IN ($5,$6)`,
+		},
+		{
+			DuckDB,
+			`SELECT "id", "start", "stop" FROM "genes" WHERE "id" > ? AND "bin" IS NULL ORDER BY "id" LIMIT 100`,
+			`UPDATE "genes" SET "bin" = CASE "id" WHEN ? THEN ?::bigint WHEN ? THEN ?::bigint END WHERE "id" IN (?,?)`,
+		},
+		{
+			SQLite,
+			`SELECT "id", "start", "stop" FROM "genes" WHERE "id" > ? AND "bin" IS NULL ORDER BY "id" LIMIT 100`,
+			`UPDATE "genes" SET "bin" = CASE "id" WHEN ? THEN ? WHEN ? THEN ? END WHERE "id" IN (?,?)`,
+		},
+		{
+			MySQL,
+			"SELECT `id`, `start`, `stop` FROM `genes` WHERE `id` > ? AND `bin` IS NULL ORDER BY `id` LIMIT 100",
+			"UPDATE `genes` SET `bin` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? END WHERE `id` IN (?,?)",
+		},
+		{
+			BigQuery,
+			"SELECT `id`, `start`, `stop` FROM `genes` WHERE `id` > ? AND `bin` IS NULL ORDER BY `id` LIMIT 100",
+			"UPDATE `genes` SET `bin` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? END WHERE `id` IN (?,?)",
+		},
+	}
+	columns := []string{"id", "start", "stop"}
+	for _, test := range tests {
+		fakeQueries = []fakeQuery{
+			{test.query, columns, [][]driver.Value{{int64(3), int64(10), int64(20)}, {int64(7), int64(1 << 17), int64(1<<17 + 5)}}},
+			{test.query, columns, nil},
+		}
+		fakeExecs = nil
+		var progress []int64
+		last, error := BackfillBins(context.Background(), db, StandardBinning(), "genes", "id", 2, 100, func(rows int, last int64) {
+			progress = append(progress, int64(rows), last)
+		}, WithDialect(test.dialect))
+		if error != nil {
+			t.Errorf("BackfillBins for dialect %v error: %v", test.dialect, error)
+			continue
+		}
+		if last != 7 {
+			t.Errorf("BackfillBins for dialect %v = %d, expected %d", test.dialect, last, 7)
+		}
+		if expected := []int64{2, 7}; !slices.Equal(progress, expected) {
+			t.Errorf("BackfillBins for dialect %v progress = %v, expected %v", test.dialect, progress, expected)
+		}
+		if len(fakeQueries) != 0 {
+			t.Errorf("BackfillBins for dialect %v did not run %d expected queries", test.dialect, len(fakeQueries))
+		}
+		if len(fakeExecs) != 1 {
+			t.Errorf("BackfillBins for dialect %v executed %d statements, expected 1", test.dialect, len(fakeExecs))
+			continue
+		}
+		if fakeExecs[0].statement != test.statement {
+			t.Errorf("BackfillBins for dialect %v executed %q, expected %q", test.dialect, fakeExecs[0].statement, test.statement)
+		}
+		if args := fakeExecs[0].args; !slices.Equal(args, []driver.Value{int64(3), int64(585), int64(7), int64(586), int64(3), int64(7)}) {
+			t.Errorf("BackfillBins for dialect %v executed with args %v", test.dialect, args)
+		}
+	}
+
+	query := "SELECT id, start, stop FROM genes WHERE id > ? AND bin IS NULL ORDER BY id LIMIT 100"
+	fakeQueries = []fakeQuery{{query, columns, [][]driver.Value{{int64(9), int64(0), int64(1 << 30)}}}}
+	last, error := BackfillBins(context.Background(), db, StandardBinning(), "genes", "id", 7, 100, nil)
+	if error == nil {
+		t.Errorf("BackfillBins with invalid interval did not return error")
+	}
+	if last != 7 {
+		t.Errorf("BackfillBins with invalid interval = %d, expected %d", last, 7)
+	}
+
+	if _, error := BackfillBins(context.Background(), db, StandardBinning(), "genes", "id", 0, 0, nil); error == nil {
+		t.Errorf("BackfillBins with batch size 0 did not return error")
+	}
+	if _, error := BackfillBins(context.Background(), db, StandardBinning(), "genes", "id", 0, 100, nil, WithDialect(ClickHouse)); error == nil {
+		t.Errorf("BackfillBins for ClickHouse did not return error")
+	}
+}
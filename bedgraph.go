@@ -0,0 +1,42 @@
+package binning
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// WriteBedGraph writes values per bin, such as the counts or mean scores
+// returned by ReduceByBin, to w in the bedGraph format for chromosome chrom.
+// Each bin is written as the interval covered by it, truncated to the range
+// of the binning scheme, ordered by position. It returns an error if bins
+// overlap, which happens when values are given for bins on different levels.
+// https://genome.ucsc.edu/goldenPath/help/bedgraph.html
+func WriteBedGraph(w io.Writer, chrom string, b Binning, values map[int]float64) error {
+	type line struct {
+		start, stop int
+		value       float64
+	}
+	lines := make([]line, 0, len(values))
+	for bin, value := range values {
+		start, stop, err := b.Covered(bin)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line{start, min(stop-1, b.MaxPosition) + 1, value})
+	}
+	slices.SortFunc(lines, func(a, b line) int { return cmp.Compare(a.start, b.start) })
+
+	writer := bufio.NewWriter(w)
+	for i, l := range lines {
+		if i > 0 && l.start < lines[i-1].stop {
+			return errors.New(fmt.Sprintf("overlapping bins at %d-%d and %d-%d", lines[i-1].start, lines[i-1].stop, l.start, l.stop))
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%s\n", chrom, l.start, l.stop, strconv.FormatFloat(l.value, 'g', -1, 64))
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,44 @@
+package binning
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteBedGraph(t *testing.T) {
+	var b strings.Builder
+	values := map[int]float64{586: 2.5, 585: 3, 4680: 0.125}
+	if error := WriteBedGraph(&b, "chr1", StandardBinning(), values); error != nil {
+		t.Fatalf("WriteBedGraph error: %v", error)
+	}
+	expected := "chr1\t0\t131072\t3\nchr1\t131072\t262144\t2.5\nchr1\t536739840\t536870912\t0.125\n"
+	if b.String() != expected {
+		t.Errorf("WriteBedGraph = %q, expected %q", b.String(), expected)
+	}
+}
+
+func TestWriteBedGraphFromReduceByBin(t *testing.T) {
+	x := newTestIndex(t)
+	counts, _ := ReduceByBin(x, 0, func(bin int, values []string) float64 { return float64(len(values)) })
+
+	var b strings.Builder
+	if error := WriteBedGraph(&b, "chr1", x.Scheme(), counts); error != nil {
+		t.Fatalf("WriteBedGraph error: %v", error)
+	}
+	expected := "chr1\t0\t131072\t4\nchr1\t131072\t262144\t1\nchr1\t917504\t1048576\t1\n"
+	if b.String() != expected {
+		t.Errorf("WriteBedGraph = %q, expected %q", b.String(), expected)
+	}
+}
+
+func TestWriteBedGraphInvalid(t *testing.T) {
+	tests := []map[int]float64{
+		{585: 1, 73: 2},
+		{-1: 1},
+	}
+	for _, values := range tests {
+		if error := WriteBedGraph(&strings.Builder{}, "chr1", StandardBinning(), values); error == nil {
+			t.Errorf("WriteBedGraph(%v) did not return error", values)
+		}
+	}
+}
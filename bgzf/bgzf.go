@@ -0,0 +1,274 @@
+// Package bgzf implements reading and writing of BGZF files, the blocked
+// gzip format used by bgzip, BAM, tabix and related tools.
+//
+// A BGZF file is a series of gzip members (blocks) of at most 64 KiB each,
+// ending with an empty block. Positions in the uncompressed data are given
+// as virtual offsets, combining the offset of a block in the compressed file
+// with an offset in the uncompressed data of that block.
+// http://samtools.github.io/hts-specs/SAMv1.pdf
+package bgzf
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// A VirtualOffset is a position in the uncompressed data of a BGZF file,
+// with the offset of a block in the compressed file in the upper 48 bits and
+// an offset in the uncompressed data of that block in the lower 16 bits.
+type VirtualOffset uint64
+
+// NewVirtualOffset returns the virtual offset for offset within in the block
+// starting at offset block in the compressed file.
+func NewVirtualOffset(block int64, within int) VirtualOffset {
+	return VirtualOffset(uint64(block)<<16 | uint64(within))
+}
+
+// Block returns the offset of the block in the compressed file.
+func (v VirtualOffset) Block() int64 {
+	return int64(v >> 16)
+}
+
+// Within returns the offset in the uncompressed data of the block.
+func (v VirtualOffset) Within() int {
+	return int(v & 0xffff)
+}
+
+func (v VirtualOffset) String() string {
+	return fmt.Sprintf("%d:%d", v.Block(), v.Within())
+}
+
+// BlockSize is the maximum number of uncompressed bytes written per block.
+const BlockSize = 0xff00
+
+// headerSize is the size of a block header with only the BC extra subfield.
+const headerSize = 18
+
+// eof is the empty block marking the end of a BGZF file.
+var eof = []byte{
+	0x1f, 0x8b, 0x08, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x06, 0x00, 0x42, 0x43,
+	0x02, 0x00, 0x1b, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+}
+
+// A Writer compresses data written to it into BGZF blocks.
+type Writer struct {
+	w      io.Writer
+	buffer []byte
+	offset int64
+	err    error
+}
+
+// NewWriter returns a new Writer writing BGZF blocks to w. The caller must
+// call Close when done to write the final blocks.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, buffer: make([]byte, 0, BlockSize)}
+}
+
+// Offset returns the virtual offset of the next byte to be written.
+func (w *Writer) Offset() VirtualOffset {
+	return NewVirtualOffset(w.offset, len(w.buffer))
+}
+
+// Write writes p to the uncompressed data, writing blocks as they fill up.
+func (w *Writer) Write(p []byte) (int, error) {
+	n := 0
+	for w.err == nil && len(p) > 0 {
+		m := min(len(p), BlockSize-len(w.buffer))
+		w.buffer = append(w.buffer, p[:m]...)
+		p, n = p[m:], n+m
+		if len(w.buffer) == BlockSize {
+			w.Flush()
+		}
+	}
+	return n, w.err
+}
+
+// Flush writes the buffered data as a block, so the next byte written starts
+// a new block. It does nothing if no data is buffered.
+func (w *Writer) Flush() error {
+	if w.err != nil || len(w.buffer) == 0 {
+		return w.err
+	}
+	var compressed bytes.Buffer
+	compressor, _ := flate.NewWriter(&compressed, flate.DefaultCompression)
+	compressor.Write(w.buffer)
+	compressor.Close()
+
+	block := make([]byte, headerSize, headerSize+compressed.Len()+8)
+	copy(block, eof[:headerSize])
+	binary.LittleEndian.PutUint16(block[16:], uint16(headerSize+compressed.Len()+8-1))
+	block = append(block, compressed.Bytes()...)
+	block = binary.LittleEndian.AppendUint32(block, crc32.ChecksumIEEE(w.buffer))
+	block = binary.LittleEndian.AppendUint32(block, uint32(len(w.buffer)))
+
+	_, w.err = w.w.Write(block)
+	w.offset += int64(len(block))
+	w.buffer = w.buffer[:0]
+	return w.err
+}
+
+// Close writes the buffered data and the end-of-file marker block. It does
+// not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, w.err = w.w.Write(eof)
+	w.offset += int64(len(eof))
+	if w.err == nil {
+		w.err = errors.New("bgzf: writer is closed")
+		return nil
+	}
+	return w.err
+}
+
+// A Reader decompresses the BGZF blocks read from an underlying reader.
+type Reader struct {
+	r      io.Reader
+	block  []byte
+	pos    int
+	offset int64
+	next   int64
+	err    error
+}
+
+// NewReader returns a new Reader reading BGZF blocks from r. Seek is only
+// supported if r implements io.Seeker.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Offset returns the virtual offset of the next byte to be read. At the end
+// of a block, this is the start of the next block.
+func (r *Reader) Offset() VirtualOffset {
+	if r.pos == len(r.block) {
+		return NewVirtualOffset(r.next, 0)
+	}
+	return NewVirtualOffset(r.offset, r.pos)
+}
+
+// Seek moves to virtual offset v. The underlying reader must implement
+// io.Seeker.
+func (r *Reader) Seek(v VirtualOffset) error {
+	s, ok := r.r.(io.Seeker)
+	if !ok {
+		return errors.New("bgzf: underlying reader does not support seeking")
+	}
+	if _, err := s.Seek(v.Block(), io.SeekStart); err != nil {
+		return err
+	}
+	r.block, r.pos, r.next, r.err = nil, 0, v.Block(), nil
+	if err := r.readBlock(); err != nil {
+		return err
+	}
+	if v.Within() > len(r.block) {
+		return errors.New(fmt.Sprintf("bgzf: invalid virtual offset: %v", v))
+	}
+	r.pos = v.Within()
+	return nil
+}
+
+// readBlock reads the next non-empty block. It returns io.EOF at the end of
+// the underlying reader.
+func (r *Reader) readBlock() error {
+	for r.err == nil {
+		r.offset = r.next
+		r.block, r.pos = nil, 0
+		size, err := r.readBlockData()
+		if err != nil {
+			r.err = err
+			break
+		}
+		r.next = r.offset + int64(size)
+		if len(r.block) > 0 {
+			return nil
+		}
+	}
+	return r.err
+}
+
+// readBlockData reads one block into r.block and returns its compressed
+// size.
+func (r *Reader) readBlockData() (int, error) {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return 0, errors.New("bgzf: truncated block header")
+		}
+		return 0, err
+	}
+	if header[0] != 0x1f || header[1] != 0x8b || header[2] != 8 || header[3]&4 == 0 {
+		return 0, errors.New("bgzf: invalid block header")
+	}
+	extra := make([]byte, binary.LittleEndian.Uint16(header[10:]))
+	if _, err := io.ReadFull(r.r, extra); err != nil {
+		return 0, errors.New("bgzf: truncated block header")
+	}
+	size := -1
+	for e := extra; len(e) >= 4; {
+		length := int(binary.LittleEndian.Uint16(e[2:]))
+		if e[0] == 'B' && e[1] == 'C' && length == 2 && len(e) >= 6 {
+			size = int(binary.LittleEndian.Uint16(e[4:])) + 1
+		}
+		if len(e) < 4+length {
+			break
+		}
+		e = e[4+length:]
+	}
+	if size < len(header)+len(extra)+8 {
+		return 0, errors.New("bgzf: missing or invalid block size")
+	}
+	data := make([]byte, size-len(header)-len(extra))
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return 0, errors.New("bgzf: truncated block")
+	}
+	block, err := io.ReadAll(flate.NewReader(bytes.NewReader(data[:len(data)-8])))
+	if err != nil {
+		return 0, err
+	}
+	trailer := data[len(data)-8:]
+	if crc32.ChecksumIEEE(block) != binary.LittleEndian.Uint32(trailer) || uint32(len(block)) != binary.LittleEndian.Uint32(trailer[4:]) {
+		return 0, errors.New("bgzf: block checksum or size mismatch")
+	}
+	r.block = block
+	return size, nil
+}
+
+// Read reads uncompressed data into p.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.pos == len(r.block) {
+		if err := r.readBlock(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.block[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+// ReadBytes reads until the first occurrence of delim and returns the data
+// read, including the delimiter. If it reaches the end of the data before
+// finding delim, it returns the data read and io.EOF. The returned data may
+// span several blocks.
+func (r *Reader) ReadBytes(delim byte) ([]byte, error) {
+	var line []byte
+	for {
+		if r.pos == len(r.block) {
+			if err := r.readBlock(); err != nil {
+				return line, err
+			}
+		}
+		if i := bytes.IndexByte(r.block[r.pos:], delim); i >= 0 {
+			line = append(line, r.block[r.pos:r.pos+i+1]...)
+			r.pos += i + 1
+			return line, nil
+		}
+		line = append(line, r.block[r.pos:]...)
+		r.pos = len(r.block)
+	}
+}
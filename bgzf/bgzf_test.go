@@ -0,0 +1,113 @@
+package bgzf
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestVirtualOffset(t *testing.T) {
+	v := NewVirtualOffset(123456, 789)
+	if v.Block() != 123456 || v.Within() != 789 {
+		t.Errorf("NewVirtualOffset(123456, 789) = %v, expected 123456:789", v)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := []byte(strings.Repeat("chr1\t100\t200\tfeature\n", 10000))
+
+	var compressed bytes.Buffer
+	w := NewWriter(&compressed)
+	if _, error := w.Write(data); error != nil {
+		t.Fatalf("Write error: %v", error)
+	}
+	if error := w.Close(); error != nil {
+		t.Fatalf("Close error: %v", error)
+	}
+	if !bytes.HasSuffix(compressed.Bytes(), eof) {
+		t.Errorf("Close did not write end-of-file marker")
+	}
+
+	r := NewReader(bytes.NewReader(compressed.Bytes()))
+	result, error := io.ReadAll(r)
+	if error != nil {
+		t.Fatalf("ReadAll error: %v", error)
+	}
+	if !bytes.Equal(result, data) {
+		t.Errorf("ReadAll returned %d bytes, expected %d", len(result), len(data))
+	}
+}
+
+func TestWriterOffset(t *testing.T) {
+	var compressed bytes.Buffer
+	w := NewWriter(&compressed)
+	w.Write([]byte("first\n"))
+	if v := w.Offset(); v != NewVirtualOffset(0, 6) {
+		t.Errorf("Offset() = %v, expected 0:6", v)
+	}
+	w.Flush()
+	second := w.Offset()
+	if second.Block() != int64(compressed.Len()) || second.Within() != 0 {
+		t.Errorf("Offset() = %v, expected %d:0", second, compressed.Len())
+	}
+	w.Write([]byte("second\n"))
+	w.Close()
+
+	r := NewReader(bytes.NewReader(compressed.Bytes()))
+	if error := r.Seek(second); error != nil {
+		t.Fatalf("Seek(%v) error: %v", second, error)
+	}
+	line, error := r.ReadBytes('\n')
+	if error != nil || string(line) != "second\n" {
+		t.Errorf("ReadBytes('\\n') = %q, %v, expected \"second\\n\"", line, error)
+	}
+}
+
+func TestReadBytes(t *testing.T) {
+	var compressed bytes.Buffer
+	w := NewWriter(&compressed)
+	w.Write([]byte("a\nbc"))
+	w.Flush()
+	w.Write([]byte("d\ne"))
+	w.Close()
+
+	r := NewReader(&compressed)
+	var offsets []VirtualOffset
+	var lines []string
+	for {
+		offsets = append(offsets, r.Offset())
+		line, error := r.ReadBytes('\n')
+		if len(line) > 0 {
+			lines = append(lines, string(line))
+		}
+		if error == io.EOF {
+			break
+		}
+		if error != nil {
+			t.Fatalf("ReadBytes error: %v", error)
+		}
+	}
+	expected := []string{"a\n", "bcd\n", "e"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("ReadBytes lines = %q, expected %q", lines, expected)
+	}
+	if offsets[1] != NewVirtualOffset(0, 2) || offsets[2].Within() != 2 || offsets[2].Block() == 0 {
+		t.Errorf("Offset() before lines = %v", offsets)
+	}
+}
+
+func TestReaderInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not gzip", []byte("chr1\t100\t200\n....................")},
+		{"truncated", eof[:20]},
+	}
+	for _, test := range tests {
+		if _, error := io.ReadAll(NewReader(bytes.NewReader(test.data))); error == nil {
+			t.Errorf("ReadAll(%s) did not return error", test.name)
+		}
+	}
+}
@@ -10,60 +10,130 @@
 // as R-trees. See for example the PostGIS extension for PostgreSQL: http://postgis.net
 //
 // Although in principle the method can be used for binning any kind of
-// intervals, be aware that the largest position supported by this
-// implementation is 2^29 (which covers the longest human chromosome).
+// intervals, be aware that the largest position supported by the standard
+// scheme is 2^29 (which covers the longest human chromosome). The extended
+// scheme supports positions up to 2^31.
 //
 // All positions and ranges in this package are zero-based and open-ended,
 // following standard Go indexing and slicing notation.
 package binning
 
 import (
-	"errors"
 	"fmt"
+	"slices"
 )
 
-// A Binning implements a specific interval binning scheme.
-type Binning struct {
-	MaxPosition int
-	MaxBin      int
+// Integer is a constraint permitting the integer types that can be used for
+// positions and bin numbers. Types smaller than 32 bits are not permitted, as
+// they cannot hold the positions supported by the standard binning scheme.
+type Integer interface {
+	~int | ~int32 | ~int64 | ~uint | ~uint32 | ~uint64
+}
+
+// A Scheme implements a specific interval binning scheme on positions and bin
+// numbers of type T.
+type Scheme[T Integer] struct {
+	MaxPosition T
+	MinBin      T
+	MaxBin      T
+
+	binOffsets []T
+	shifts     []uint
+	strict     bool
+	clamp      bool
+	overflow   error
+}
+
+// A Binning implements a specific interval binning scheme on positions and
+// bin numbers of type int.
+type Binning = Scheme[int]
+
+// A Binning64 implements a specific interval binning scheme on positions and
+// bin numbers of type int64.
+type Binning64 = Scheme[int64]
 
-	binOffsets []int
-	shiftFirst uint
-	shiftNext  uint
+// check returns an InvalidSchemeError if the binning scheme has no levels or
+// its bin numbers overflow T.
+func (b Scheme[T]) check() error {
+	if len(b.binOffsets) == 0 || len(b.shifts) != len(b.binOffsets) {
+		return &InvalidSchemeError{"no levels"}
+	}
+	return b.overflow
+}
+
+// checkRange returns an error if the binning scheme is not usable (see
+// check), if the interval start:stop is not within the range of positions
+// supported by the binning scheme, or if it is empty or inverted and the
+// binning scheme is strict. If the binning scheme clamps,
+// an interval partially within the range is truncated to the range instead.
+// The (truncated) interval is returned.
+func (b Scheme[T]) checkRange(start, stop T) (T, T, error) {
+	if err := b.check(); err != nil {
+		return 0, 0, err
+	}
+	// Note that MaxPosition+1 may not be representable in T.
+	beyond := func(stop T) bool { return stop > b.MaxPosition && stop-b.MaxPosition > 1 }
+	if b.clamp && start <= b.MaxPosition && stop > 0 && stop > start {
+		start = max(start, 0)
+		if beyond(stop) {
+			stop = b.MaxPosition + 1
+		}
+	}
+	if start < 0 || start > b.MaxPosition || beyond(stop) {
+		return 0, 0, &OutOfRangeError{Start: int64(start), Stop: int64(stop), MaxPosition: int64(b.MaxPosition)}
+	}
+	if b.strict && stop <= start {
+		return 0, 0, &EmptyIntervalError{Start: int64(start), Stop: int64(stop)}
+	}
+	return start, stop, nil
+}
+
+// Strict returns a copy of the binning scheme that returns an
+// EmptyIntervalError for empty or inverted intervals start:stop, i.e., where
+// stop <= start. By default, such intervals are treated as the interval
+// start:start+1.
+func (b Scheme[T]) Strict() Scheme[T] {
+	b.strict = true
+	return b
+}
+
+// Clamped returns a copy of the binning scheme that truncates intervals
+// partially outside the range of supported positions to that range, instead
+// of returning an OutOfRangeError. Intervals completely outside the range
+// still result in an OutOfRangeError.
+func (b Scheme[T]) Clamped() Scheme[T] {
+	b.clamp = true
+	return b
 }
 
 // The closure created by ranges for the interval start:stop returns the first
 // and last bin overlapping the interval for each level, starting with the
 // smallest bins.
 // Algorithm by Jim Kent: http://genomewiki.ucsc.edu/index.php/Bin_indexing_system
-func (b Binning) ranges(start, stop int) (func() (int, int, bool), error) {
-	if start < 0 || stop > b.MaxPosition+1 {
-		return nil, errors.New(fmt.Sprintf("interval out of range: %d-%d (maximum position is %d)", start, stop, b.MaxPosition))
+func (b Scheme[T]) ranges(start, stop T) (func() (T, T, bool), error) {
+	start, stop, err := b.checkRange(start, stop)
+	if err != nil {
+		return nil, err
 	}
-	if stop <= start {
-		stop = start + 1
+	last := start
+	if stop > start {
+		last = stop - 1
 	}
 
-	startBin := start >> b.shiftFirst
-	stopBin := (stop - 1) >> b.shiftFirst
-	maxLevel := len(b.binOffsets) - 1
 	level := 0
 
-	return func() (int, int, bool) {
-		if level > maxLevel {
+	return func() (T, T, bool) {
+		if level >= len(b.binOffsets) {
 			return 0, 0, false
 		}
-		if level > 0 {
-			startBin >>= b.shiftNext
-			stopBin >>= b.shiftNext
-		}
+		offset, shift := b.binOffsets[level], b.shifts[level]
 		level++
-		return b.binOffsets[level-1] + startBin, b.binOffsets[level-1] + stopBin, true
+		return offset + start>>shift, offset + last>>shift, true
 	}, nil
 }
 
 // Assign returns the smallest bin fitting the interval start:stop.
-func (b Binning) Assign(start, stop int) (int, error) {
+func (b Scheme[T]) Assign(start, stop T) (T, error) {
 	nextRange, err := b.ranges(start, stop)
 	if err != nil {
 		return 0, err
@@ -79,37 +149,123 @@ func (b Binning) Assign(start, stop int) (int, error) {
 		}
 	}
 
-	panic("unexpected loop fall-through")
+	return 0, &InvalidSchemeError{fmt.Sprintf("no level has a single bin for interval %d-%d", start, stop)}
 }
 
+// An Order specifies the order of bins returned by OverlappingOrdered.
+type Order int
+
+const (
+	// FineToCoarse orders bins by level, starting with the smallest bins.
+	// Within a level, bins are in ascending order.
+	FineToCoarse Order = iota
+
+	// CoarseToFine orders bins by level, starting with the largest bins.
+	// Within a level, bins are in ascending order.
+	CoarseToFine
+
+	// Ascending orders bins by bin number.
+	Ascending
+)
+
 // Overlapping returns bins for all intervals overlapping the interval
 // start:stop by at least one position.
-func (b Binning) Overlapping(start, stop int) ([]int, error) {
+func (b Scheme[T]) Overlapping(start, stop T) ([]T, error) {
+	return b.OverlappingOrdered(start, stop, FineToCoarse)
+}
+
+// OverlappingOrdered returns bins for all intervals overlapping the interval
+// start:stop by at least one position, in the given order.
+func (b Scheme[T]) OverlappingOrdered(start, stop T, order Order) ([]T, error) {
 	nextRange, err := b.ranges(start, stop)
 	if err != nil {
 		return nil, err
 	}
 
-	bins := []int{}
+	levels := [][]T{}
 
 	for {
 		startBin, stopBin, ok := nextRange()
 		if !ok {
 			break
 		}
-		tmp := make([]int, stopBin-startBin+1)
+		tmp := make([]T, stopBin-startBin+1)
 		for bin := startBin; bin <= stopBin; bin++ {
 			tmp[bin-startBin] = bin
 		}
-		bins = append(bins, tmp...)
+		levels = append(levels, tmp)
+	}
+
+	if order == CoarseToFine {
+		for i, j := 0, len(levels)-1; i < j; i, j = i+1, j-1 {
+			levels[i], levels[j] = levels[j], levels[i]
+		}
+	}
+
+	bins := []T{}
+	for _, level := range levels {
+		bins = append(bins, level...)
+	}
+
+	if order == Ascending {
+		slices.Sort(bins)
 	}
 
 	return bins, nil
 }
 
+// A BinRange is a range of consecutive bins on one level, from First up to
+// and including Last.
+type BinRange[T Integer] struct {
+	First, Last T
+}
+
+// OverlappingBudget returns bins for all intervals overlapping the interval
+// start:stop by at least one position, like Overlapping, but returns at most
+// maxBins individual bins. Levels are considered starting with the largest
+// bins. The first level that would exceed the budget and all levels with
+// smaller bins are returned as bin ranges instead. Both bins and bin ranges
+// start with the smallest bins.
+func (b Scheme[T]) OverlappingBudget(start, stop T, maxBins int) ([]T, []BinRange[T], error) {
+	nextRange, err := b.ranges(start, stop)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	levels := []BinRange[T]{}
+
+	for {
+		startBin, stopBin, ok := nextRange()
+		if !ok {
+			break
+		}
+		levels = append(levels, BinRange[T]{startBin, stopBin})
+	}
+
+	// Find the smallest level such that it and all larger levels fit.
+	split := len(levels)
+	count := 0
+	for split > 0 {
+		count += int(levels[split-1].Last - levels[split-1].First + 1)
+		if count > maxBins {
+			break
+		}
+		split--
+	}
+
+	bins := []T{}
+	for _, level := range levels[split:] {
+		for bin := level.First; bin <= level.Last; bin++ {
+			bins = append(bins, bin)
+		}
+	}
+
+	return bins, levels[:split], nil
+}
+
 // Containing returns bins for all intervals completely containing the
 // interval start:stop.
-func (b Binning) Containing(start, stop int) ([]int, error) {
+func (b Scheme[T]) Containing(start, stop T) ([]T, error) {
 	maxBin, err := b.Assign(start, stop)
 	if err != nil {
 		return nil, err
@@ -132,7 +288,7 @@ func (b Binning) Containing(start, stop int) ([]int, error) {
 
 // Contained returns bins for all intervals completely contained by the
 // interval start:stop.
-func (b Binning) Contained(start, stop int) ([]int, error) {
+func (b Scheme[T]) Contained(start, stop T) ([]T, error) {
 	minBin, err := b.Assign(start, stop)
 	if err != nil {
 		return nil, err
@@ -153,40 +309,420 @@ func (b Binning) Contained(start, stop int) ([]int, error) {
 	return bins, nil
 }
 
+// Boundaries returns the positions strictly inside the interval start:stop at
+// which a bin on the smallest level starts. Splitting the interval at these
+// positions yields parts that each fit in a single bin on the smallest level.
+func (b Scheme[T]) Boundaries(start, stop T) ([]T, error) {
+	start, stop, err := b.checkRange(start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	boundaries := []T{}
+
+	// Stop before the next boundary would be beyond MaxPosition, where it
+	// may not be representable in T.
+	size := T(1) << b.shifts[0]
+	for pos := start >> b.shifts[0] << b.shifts[0]; size > 0 && size <= b.MaxPosition && pos <= b.MaxPosition-size; {
+		pos += size
+		if pos >= stop {
+			break
+		}
+		boundaries = append(boundaries, pos)
+	}
+
+	return boundaries, nil
+}
+
 // Covered returns the interval covered by bin.
-func (b Binning) Covered(bin int) (int, int, error) {
-	if bin < 0 || bin > b.MaxBin {
-		return 0, 0, errors.New(fmt.Sprintf("not a valid bin number: %d (must be >= 0 and <= %d)", bin, b.MaxBin))
+func (b Scheme[T]) Covered(bin T) (T, T, error) {
+	if err := b.check(); err != nil {
+		return 0, 0, err
+	}
+	if bin < b.MinBin || bin > b.MaxBin {
+		return 0, 0, &InvalidBinError{Bin: int64(bin), MinBin: int64(b.MinBin), MaxBin: int64(b.MaxBin)}
 	}
 
-	shift := b.shiftFirst
-	for _, offset := range b.binOffsets {
+	for level, offset := range b.binOffsets {
 		if offset <= bin {
-			return (bin - offset) << shift, (bin + 1 - offset) << shift, nil
+			shift := b.shifts[level]
+			start, stop := (bin-offset)<<shift, (bin+1-offset)<<shift
+			if stop <= start {
+				// The extended scheme on 32-bit platforms covers positions
+				// up to the largest int, so stop may not be representable.
+				return 0, 0, &InvalidSchemeError{fmt.Sprintf("positions overflow for bin %d", bin)}
+			}
+			return start, stop, nil
 		}
-		shift += b.shiftNext
 	}
 
-	panic("unexpected loop fall-through")
+	return 0, 0, &InvalidSchemeError{fmt.Sprintf("no level has bin %d", bin)}
+}
+
+// equalSchemes reports whether a and b have the same parameters.
+func equalSchemes[T Integer](a, b Scheme[T]) bool {
+	return a.MaxPosition == b.MaxPosition && slices.Equal(a.binOffsets, b.binOffsets) && slices.Equal(a.shifts, b.shifts)
+}
+
+// level returns the level of bin, or -1 if no level has bin.
+func (b Scheme[T]) level(bin T) int {
+	for level, offset := range b.binOffsets {
+		if offset <= bin {
+			return level
+		}
+	}
+	return -1
+}
+
+// Validate returns an InvalidSchemeError if the binning scheme is not
+// consistent, e.g., because it was created by NewBinning with invalid
+// parameters or its MinBin or MaxBin fields were modified.
+func (b Scheme[T]) Validate() error {
+	if err := checkScheme(b.MaxPosition, b.binOffsets, b.shifts); err != nil {
+		return err
+	}
+	if minBin := b.binOffsets[len(b.binOffsets)-1]; b.MinBin != minBin {
+		return &InvalidSchemeError{fmt.Sprintf("minimum bin %d does not match bin offsets (expected %d)", b.MinBin, minBin)}
+	}
+	if maxBin := b.binOffsets[0] + b.MaxPosition>>b.shifts[0]; b.MaxBin != maxBin {
+		return &InvalidSchemeError{fmt.Sprintf("maximum bin %d does not match maximum position and bin offsets (expected %d)", b.MaxBin, maxBin)}
+	}
+	return nil
+}
+
+// ValidateBin returns an error if bin is not a plausible bin for the interval
+// start:stop, i.e., if bin does not exist or does not cover the interval.
+// The bin is not required to be the smallest bin fitting the interval, as
+// returned by Assign, but it must be on the same or a larger level. If the
+// bin does not cover the interval, a BinMismatchError is returned. If the
+// binning scheme clamps, the truncated interval is validated.
+func (b Scheme[T]) ValidateBin(bin, start, stop T) error {
+	start, stop, err := b.checkRange(start, stop)
+	if err != nil {
+		return err
+	}
+	assigned, err := b.Assign(start, stop)
+	if err != nil {
+		return err
+	}
+	binStart, binStop, err := b.Covered(bin)
+	if err != nil {
+		return err
+	}
+	if stop <= start {
+		stop = start + 1
+	}
+	if start < binStart || stop > binStop {
+		return &BinMismatchError{Bin: int64(bin), Assigned: int64(assigned), Start: int64(start), Stop: int64(stop)}
+	}
+	return nil
 }
 
-// NewBinning creates a new binning scheme with maxPosition the maximum
+// NewScheme creates a new binning scheme with maxPosition the maximum
 // position that can be binned, binOffsets the first bin number per level,
 // shiftFirst how much to shift to get to the smallest bin, and shiftNext how
-// much to shift to get to the next larger bin.
-func NewBinning(maxPosition int, binOffsets []int, shiftFirst, shiftNext uint) Binning {
-	return Binning{
+// much to shift to get to the next larger bin. If the bin numbers do not fit
+// in T, methods of the scheme return an InvalidSchemeError. Use
+// NewValidatedScheme to check all parameters when creating the scheme.
+func NewScheme[T Integer](maxPosition T, binOffsets []T, shiftFirst, shiftNext uint) Scheme[T] {
+	return newScheme(maxPosition, binOffsets, uniformShifts(len(binOffsets), shiftFirst, shiftNext))
+}
+
+// newScheme creates a new binning scheme with maxPosition the maximum
+// position that can be binned, binOffsets the first bin number per level, and
+// shifts how much to shift to get to the bin per level.
+func newScheme[T Integer](maxPosition T, binOffsets []T, shifts []uint) Scheme[T] {
+	if len(binOffsets) == 0 || len(shifts) != len(binOffsets) {
+		return Scheme[T]{MaxPosition: maxPosition}
+	}
+	var overflow error
+	for level, offset := range binOffsets {
+		if overflow = checkBinOverflow(maxPosition, level, offset, shifts[level]); overflow != nil {
+			break
+		}
+	}
+	return Scheme[T]{
 		MaxPosition: maxPosition,
-		MaxBin:      binOffsets[0] + (maxPosition >> shiftFirst),
+		MinBin:      binOffsets[len(binOffsets)-1],
+		MaxBin:      binOffsets[0] + (maxPosition >> shifts[0]),
 		binOffsets:  binOffsets,
-		shiftFirst:  shiftFirst,
-		shiftNext:   shiftNext,
+		shifts:      shifts,
+		overflow:    overflow,
 	}
 }
 
-// StandardBinning returns the standard binning scheme used by the UCSC Genome
+// uniformShifts returns how much to shift to get to the bin per level for a
+// scheme with the given number of levels, shiftFirst how much to shift to get
+// to the smallest bin, and shiftNext how much to shift to get to the next
+// larger bin.
+func uniformShifts(levels int, shiftFirst, shiftNext uint) []uint {
+	shifts := make([]uint, levels)
+	for level := range shifts {
+		shifts[level] = shiftFirst + uint(level)*shiftNext
+	}
+	return shifts
+}
+
+// NewValidatedScheme creates a new binning scheme like NewScheme, but returns
+// an error if the parameters do not describe a consistent binning scheme.
+func NewValidatedScheme[T Integer](maxPosition T, binOffsets []T, shiftFirst, shiftNext uint) (Scheme[T], error) {
+	if err := checkScheme(maxPosition, binOffsets, uniformShifts(len(binOffsets), shiftFirst, shiftNext)); err != nil {
+		return Scheme[T]{}, err
+	}
+	return NewScheme(maxPosition, binOffsets, shiftFirst, shiftNext), nil
+}
+
+// NewValidatedBinning creates a new binning scheme on positions of type int
+// like NewBinning, but returns an error if the parameters do not describe a
+// consistent binning scheme.
+func NewValidatedBinning(maxPosition int, binOffsets []int, shiftFirst, shiftNext uint) (Binning, error) {
+	return NewValidatedScheme(maxPosition, binOffsets, shiftFirst, shiftNext)
+}
+
+// checkScheme returns an error if the parameters do not describe a
+// consistent binning scheme. See newScheme for a description of the
+// parameters.
+func checkScheme[T Integer](maxPosition T, binOffsets []T, shifts []uint) error {
+	if maxPosition < 0 {
+		return &InvalidSchemeError{fmt.Sprintf("invalid maximum position: %d", maxPosition)}
+	}
+	if len(binOffsets) == 0 {
+		return &InvalidSchemeError{"no bin offsets"}
+	}
+	if len(shifts) != len(binOffsets) {
+		return &InvalidSchemeError{fmt.Sprintf("number of shifts (%d) does not match number of bin offsets (%d)", len(shifts), len(binOffsets))}
+	}
+
+	for level, offset := range binOffsets {
+		shift := shifts[level]
+		if level > 0 && shift <= shifts[level-1] {
+			return &InvalidSchemeError{fmt.Sprintf("shifts for levels %d and %d are inconsistent: %d must be > %d", level, level-1, shift, shifts[level-1])}
+		}
+		if offset < 0 {
+			return &InvalidSchemeError{fmt.Sprintf("invalid bin offset for level %d: %d", level, offset)}
+		}
+		if err := checkBinOverflow(maxPosition, level, offset, shift); err != nil {
+			return err
+		}
+		bins := maxPosition>>shift + 1
+		if covered := bins << shift; covered>>shift != bins || covered <= 0 {
+			return &InvalidSchemeError{fmt.Sprintf("positions overflow for level %d: %d bins of 2^%d positions", level, bins, shift)}
+		}
+		if level > 0 && offset+bins-1 >= binOffsets[level-1] {
+			return &InvalidSchemeError{fmt.Sprintf("bin offsets for levels %d and %d are inconsistent: %d bins from offset %d overlap offset %d",
+				level, level-1, bins, offset, binOffsets[level-1])}
+		}
+	}
+
+	if shift := shifts[len(shifts)-1]; maxPosition>>shift != 0 {
+		return &InvalidSchemeError{fmt.Sprintf("largest level has more than one bin: maximum position %d shifted by %d is %d",
+			maxPosition, shift, maxPosition>>shift)}
+	}
+
+	return nil
+}
+
+// checkBinOverflow returns an error if the bin numbers on a level with the
+// given bin offset and shift do not fit in T.
+func checkBinOverflow[T Integer](maxPosition T, level int, offset T, shift uint) error {
+	if bins := maxPosition>>shift + 1; offset+bins-1 < offset {
+		return &InvalidSchemeError{fmt.Sprintf("bin numbers overflow for level %d: %d bins from offset %d", level, bins, offset)}
+	}
+	return nil
+}
+
+// NewBinning creates a new binning scheme on positions of type int. See
+// NewScheme for a description of the parameters.
+func NewBinning(maxPosition int, binOffsets []int, shiftFirst, shiftNext uint) Binning {
+	return NewScheme(maxPosition, binOffsets, shiftFirst, shiftNext)
+}
+
+// NewBinning64 creates a new binning scheme on positions of type int64. See
+// NewScheme for a description of the parameters.
+func NewBinning64(maxPosition int64, binOffsets []int64, shiftFirst, shiftNext uint) Binning64 {
+	return NewScheme(maxPosition, binOffsets, shiftFirst, shiftNext)
+}
+
+// NewSchemeFromShifts creates a new binning scheme with the given number of
+// levels, shiftFirst how much to shift to get to the smallest bin, shiftNext
+// how much to shift to get to the next larger bin, and maxPosition the
+// maximum position that can be binned. The first bin number per level is
+// derived such that the largest level has bin number 0 and bin numbers on
+// each level follow those on the level above it.
+func NewSchemeFromShifts[T Integer](levels int, shiftFirst, shiftNext uint, maxPosition T) (Scheme[T], error) {
+	if levels < 1 {
+		return Scheme[T]{}, &InvalidSchemeError{fmt.Sprintf("invalid number of levels: %d", levels)}
+	}
+	return NewValidatedScheme(maxPosition, computeOffsets[T](uniformShifts(levels, shiftFirst, shiftNext)), shiftFirst, shiftNext)
+}
+
+// NewBinningFromShifts creates a new binning scheme on positions of type int
+// with derived bin offsets. See NewSchemeFromShifts for a description of the
+// parameters.
+func NewBinningFromShifts(levels int, shiftFirst, shiftNext uint, maxPosition int) (Binning, error) {
+	return NewSchemeFromShifts(levels, shiftFirst, shiftNext, maxPosition)
+}
+
+// NewSchemeFromLevels creates a new binning scheme with maxPosition the
+// maximum position that can be binned, binOffsets the first bin number per
+// level, and shifts how much to shift to get to the bin per level, starting
+// with the smallest bins. This allows for a different number of bins per
+// level. If binOffsets is nil, it is derived as with NewSchemeFromShifts.
+func NewSchemeFromLevels[T Integer](maxPosition T, binOffsets []T, shifts []uint) (Scheme[T], error) {
+	if binOffsets == nil && len(shifts) > 0 {
+		binOffsets = computeOffsets[T](shifts)
+	}
+	if err := checkScheme(maxPosition, binOffsets, shifts); err != nil {
+		return Scheme[T]{}, err
+	}
+	return newScheme(maxPosition, binOffsets, shifts), nil
+}
+
+// NewBinningFromLevels creates a new binning scheme on positions of type int
+// with a different number of bins per level. See NewSchemeFromLevels for a
+// description of the parameters.
+func NewBinningFromLevels(maxPosition int, binOffsets []int, shifts []uint) (Binning, error) {
+	return NewSchemeFromLevels(maxPosition, binOffsets, shifts)
+}
+
+// computeOffsets returns the first bin number per level for a scheme with
+// shifts how much to shift to get to the bin per level, such that the
+// largest level has a single bin.
+func computeOffsets[T Integer](shifts []uint) []T {
+	binOffsets := make([]T, len(shifts))
+	for level := len(shifts) - 2; level >= 0; level-- {
+		binOffsets[level] = binOffsets[level+1] + T(1)<<(shifts[len(shifts)-1]-shifts[level+1])
+	}
+	return binOffsets
+}
+
+// NewCSIScheme creates a new binning scheme parameterized the way CSI indexes
+// are, with minShift how much to shift to get to the smallest bin and depth
+// the number of levels below the level with a single bin. Each level has 8
+// times as many bins as the level above it. The scheme covers positions >= 0
+// and <= 2^(minShift+3*depth)-1.
+// If minShift or depth is negative, or the positions do not fit in T, the
+// scheme has no levels and its methods return an InvalidSchemeError. Use
+// NewValidatedCSIScheme to get the error when creating the scheme.
+// http://samtools.github.io/hts-specs/CSIv1.pdf
+func NewCSIScheme[T Integer](minShift, depth int) Scheme[T] {
+	if checkCSI[T](minShift, depth) != nil {
+		return Scheme[T]{}
+	}
+	shifts := uniformShifts(depth+1, uint(minShift), 3)
+	return newScheme(T(1)<<uint(minShift+3*depth)-1, computeOffsets[T](shifts), shifts)
+}
+
+// NewValidatedCSIScheme creates a new binning scheme like NewCSIScheme, but
+// returns an error if minShift and depth do not describe a consistent
+// binning scheme on positions of type T.
+func NewValidatedCSIScheme[T Integer](minShift, depth int) (Scheme[T], error) {
+	if err := checkCSI[T](minShift, depth); err != nil {
+		return Scheme[T]{}, err
+	}
+	b := NewCSIScheme[T](minShift, depth)
+	if err := b.Validate(); err != nil {
+		return Scheme[T]{}, err
+	}
+	return b, nil
+}
+
+// checkCSI returns an error if minShift or depth is negative, or if the
+// positions of a CSI binning scheme with these parameters do not fit in T.
+func checkCSI[T Integer](minShift, depth int) error {
+	if minShift < 0 || depth < 0 {
+		return &InvalidSchemeError{fmt.Sprintf("invalid CSI parameters: min_shift %d, depth %d", minShift, depth)}
+	}
+	bits := 0
+	for v := T(1); v > 0; v <<= 1 {
+		bits++
+	}
+	if minShift >= bits || depth >= bits || minShift+3*depth >= bits {
+		return &InvalidSchemeError{fmt.Sprintf("positions overflow for CSI parameters: min_shift %d, depth %d cover 2^%d positions", minShift, depth, minShift+3*depth)}
+	}
+	return nil
+}
+
+// NewCSIBinning creates a new binning scheme on positions of type int
+// parameterized the way CSI indexes are. See NewCSIScheme for a description
+// of the parameters.
+func NewCSIBinning(minShift, depth int) Binning {
+	return NewCSIScheme[int](minShift, depth)
+}
+
+// StandardScheme returns the standard binning scheme used by the UCSC Genome
 // Browser covering positions >= 0 and <= 2^29-1.
 // http://genomewiki.ucsc.edu/index.php/Bin_indexing_system
+func StandardScheme[T Integer]() Scheme[T] {
+	return NewScheme[T](1<<29-1, []T{512 + 64 + 8 + 1, 64 + 8 + 1, 8 + 1, 1, 0}, 17, 3)
+}
+
+// StandardBinning returns the standard binning scheme used by the UCSC Genome
+// Browser on positions of type int.
 func StandardBinning() Binning {
-	return NewBinning(1<<29-1, []int{512 + 64 + 8 + 1, 64 + 8 + 1, 8 + 1, 1, 0}, 17, 3)
+	return StandardScheme[int]()
+}
+
+// ExtendedScheme returns the extended binning scheme used by the UCSC Genome
+// Browser covering positions >= 0 and <= 2^31-1. It has one more level than
+// the standard scheme and its bin numbers start after the largest bin number
+// of the standard scheme (4681), so both schemes can be used in one table.
+// http://genomewiki.ucsc.edu/index.php/Bin_indexing_system
+func ExtendedScheme[T Integer]() Scheme[T] {
+	return NewScheme[T](1<<31-1, []T{
+		4681 + 4096 + 512 + 64 + 8 + 1,
+		4681 + 512 + 64 + 8 + 1,
+		4681 + 64 + 8 + 1,
+		4681 + 8 + 1,
+		4681 + 1,
+		4681,
+	}, 17, 3)
+}
+
+// ExtendedBinning returns the extended binning scheme used by the UCSC Genome
+// Browser on positions of type int. On platforms where int is 32 bits, the
+// positions covered by the largest bins cannot be represented and Validate
+// returns an error for this scheme. Use ExtendedScheme[int64] instead.
+func ExtendedBinning() Binning {
+	return ExtendedScheme[int]()
+}
+
+// TabixScheme returns the binning scheme used by BAI and tabix indexes
+// covering positions >= 0 and <= 2^29-1. It has six levels with bins of 2^14
+// positions on the smallest level, for a total of 37450 bins.
+// http://samtools.github.io/hts-specs/SAMv1.pdf
+func TabixScheme[T Integer]() Scheme[T] {
+	return NewCSIScheme[T](14, 5)
+}
+
+// TabixBinning returns the binning scheme used by BAI and tabix indexes on
+// positions of type int.
+func TabixBinning() Binning {
+	return TabixScheme[int]()
+}
+
+// FineScheme returns a binning scheme for dense point-like data covering
+// positions >= 0 and <= 2^30-1. It has seven levels with bins of 2^12
+// positions on the smallest level.
+func FineScheme[T Integer]() Scheme[T] {
+	return NewCSIScheme[T](12, 6)
+}
+
+// FineBinning returns a binning scheme for dense point-like data on positions
+// of type int.
+func FineBinning() Binning {
+	return FineScheme[int]()
+}
+
+// CoarseScheme returns a binning scheme for long features covering positions
+// >= 0 and <= 2^29-1. It has four levels with bins of 2^20 positions on the
+// smallest level.
+func CoarseScheme[T Integer]() Scheme[T] {
+	return NewCSIScheme[T](20, 3)
+}
+
+// CoarseBinning returns a binning scheme for long features on positions of
+// type int.
+func CoarseBinning() Binning {
+	return CoarseScheme[int]()
 }
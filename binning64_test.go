@@ -0,0 +1,73 @@
+package binning
+
+import "testing"
+
+// Some example intervals with pre-calculated bin numbers in a 64-bit binning
+// scheme with nine levels.
+var interval64Bins = []struct{ start, stop, bin int64 }{
+	{0, 1, 2396745},
+	{0, 1 << 17, 2396745},
+	{0, 1<<17 + 1, 299593},
+	{1 << 32, 1<<32 + 1, 2396745 + 1<<15},
+	{1 << 39, 1<<39 + 1, 2396745 + 1<<22},
+	{1<<40 - 1, 1 << 40, 2396745 + 1<<23 - 1},
+	{0, 1 << 40, 0},
+}
+
+func largeBinning64() Binning64 {
+	return NewBinning64(1<<40-1, []int64{2396745, 299593, 37449, 4681, 585, 73, 9, 1, 0}, 17, 3)
+}
+
+func TestAssign64(t *testing.T) {
+	b := largeBinning64()
+	for _, v := range interval64Bins {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+}
+
+func TestAssign64Invalid(t *testing.T) {
+	b := largeBinning64()
+	for _, v := range []struct{ start, stop int64 }{{-1, 0}, {0, 1<<40 + 1}} {
+		if bin, error := b.Assign(v.start, v.stop); error == nil {
+			t.Errorf("Assign(%d, %d) = %d, expected error", v.start, v.stop, bin)
+		}
+	}
+}
+
+func TestAssignCovered64(t *testing.T) {
+	b := largeBinning64()
+	for _, v := range interval64Bins {
+		if start, stop, error := b.Covered(v.bin); error != nil {
+			t.Errorf("Covered(%d) returned error: %v", v.bin, error)
+		} else if start > v.start || v.stop > stop {
+			t.Errorf("Covered(%d) = (%d, %d), expected (<=%d, >=%d)", v.bin, start, stop, v.start, v.stop)
+		}
+	}
+}
+
+func TestStandard64(t *testing.T) {
+	b := StandardBinning()
+	b64 := NewBinning64(1<<29-1, []int64{512 + 64 + 8 + 1, 64 + 8 + 1, 8 + 1, 1, 0}, 17, 3)
+	for _, v := range intervalOverlappingBins {
+		bins, _ := b.Overlapping(v.start, v.stop)
+		bins64, error := b64.Overlapping(int64(v.start), int64(v.stop))
+		if error != nil {
+			t.Errorf("Overlapping(%d, %d) returned error: %v", v.start, v.stop, error)
+			continue
+		}
+		if len(bins64) != len(bins) {
+			t.Errorf("len(Overlapping(%d, %d)) = %v, expected %v", v.start, v.stop, len(bins64), len(bins))
+			continue
+		}
+		for i := 0; i < len(bins); i++ {
+			if bins64[i] != int64(bins[i]) {
+				t.Errorf("Overlapping(%d, %d)[%d] = %v, expected %v", v.start, v.stop, i, bins64[i], bins[i])
+				break
+			}
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package binning
 
-import "testing"
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
 
 // Some example intervals with pre-calculated bin numbers.
 // http://genomewiki.ucsc.edu/index.php/Bin_indexing_system
@@ -34,6 +39,32 @@ var intervalBins = []struct{ start, stop, bin int }{
 	{1200000, 2000000, 74},
 }
 
+// Some example intervals with pre-calculated bin numbers in the extended
+// binning scheme.
+var intervalExtendedBins = []struct{ start, stop, bin int64 }{
+	{0, 1, 9362},
+	{0, 1 << 17, 9362},
+	{0, 1<<17 + 1, 4681 + 585},
+	{1 << 29, 1<<29 + 1, 9362 + 4096},
+	{1<<31 - 1, 1 << 31, 9362 + 16383},
+	{0, 1 << 29, 4681 + 1},
+	{0, 1 << 31, 4681},
+	{74012, 173034, 4681 + 585},
+}
+
+// Some example intervals with pre-calculated bin numbers in the BAI and tabix
+// binning scheme, computed using reg2bin from the SAM specification.
+var intervalTabixBins = []struct{ start, stop, bin int }{
+	{0, 1, 4681},
+	{1<<29 - 1, 1 << 29, 37448},
+	{0, 1 << 29, 0},
+	{0, 1 << 14, 4681},
+	{0, 1<<14 + 1, 585},
+	{74012, 173034, 73},
+	{1000000, 1000100, 4742},
+	{1200000, 2000000, 74},
+}
+
 var invalidIntervals = []struct{ start, stop int }{
 	{-23442, -334},
 	{-23442, 334},
@@ -42,6 +73,7 @@ var invalidIntervals = []struct{ start, stop int }{
 	{-1, 0},
 	{5656, 1<<29 + 1},
 	{-34234, 1<<29 + 3431},
+	{1 << 29, 1 << 29},
 }
 
 var intervalRanges = []struct {
@@ -74,6 +106,34 @@ var intervalOverlappingBins = []struct {
 	{300000000, 301000015, append(rng(2873, 2882), 359, 360, 44, 5, 0)},
 }
 
+var intervalOverlappingOrderedBins = []struct {
+	start, stop int
+	order       Order
+	bins        []int
+}{
+	{0, 1, FineToCoarse, []int{585, 73, 9, 1, 0}},
+	{0, 1, CoarseToFine, []int{0, 1, 9, 73, 585}},
+	{0, 1, Ascending, []int{0, 1, 9, 73, 585}},
+	{0, 1<<17 + 1, FineToCoarse, []int{585, 586, 73, 9, 1, 0}},
+	{0, 1<<17 + 1, CoarseToFine, []int{0, 1, 9, 73, 585, 586}},
+	{0, 1<<17 + 1, Ascending, []int{0, 1, 9, 73, 585, 586}},
+	{300000000, 301000015, CoarseToFine, append([]int{0, 5, 44, 359, 360}, rng(2873, 2882)...)},
+	{300000000, 301000015, Ascending, append([]int{0, 5, 44, 359, 360}, rng(2873, 2882)...)},
+}
+
+var intervalOverlappingBudgetBins = []struct {
+	start, stop, maxBins int
+	bins                 []int
+	ranges               []BinRange[int]
+}{
+	{0, 1, 5, []int{585, 73, 9, 1, 0}, []BinRange[int]{}},
+	{0, 1, 4, []int{73, 9, 1, 0}, []BinRange[int]{{585, 585}}},
+	{0, 1, 0, []int{}, []BinRange[int]{{585, 585}, {73, 73}, {9, 9}, {1, 1}, {0, 0}}},
+	{0, 1 << 29, 100, conc(rng(9, 73), rng(1, 9), []int{0}), []BinRange[int]{{585, 4680}, {73, 584}}},
+	{300000000, 301000015, 10, []int{359, 360, 44, 5, 0}, []BinRange[int]{{2873, 2881}}},
+	{300000000, 301000015, 14, append(rng(2873, 2882), 359, 360, 44, 5, 0), []BinRange[int]{}},
+}
+
 var intervalContainingBins = []struct {
 	start, stop int
 	bins        []int
@@ -113,6 +173,248 @@ func TestAssign(t *testing.T) {
 	}
 }
 
+func TestAssignUint32(t *testing.T) {
+	b := StandardScheme[uint32]()
+	for _, v := range intervalBins {
+		if bin, error := b.Assign(uint32(v.start), uint32(v.stop)); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != uint32(v.bin) {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+}
+
+func TestAssignExtended(t *testing.T) {
+	b := ExtendedScheme[int64]()
+	for _, v := range intervalExtendedBins {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+}
+
+func TestCoveredExtended(t *testing.T) {
+	b := ExtendedScheme[int64]()
+	for _, bin := range []int64{-1, 0, 4680, 25746} {
+		if start, stop, error := b.Covered(bin); error == nil {
+			t.Errorf("Covered(%d) = (%d, %d), expected error", bin, start, stop)
+		}
+	}
+	for _, v := range intervalExtendedBins {
+		if start, stop, error := b.Covered(v.bin); error != nil {
+			t.Errorf("Covered(%d) returned error: %v", v.bin, error)
+		} else if start > v.start || v.stop > stop {
+			t.Errorf("Covered(%d) = (%d, %d), expected (<=%d, >=%d)", v.bin, start, stop, v.start, v.stop)
+		}
+	}
+}
+
+func TestNewCSIBinning(t *testing.T) {
+	b := NewCSIBinning(17, 4)
+	standard := StandardBinning()
+	if b.MaxPosition != standard.MaxPosition || b.MinBin != standard.MinBin || b.MaxBin != standard.MaxBin {
+		t.Errorf("NewCSIBinning(17, 4) = %v, expected %v", b, standard)
+	}
+	for _, v := range intervalBins {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+
+	b = NewCSIBinning(14, 5)
+	if b.MaxPosition != 1<<29-1 || b.MinBin != 0 || b.MaxBin != 37448 {
+		t.Errorf("NewCSIBinning(14, 5) = %v, expected MaxPosition %d, MinBin %d, MaxBin %d", b, 1<<29-1, 0, 37448)
+	}
+	if bin, error := b.Assign(0, 1<<14+1); error != nil {
+		t.Errorf("Assign(%d, %d) returned error: %v", 0, 1<<14+1, error)
+	} else if bin != 585 {
+		t.Errorf("Assign(%d, %d) = %d, expected %d", 0, 1<<14+1, bin, 585)
+	}
+}
+
+func TestAssignTabix(t *testing.T) {
+	b := TabixBinning()
+	for _, v := range intervalTabixBins {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+}
+
+var invalidSchemes = []struct {
+	maxPosition           int
+	binOffsets            []int
+	shiftFirst, shiftNext uint
+}{
+	{-1, []int{585, 73, 9, 1, 0}, 17, 3},
+	{1<<29 - 1, []int{}, 17, 3},
+	{1<<29 - 1, []int{585, 73, 9, 1, 0}, 17, 0},
+	{1<<29 - 1, []int{585, 73, 9, 1}, 17, 3},
+	{1<<29 - 1, []int{585, 73, 9, 0, 1}, 17, 3},
+	{1<<29 - 1, []int{584, 73, 9, 1, 0}, 17, 3},
+	{1<<29 - 1, []int{585, 73, 9, 1, -1}, 17, 3},
+	{1<<30 - 1, []int{585, 73, 9, 1, 0}, 17, 3},
+	{1<<29 - 1, []int{585, 73, 9, 1, 0}, 16, 3},
+	{1<<30 - 1, []int{math.MaxInt - 1, 0}, 0, 30},
+}
+
+func TestNewValidatedCSIScheme(t *testing.T) {
+	if b, error := NewValidatedCSIScheme[int](14, 5); error != nil {
+		t.Errorf("NewValidatedCSIScheme(%d, %d) returned error: %v", 14, 5, error)
+	} else if b.MaxBin != 37448 {
+		t.Errorf("NewValidatedCSIScheme(%d, %d) = %v, expected MaxBin %d", 14, 5, b, 37448)
+	}
+	for _, v := range []struct {
+		minShift, depth int
+	}{
+		{14, -1},
+		{-1, 5},
+		{14, 6},
+		{30, 1 << 30},
+	} {
+		if _, error := NewValidatedCSIScheme[int32](v.minShift, v.depth); error == nil {
+			t.Errorf("NewValidatedCSIScheme[int32](%d, %d) returned no error, expected error", v.minShift, v.depth)
+		}
+		b := NewCSIScheme[int32](v.minShift, v.depth)
+		if _, error := b.Assign(0, 1); error == nil {
+			t.Errorf("Assign(%d, %d) on NewCSIScheme[int32](%d, %d) returned no error, expected error", 0, 1, v.minShift, v.depth)
+		}
+		if _, error := b.Overlapping(0, 1); !errors.Is(error, ErrInvalidScheme) {
+			t.Errorf("Overlapping(%d, %d) on NewCSIScheme[int32](%d, %d) returned error %v, expected ErrInvalidScheme", 0, 1, v.minShift, v.depth, error)
+		}
+	}
+}
+
+func TestNewValidatedBinning(t *testing.T) {
+	if _, error := NewValidatedBinning(1<<29-1, []int{585, 73, 9, 1, 0}, 17, 3); error != nil {
+		t.Errorf("NewValidatedBinning(%d, %v, %d, %d) returned error: %v", 1<<29-1, []int{585, 73, 9, 1, 0}, 17, 3, error)
+	}
+	if _, error := NewValidatedScheme[int64](1<<31-1, []int64{9362, 5266, 4754, 4690, 4682, 4681}, 17, 3); error != nil {
+		t.Errorf("NewValidatedScheme(%d, %v, %d, %d) returned error: %v", 1<<31-1, []int64{9362, 5266, 4754, 4690, 4682, 4681}, 17, 3, error)
+	}
+	for _, v := range invalidSchemes {
+		if _, error := NewValidatedBinning(v.maxPosition, v.binOffsets, v.shiftFirst, v.shiftNext); error == nil {
+			t.Errorf("NewValidatedBinning(%d, %v, %d, %d) returned no error, expected error", v.maxPosition, v.binOffsets, v.shiftFirst, v.shiftNext)
+		}
+	}
+}
+
+func TestNewBinningFromShifts(t *testing.T) {
+	b, error := NewBinningFromShifts(5, 17, 3, 1<<29-1)
+	if error != nil {
+		t.Fatalf("NewBinningFromShifts(%d, %d, %d, %d) returned error: %v", 5, 17, 3, 1<<29-1, error)
+	}
+	standard := StandardBinning()
+	for i, offset := range b.binOffsets {
+		if offset != standard.binOffsets[i] {
+			t.Errorf("NewBinningFromShifts(%d, %d, %d, %d).binOffsets[%d] = %d, expected %d", 5, 17, 3, 1<<29-1, i, offset, standard.binOffsets[i])
+		}
+	}
+
+	b, error = NewBinningFromShifts(3, 10, 4, 1<<18-1)
+	if error != nil {
+		t.Fatalf("NewBinningFromShifts(%d, %d, %d, %d) returned error: %v", 3, 10, 4, 1<<18-1, error)
+	}
+	expected := []int{17, 1, 0}
+	for i, offset := range b.binOffsets {
+		if offset != expected[i] {
+			t.Errorf("NewBinningFromShifts(%d, %d, %d, %d).binOffsets[%d] = %d, expected %d", 3, 10, 4, 1<<18-1, i, offset, expected[i])
+		}
+	}
+
+	for _, levels := range []int{0, 4} {
+		if _, error := NewBinningFromShifts(levels, 17, 3, 1<<29-1); error == nil {
+			t.Errorf("NewBinningFromShifts(%d, %d, %d, %d) returned no error, expected error", levels, 17, 3, 1<<29-1)
+		}
+	}
+}
+
+func TestFineBinning(t *testing.T) {
+	b := FineBinning()
+	if b.MaxPosition != 1<<30-1 || b.MinBin != 0 || b.MaxBin != 299592 {
+		t.Errorf("FineBinning() = %v, expected MaxPosition %d, MinBin %d, MaxBin %d", b, 1<<30-1, 0, 299592)
+	}
+	for _, v := range []struct{ start, stop, bin int }{
+		{0, 1, 37449},
+		{0, 1 << 12, 37449},
+		{0, 1<<12 + 1, 4681},
+		{1<<30 - 1, 1 << 30, 299592},
+		{0, 1 << 30, 0},
+	} {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+}
+
+func TestCoarseBinning(t *testing.T) {
+	b := CoarseBinning()
+	if b.MaxPosition != 1<<29-1 || b.MinBin != 0 || b.MaxBin != 584 {
+		t.Errorf("CoarseBinning() = %v, expected MaxPosition %d, MinBin %d, MaxBin %d", b, 1<<29-1, 0, 584)
+	}
+	for _, v := range []struct{ start, stop, bin int }{
+		{0, 1, 73},
+		{0, 1 << 20, 73},
+		{0, 1<<20 + 1, 9},
+		{1<<29 - 1, 1 << 29, 584},
+		{0, 1 << 29, 0},
+		{74012, 173034, 73},
+	} {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+}
+
+func TestNewBinningFromLevels(t *testing.T) {
+	b, error := NewBinningFromLevels(1<<29-1, nil, []uint{12, 16, 23, 29})
+	if error != nil {
+		t.Fatalf("NewBinningFromLevels(%d, %v, %v) returned error: %v", 1<<29-1, nil, []uint{12, 16, 23, 29}, error)
+	}
+	expected := []int{64 + 1 + 1<<13, 64 + 1, 1, 0}
+	for i, offset := range b.binOffsets {
+		if offset != expected[i] {
+			t.Errorf("NewBinningFromLevels(%d, %v, %v).binOffsets[%d] = %d, expected %d", 1<<29-1, nil, []uint{12, 16, 23, 29}, i, offset, expected[i])
+		}
+	}
+	for _, v := range []struct{ start, stop, bin int }{
+		{0, 1, 64 + 1 + 1<<13},
+		{0, 1<<12 + 1, 64 + 1},
+		{1<<16 - 1, 1<<16 + 1, 1},
+		{1<<29 - 1, 1 << 29, 64 + 1<<13 + 1<<17},
+		{1<<23 - 1, 1<<23 + 1, 0},
+	} {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		} else if start, stop, error := b.Covered(bin); error != nil {
+			t.Errorf("Covered(%d) returned error: %v", bin, error)
+		} else if start > v.start || v.stop > stop {
+			t.Errorf("Covered(%d) = (%d, %d), expected (<=%d, >=%d)", bin, start, stop, v.start, v.stop)
+		}
+	}
+
+	for _, shifts := range [][]uint{{}, {12, 16, 16, 29}, {12, 16, 23}} {
+		if _, error := NewBinningFromLevels(1<<29-1, nil, shifts); error == nil {
+			t.Errorf("NewBinningFromLevels(%d, %v, %v) returned no error, expected error", 1<<29-1, nil, shifts)
+		}
+	}
+	if _, error := NewBinningFromLevels(1<<29-1, []int{585, 73, 9, 1, 0}, []uint{17, 20, 23, 26}); error == nil {
+		t.Errorf("NewBinningFromLevels(%d, %v, %v) returned no error, expected error", 1<<29-1, []int{585, 73, 9, 1, 0}, []uint{17, 20, 23, 26})
+	}
+}
+
 func TestAssignInvalid(t *testing.T) {
 	b := StandardBinning()
 	for _, v := range invalidIntervals {
@@ -122,6 +424,145 @@ func TestAssignInvalid(t *testing.T) {
 	}
 }
 
+func TestAssignClamped(t *testing.T) {
+	b := StandardBinning().Clamped()
+	for _, v := range []struct{ start, stop, bin int }{
+		{-2, 1, 585},
+		{-2, 1 << 29, 0},
+		{1<<29 - 1, 1<<29 + 2, 4680},
+		{-23442, 1<<29 + 3431, 0},
+		{74012, 173034, 73},
+	} {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+	for _, v := range []struct{ start, stop int }{{-23442, -334}, {-23442, 0}, {-1, -1}, {1 << 29, 1<<29 + 1}} {
+		if bin, error := b.Assign(v.start, v.stop); error == nil {
+			t.Errorf("Assign(%d, %d) = %d, expected error", v.start, v.stop, bin)
+		}
+	}
+}
+
+func TestValidateBin(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range intervalBins {
+		if error := b.ValidateBin(v.bin, v.start, v.stop); error != nil {
+			t.Errorf("ValidateBin(%d, %d, %d) returned error: %v", v.bin, v.start, v.stop, error)
+		}
+		if error := b.ValidateBin(0, v.start, v.stop); error != nil {
+			t.Errorf("ValidateBin(%d, %d, %d) returned error: %v", 0, v.start, v.stop, error)
+		}
+	}
+	for _, v := range []struct{ bin, start, stop int }{
+		{586, 0, 1},
+		{585, 0, 1<<17 + 1},
+		{74, 74012, 173034},
+		{4681, 0, 1},
+		{-1, 0, 1},
+		{0, -1, 1},
+		{0, 0, 1<<29 + 1},
+	} {
+		if error := b.ValidateBin(v.bin, v.start, v.stop); error == nil {
+			t.Errorf("ValidateBin(%d, %d, %d) returned no error, expected error", v.bin, v.start, v.stop)
+		}
+	}
+
+	var mismatch *BinMismatchError
+	if error := b.ValidateBin(586, 0, 1); !errors.As(error, &mismatch) || !errors.Is(error, ErrBinMismatch) {
+		t.Errorf("ValidateBin(%d, %d, %d) returned error %v, expected BinMismatchError", 586, 0, 1, error)
+	} else if mismatch.Bin != 586 || mismatch.Assigned != 585 {
+		t.Errorf("ValidateBin(%d, %d, %d) returned %+v, expected Bin %d and Assigned %d", 586, 0, 1, mismatch, 586, 585)
+	}
+
+	c := b.Clamped()
+	if error := c.ValidateBin(585, -5, 1); error != nil {
+		t.Errorf("ValidateBin(%d, %d, %d) on clamped scheme returned error: %v", 585, -5, 1, error)
+	}
+	if error := c.ValidateBin(4680, 1<<29-1, 1<<29+5); error != nil {
+		t.Errorf("ValidateBin(%d, %d, %d) on clamped scheme returned error: %v", 4680, 1<<29-1, 1<<29+5, error)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, b := range []Binning{StandardBinning(), TabixBinning(), FineBinning(), CoarseBinning()} {
+		if error := b.Validate(); error != nil {
+			t.Errorf("Validate() for %v returned error: %v", b, error)
+		}
+	}
+	for _, v := range invalidSchemes {
+		b := NewBinning(v.maxPosition, v.binOffsets, v.shiftFirst, v.shiftNext)
+		if error := b.Validate(); error == nil {
+			t.Errorf("Validate() for %v returned no error, expected error", b)
+		}
+	}
+	b := StandardBinning()
+	b.MaxBin++
+	if error := b.Validate(); error == nil {
+		t.Errorf("Validate() for %v returned no error, expected error", b)
+	}
+	b = StandardBinning()
+	b.MinBin = 1
+	if error := b.Validate(); error == nil {
+		t.Errorf("Validate() for %v returned no error, expected error", b)
+	}
+	if error := (Binning{}).Validate(); error == nil {
+		t.Errorf("Validate() for %v returned no error, expected error", Binning{})
+	}
+}
+
+func TestValidateOverflow(t *testing.T) {
+	for _, v := range []struct {
+		name  string
+		error error
+	}{
+		{"StandardScheme[int32]", StandardScheme[int32]().Validate()},
+		{"StandardScheme[uint32]", StandardScheme[uint32]().Validate()},
+		{"ExtendedScheme[int64]", ExtendedScheme[int64]().Validate()},
+		{"ExtendedScheme[uint64]", ExtendedScheme[uint64]().Validate()},
+		{"NewCSIScheme[int32](14, 5)", NewCSIScheme[int32](14, 5).Validate()},
+	} {
+		if v.error != nil {
+			t.Errorf("Validate() for %s returned error: %v", v.name, v.error)
+		}
+	}
+	for _, v := range []struct {
+		name  string
+		error error
+	}{
+		{"ExtendedScheme[int32]", ExtendedScheme[int32]().Validate()},
+		{"ExtendedScheme[uint32]", ExtendedScheme[uint32]().Validate()},
+		{"NewCSIScheme[int32](14, 6)", NewCSIScheme[int32](14, 6).Validate()},
+		{"NewCSIScheme[int64](14, 17)", NewCSIScheme[int64](14, 17).Validate()},
+	} {
+		if v.error == nil {
+			t.Errorf("Validate() for %s returned no error, expected error", v.name)
+		}
+	}
+	if _, error := NewSchemeFromShifts[int32](6, 17, 3, 1<<31-1); error == nil {
+		t.Errorf("NewSchemeFromShifts[int32](%d, %d, %d, %d) returned no error, expected error", 6, 17, 3, 1<<31-1)
+	}
+	b := NewScheme[int32](1<<29-1, []int32{math.MaxInt32 - 100, 0}, 17, 12)
+	if bin, error := b.Assign(0, 1); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("Assign(%d, %d) on overflowing scheme = (%d, %v), expected ErrInvalidScheme", 0, 1, bin, error)
+	}
+	if bins, error := b.Overlapping(0, 1); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("Overlapping(%d, %d) on overflowing scheme = (%v, %v), expected ErrInvalidScheme", 0, 1, bins, error)
+	}
+	if start, stop, error := b.Covered(0); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("Covered(%d) on overflowing scheme = (%d, %d, %v), expected ErrInvalidScheme", 0, start, stop, error)
+	}
+	e := ExtendedScheme[int32]()
+	if start, stop, error := e.Covered(e.MinBin); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("Covered(%d) on ExtendedScheme[int32] = (%d, %d, %v), expected ErrInvalidScheme", e.MinBin, start, stop, error)
+	}
+	if bin, error := e.Assign(1<<31-2, 1<<31-1); error != nil || bin != e.MaxBin {
+		t.Errorf("Assign(%d, %d) on ExtendedScheme[int32] = (%d, %v), expected %d", 1<<31-2, 1<<31-1, bin, error, e.MaxBin)
+	}
+}
+
 func TestRanges(t *testing.T) {
 	b := StandardBinning()
 	for _, v := range intervalRanges {
@@ -148,8 +589,8 @@ func TestRanges(t *testing.T) {
 func TestRangesInvalid(t *testing.T) {
 	b := StandardBinning()
 	for _, v := range invalidIntervals {
-		if r, error := b.ranges(v.start, v.stop); error == nil {
-			t.Errorf("ranges(%d, %d) = %q, expected error", v.start, v.stop, r)
+		if _, error := b.ranges(v.start, v.stop); error == nil {
+			t.Errorf("ranges(%d, %d) returned no error, expected error", v.start, v.stop)
 		}
 	}
 }
@@ -175,6 +616,58 @@ func TestOverlapping(t *testing.T) {
 	}
 }
 
+func TestOverlappingOrdered(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range intervalOverlappingOrderedBins {
+		bins, error := b.OverlappingOrdered(v.start, v.stop, v.order)
+		if error != nil {
+			t.Errorf("OverlappingOrdered(%d, %d, %d) returned error: %v", v.start, v.stop, v.order, error)
+			continue
+		}
+		if len(bins) != len(v.bins) {
+			t.Errorf("len(OverlappingOrdered(%d, %d, %d)) = %v, expected %v", v.start, v.stop, v.order, len(bins), len(v.bins))
+			continue
+		}
+		for i := 0; i < len(bins); i++ {
+			if bins[i] != v.bins[i] {
+				t.Errorf("OverlappingOrdered(%d, %d, %d)[%d] = %v, expected %v", v.start, v.stop, v.order, i, bins[i], v.bins[i])
+				break
+			}
+		}
+	}
+}
+
+func TestOverlappingBudget(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range intervalOverlappingBudgetBins {
+		bins, ranges, error := b.OverlappingBudget(v.start, v.stop, v.maxBins)
+		if error != nil {
+			t.Errorf("OverlappingBudget(%d, %d, %d) returned error: %v", v.start, v.stop, v.maxBins, error)
+			continue
+		}
+		if len(bins) != len(v.bins) {
+			t.Errorf("len(OverlappingBudget(%d, %d, %d) bins) = %v, expected %v", v.start, v.stop, v.maxBins, len(bins), len(v.bins))
+			continue
+		}
+		for i := 0; i < len(bins); i++ {
+			if bins[i] != v.bins[i] {
+				t.Errorf("OverlappingBudget(%d, %d, %d) bins[%d] = %v, expected %v", v.start, v.stop, v.maxBins, i, bins[i], v.bins[i])
+				break
+			}
+		}
+		if len(ranges) != len(v.ranges) {
+			t.Errorf("len(OverlappingBudget(%d, %d, %d) ranges) = %v, expected %v", v.start, v.stop, v.maxBins, len(ranges), len(v.ranges))
+			continue
+		}
+		for i := 0; i < len(ranges); i++ {
+			if ranges[i] != v.ranges[i] {
+				t.Errorf("OverlappingBudget(%d, %d, %d) ranges[%d] = %v, expected %v", v.start, v.stop, v.maxBins, i, ranges[i], v.ranges[i])
+				break
+			}
+		}
+	}
+}
+
 func TestContaining(t *testing.T) {
 	b := StandardBinning()
 	for _, v := range intervalContainingBins {
@@ -217,6 +710,67 @@ func TestContained(t *testing.T) {
 	}
 }
 
+var intervalBoundaries = []struct {
+	start, stop int
+	boundaries  []int
+}{
+	{0, 1, []int{}},
+	{0, 1 << 17, []int{}},
+	{0, 1<<17 + 1, []int{1 << 17}},
+	{1 << 17, 1<<17 + 1, []int{}},
+	{1<<17 - 1, 1<<17 + 1, []int{1 << 17}},
+	{1200000, 2000000, []int{1179648 + 1<<17, 1179648 + 2<<17, 1179648 + 3<<17, 1179648 + 4<<17, 1179648 + 5<<17, 1179648 + 6<<17}},
+	{1<<29 - 1, 1 << 29, []int{}},
+}
+
+func TestBoundaries(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range intervalBoundaries {
+		boundaries, error := b.Boundaries(v.start, v.stop)
+		if error != nil {
+			t.Errorf("Boundaries(%d, %d) returned error: %v", v.start, v.stop, error)
+			continue
+		}
+		if len(boundaries) != len(v.boundaries) {
+			t.Errorf("len(Boundaries(%d, %d)) = %v, expected %v", v.start, v.stop, len(boundaries), len(v.boundaries))
+			continue
+		}
+		for i := 0; i < len(boundaries); i++ {
+			if boundaries[i] != v.boundaries[i] {
+				t.Errorf("Boundaries(%d, %d)[%d] = %v, expected %v", v.start, v.stop, i, boundaries[i], v.boundaries[i])
+				break
+			}
+		}
+	}
+}
+
+func TestBoundariesMaxPosition(t *testing.T) {
+	b := ExtendedScheme[int32]()
+	start, stop := int32(1<<31-3<<17), int32(1<<31-1<<17+5)
+	boundaries, error := b.Boundaries(start, stop)
+	if expected := []int32{1<<31 - 2<<17, 1<<31 - 1<<17}; error != nil || !slices.Equal(boundaries, expected) {
+		t.Errorf("Boundaries(%d, %d) = %v, %v, expected %v", start, stop, boundaries, error, expected)
+	}
+	boundaries, error = b.Boundaries(b.MaxPosition-1, b.MaxPosition)
+	if error != nil || len(boundaries) != 0 {
+		t.Errorf("Boundaries(%d, %d) = %v, %v, expected none", b.MaxPosition-1, b.MaxPosition, boundaries, error)
+	}
+}
+
+func TestBoundariesInvalid(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range invalidIntervals {
+		if boundaries, error := b.Boundaries(v.start, v.stop); error == nil {
+			t.Errorf("Boundaries(%d, %d) = %v, expected error", v.start, v.stop, boundaries)
+		}
+	}
+	for _, b := range []Binning{{}, NewBinning(100, []int{}, 1, 1)} {
+		if boundaries, error := b.Boundaries(0, 1); !errors.Is(error, ErrInvalidScheme) {
+			t.Errorf("Boundaries(%d, %d) on %v = (%v, %v), expected ErrInvalidScheme", 0, 1, b, boundaries, error)
+		}
+	}
+}
+
 func TestAssignCovered(t *testing.T) {
 	b := StandardBinning()
 	for _, v := range intervalBins {
@@ -0,0 +1,92 @@
+package binning
+
+import (
+	"errors"
+	"hash/fnv"
+)
+
+// bloomFilter is a Bloom filter of 512 bits using 3 hash functions. Being an
+// array, it is copied by value, which keeps it consistent with snapshots.
+type bloomFilter [8]uint64
+
+// bloomHashes returns the bit positions for key in a bloomFilter, derived
+// from a single 64-bit hash by double hashing.
+func bloomHashes(key string) [3]uint {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	sum := h.Sum64()
+	h1, h2 := uint(sum), uint(sum>>32)|1
+	return [3]uint{h1 % 512, (h1 + h2) % 512, (h1 + 2*h2) % 512}
+}
+
+// add returns the filter with key added.
+func (f bloomFilter) add(key string) bloomFilter {
+	for _, bit := range bloomHashes(key) {
+		f[bit/64] |= 1 << (bit % 64)
+	}
+	return f
+}
+
+// mayContain reports whether key may have been added to the filter. If it
+// returns false, key was certainly not added.
+func (f bloomFilter) mayContain(key string) bool {
+	for _, bit := range bloomHashes(key) {
+		if f[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EnableBloomFilters makes the index maintain a small Bloom filter per bin
+// of the keys of the values in the bin, as given by key. This allows
+// ContainsKeyNear to skip bins that certainly do not contain a key. Filters
+// are built for the entries already in the index. Deleting entries does not
+// remove their keys from the filters, which only makes them less effective.
+func (x *BinIndex[T]) EnableBloomFilters(key func(T) string) {
+	x.key = key
+	x.blooms = make(map[int]bloomFilter, len(x.bins))
+	for bin, entries := range x.bins {
+		for _, e := range entries {
+			x.addKey(bin, e.Value)
+		}
+	}
+}
+
+// addKey adds the key of value to the Bloom filter of bin, if Bloom filters
+// are enabled.
+func (x *BinIndex[T]) addKey(bin int, value T) {
+	if x.key != nil {
+		x.blooms[bin] = x.blooms[bin].add(x.key(value))
+	}
+}
+
+// ContainsKeyNear reports whether any interval overlapping the interval
+// start:stop by at least one position has a value with the given key. Bins
+// for which the Bloom filter rules out the key are not scanned. It returns an
+// error if Bloom filters are not enabled, see EnableBloomFilters.
+func (x *BinIndex[T]) ContainsKeyNear(start, stop int, key string) (bool, error) {
+	if x.key == nil {
+		return false, errors.New("bloom filters are not enabled")
+	}
+	nextRange, err := x.scheme.ranges(start, stop)
+	if err != nil {
+		return false, err
+	}
+	for {
+		startBin, stopBin, ok := nextRange()
+		if !ok {
+			return false, nil
+		}
+		for bin := startBin; bin <= stopBin; bin++ {
+			if !x.blooms[bin].mayContain(key) {
+				continue
+			}
+			for _, e := range x.bins[bin] {
+				if max(e.Start, start) < min(e.Stop, stop) && x.key(e.Value) == key {
+					return true, nil
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package binning
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	var f bloomFilter
+	for i := 0; i < 20; i++ {
+		f = f.add(strconv.Itoa(i))
+	}
+	for i := 0; i < 20; i++ {
+		if !f.mayContain(strconv.Itoa(i)) {
+			t.Errorf("mayContain(%q) = false, expected true", strconv.Itoa(i))
+		}
+	}
+	positives := 0
+	for i := 20; i < 1020; i++ {
+		if f.mayContain(strconv.Itoa(i)) {
+			positives++
+		}
+	}
+	if positives > 50 {
+		t.Errorf("mayContain() returned %d false positives out of %d, expected at most %d", positives, 1000, 50)
+	}
+}
+
+func TestContainsKeyNear(t *testing.T) {
+	x := newTestIndex(t)
+	if found, error := x.ContainsKeyNear(0, 100, "a"); error == nil {
+		t.Errorf("ContainsKeyNear(%d, %d, %q) = %v, expected error", 0, 100, "a", found)
+	}
+	x.EnableBloomFilters(func(name string) string { return name })
+	h, _ := x.Insert(300, 400, "g")
+	snapshot := x.Snapshot()
+	x.Update(h, 1<<20, 1<<20+10)
+	for _, v := range []struct {
+		start, stop int
+		key         string
+		found       bool
+	}{
+		{0, 100, "a", true},
+		{0, 100, "b", true},
+		{0, 50, "b", false},
+		{0, 100, "c", false},
+		{0, 1 << 29, "e", true},
+		{0, 1000, "g", false},
+		{1 << 20, 1<<20 + 1, "g", true},
+	} {
+		if found, error := x.ContainsKeyNear(v.start, v.stop, v.key); error != nil {
+			t.Errorf("ContainsKeyNear(%d, %d, %q) returned error: %v", v.start, v.stop, v.key, error)
+		} else if found != v.found {
+			t.Errorf("ContainsKeyNear(%d, %d, %q) = %v, expected %v", v.start, v.stop, v.key, found, v.found)
+		}
+	}
+	if found, _ := snapshot.ContainsKeyNear(0, 1000, "g"); !found {
+		t.Errorf("Snapshot().ContainsKeyNear(%d, %d, %q) = %v, expected %v", 0, 1000, "g", found, true)
+	}
+	if found, _ := x.ContainsKeyNear(-1, 10, "a"); found {
+		t.Errorf("ContainsKeyNear(%d, %d, %q) = %v, expected %v", -1, 10, "a", found, false)
+	}
+}
@@ -0,0 +1,99 @@
+package binning
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadChromSizes reads chromosome names and lengths from r and returns a
+// genome binning for them. The input is in the UCSC chrom.sizes format, with
+// one chromosome per line and the name and length in the first two
+// whitespace-separated columns. Additional columns, empty lines and lines
+// starting with # are ignored.
+func ReadChromSizes(r io.Reader) (GenomeBinning, error) {
+	chromosomes := []Chromosome{}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return GenomeBinning{}, errors.New(fmt.Sprintf("invalid chrom.sizes line %d: expected at least 2 columns, found %d", line, len(fields)))
+		}
+		length, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return GenomeBinning{}, errors.New(fmt.Sprintf("invalid chrom.sizes line %d: invalid length %q", line, fields[1]))
+		}
+		chromosomes = append(chromosomes, Chromosome{Name: fields[0], Length: length})
+	}
+	if err := scanner.Err(); err != nil {
+		return GenomeBinning{}, err
+	}
+
+	return NewGenomeBinning(chromosomes)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. The genome
+// binning is written in the chrom.sizes format, with a comma-separated list of
+// aliases in the third column for chromosomes that have aliases.
+func (g GenomeBinning) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	aliases := g.chromosomeAliases()
+	for i, c := range g.chromosomes {
+		fmt.Fprintf(&b, "%s\t%d", c.Name, c.Length)
+		if len(aliases[i]) > 0 {
+			fmt.Fprintf(&b, "\t%s", strings.Join(aliases[i], ","))
+		}
+		b.WriteString("\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. The text is
+// read as with ReadChromSizes, except that a third column is read as a
+// comma-separated list of aliases for the chromosome.
+func (g *GenomeBinning) UnmarshalText(text []byte) error {
+	chromosomes := []Chromosome{}
+	aliases := make(Aliases)
+
+	for i, line := range strings.Split(string(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			return errors.New(fmt.Sprintf("invalid genome line %d: expected 2 or 3 columns, found %d", i+1, len(fields)))
+		}
+		length, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return errors.New(fmt.Sprintf("invalid genome line %d: invalid length %q", i+1, fields[1]))
+		}
+		chromosomes = append(chromosomes, Chromosome{Name: fields[0], Length: length})
+		if len(fields) == 3 {
+			for _, alias := range strings.Split(fields[2], ",") {
+				if _, ok := aliases[alias]; ok || alias == "" {
+					return errors.New(fmt.Sprintf("invalid genome line %d: invalid alias %q", i+1, alias))
+				}
+				aliases[alias] = fields[0]
+			}
+		}
+	}
+
+	genome, err := NewGenomeBinning(chromosomes)
+	if err != nil {
+		return err
+	}
+	if genome, err = genome.WithAliases(aliases); err != nil {
+		return err
+	}
+	*g = genome
+	return nil
+}
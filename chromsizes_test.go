@@ -0,0 +1,72 @@
+package binning
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadChromSizes(t *testing.T) {
+	input := "# comment\nchr1\t248956422\nchr21\t46709983\textra\n\nchrM 16569\n"
+	g, error := ReadChromSizes(strings.NewReader(input))
+	if error != nil {
+		t.Fatalf("ReadChromSizes(%q) returned error: %v", input, error)
+	}
+	chromosomes := g.Chromosomes()
+	if len(chromosomes) != len(testChromosomes) {
+		t.Fatalf("len(ReadChromSizes(%q).Chromosomes()) = %d, expected %d", input, len(chromosomes), len(testChromosomes))
+	}
+	for i, c := range chromosomes {
+		if c != testChromosomes[i] {
+			t.Errorf("ReadChromSizes(%q).Chromosomes()[%d] = %v, expected %v", input, i, c, testChromosomes[i])
+		}
+	}
+}
+
+func TestReadChromSizesInvalid(t *testing.T) {
+	for _, input := range []string{
+		"chr1\n",
+		"chr1\tabc\n",
+		"chr1\t100\nchr1\t200\n",
+		"chr1\t0\n",
+		"chr1\t-100\n",
+	} {
+		if _, error := ReadChromSizes(strings.NewReader(input)); error == nil {
+			t.Errorf("ReadChromSizes(%q) returned no error, expected error", input)
+		}
+	}
+}
+
+func TestGenomeMarshalText(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	g, _ = g.WithAliases(Aliases{"1": "chr1", "NC_000001.11": "chr1", "MT": "chrM"})
+	text, error := g.MarshalText()
+	if error != nil {
+		t.Fatalf("MarshalText() returned error: %v", error)
+	}
+	expected := "chr1\t248956422\t1,NC_000001.11\nchr21\t46709983\nchrM\t16569\tMT\n"
+	if string(text) != expected {
+		t.Errorf("MarshalText() = %q, expected %q", text, expected)
+	}
+	var parsed GenomeBinning
+	if error := parsed.UnmarshalText(text); error != nil {
+		t.Fatalf("UnmarshalText(%q) returned error: %v", text, error)
+	}
+	if again, _ := parsed.MarshalText(); string(again) != expected {
+		t.Errorf("UnmarshalText(%q).MarshalText() = %q, expected %q", text, again, expected)
+	}
+}
+
+func TestGenomeUnmarshalTextInvalid(t *testing.T) {
+	for _, input := range []string{
+		"chr1\n",
+		"chr1\t100\t1\textra\n",
+		"chr1\t100\t1,\n",
+		"chr1\t100\t1\nchr2\t100\t1\n",
+		"chr1\t100\tchr2\nchr2\t100\n",
+	} {
+		var g GenomeBinning
+		if error := g.UnmarshalText([]byte(input)); error == nil {
+			t.Errorf("UnmarshalText(%q) returned no error, expected error", input)
+		}
+	}
+}
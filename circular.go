@@ -0,0 +1,101 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A CircularBinning implements an interval binning scheme on a circular
+// sequence, such as a mitochondrial genome or a plasmid. An interval start:stop
+// with stop < start wraps around the origin and consists of the two arcs
+// start:Length and 0:stop.
+type CircularBinning struct {
+	Scheme Binning
+	Length int
+}
+
+// NewCircularBinning creates a new binning scheme on a circular sequence of
+// the given length, using binning scheme b for positions on the sequence.
+func NewCircularBinning(length int, b Binning) (CircularBinning, error) {
+	if length < 1 || length > b.MaxPosition+1 {
+		return CircularBinning{}, errors.New(fmt.Sprintf("invalid sequence length: %d (maximum position is %d)", length, b.MaxPosition))
+	}
+	return CircularBinning{Scheme: b, Length: length}, nil
+}
+
+// checkRange returns an error if the interval start:stop is not on the
+// circular sequence.
+func (c CircularBinning) checkRange(start, stop int) error {
+	if start < 0 || start > c.Length || stop < 0 || stop > c.Length {
+		return &OutOfRangeError{Start: int64(start), Stop: int64(stop), MaxPosition: int64(c.Length - 1)}
+	}
+	return nil
+}
+
+// Assign returns the smallest bin fitting the interval start:stop. For an
+// interval wrapping around the origin, this is the smallest bin fitting both
+// arcs.
+func (c CircularBinning) Assign(start, stop int) (int, error) {
+	if err := c.checkRange(start, stop); err != nil {
+		return 0, err
+	}
+	if stop < start {
+		return c.Scheme.Assign(0, c.Length)
+	}
+	return c.Scheme.Assign(start, stop)
+}
+
+// Overlapping returns bins for all intervals overlapping the interval
+// start:stop by at least one position. For an interval wrapping around the
+// origin, these are the bins overlapping either arc. Bins are ordered as with
+// Binning.Overlapping.
+func (c CircularBinning) Overlapping(start, stop int) ([]int, error) {
+	if err := c.checkRange(start, stop); err != nil {
+		return nil, err
+	}
+	if stop >= start || stop == 0 {
+		if stop < start {
+			stop = c.Length
+		}
+		return c.Scheme.Overlapping(start, stop)
+	}
+
+	nextFirst, err := c.Scheme.ranges(0, stop)
+	if err != nil {
+		return nil, err
+	}
+	nextSecond, err := c.Scheme.ranges(start, c.Length)
+	if err != nil {
+		return nil, err
+	}
+
+	bins := []int{}
+
+	for {
+		firstStart, firstStop, ok := nextFirst()
+		if !ok {
+			break
+		}
+		secondStart, secondStop, _ := nextSecond()
+		for bin := firstStart; bin <= firstStop; bin++ {
+			bins = append(bins, bin)
+		}
+		for bin := max(secondStart, firstStop+1); bin <= secondStop; bin++ {
+			bins = append(bins, bin)
+		}
+	}
+
+	return bins, nil
+}
+
+// Covered returns the interval covered by bin, clipped to the sequence.
+func (c CircularBinning) Covered(bin int) (int, int, error) {
+	start, stop, err := c.Scheme.Covered(bin)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start >= c.Length {
+		return 0, 0, &InvalidBinError{Bin: int64(bin), MinBin: int64(c.Scheme.MinBin), MaxBin: int64(c.Scheme.MaxBin), Length: int64(c.Length)}
+	}
+	return start, min(stop, c.Length), nil
+}
@@ -0,0 +1,94 @@
+package binning
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCircularAssign(t *testing.T) {
+	c, error := NewCircularBinning(16569, StandardBinning())
+	if error != nil {
+		t.Fatalf("NewCircularBinning(%d) returned error: %v", 16569, error)
+	}
+	for _, v := range []struct{ start, stop, bin int }{
+		{0, 1, 585},
+		{16000, 16569, 585},
+		{16000, 100, 585},
+	} {
+		if bin, error := c.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+
+	c, error = NewCircularBinning(3<<17, StandardBinning())
+	if error != nil {
+		t.Fatalf("NewCircularBinning(%d) returned error: %v", 3<<17, error)
+	}
+	for _, v := range []struct{ start, stop, bin int }{
+		{3<<17 - 10, 3 << 17, 587},
+		{3<<17 - 10, 10, 73},
+		{1 << 17, 10, 73},
+	} {
+		if bin, error := c.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+}
+
+func TestCircularOverlapping(t *testing.T) {
+	c, error := NewCircularBinning(3<<17, StandardBinning())
+	if error != nil {
+		t.Fatalf("NewCircularBinning(%d) returned error: %v", 3<<17, error)
+	}
+	for _, v := range []struct {
+		start, stop int
+		bins        []int
+	}{
+		{0, 1, []int{585, 73, 9, 1, 0}},
+		{3<<17 - 10, 0, []int{587, 73, 9, 1, 0}},
+		{3<<17 - 10, 10, []int{585, 587, 73, 9, 1, 0}},
+		{1<<17 - 10, 1<<17 - 20, []int{585, 586, 587, 73, 9, 1, 0}},
+	} {
+		bins, error := c.Overlapping(v.start, v.stop)
+		if error != nil {
+			t.Errorf("Overlapping(%d, %d) returned error: %v", v.start, v.stop, error)
+			continue
+		}
+		if len(bins) != len(v.bins) {
+			t.Errorf("len(Overlapping(%d, %d)) = %v, expected %v", v.start, v.stop, len(bins), len(v.bins))
+			continue
+		}
+		for i := 0; i < len(bins); i++ {
+			if bins[i] != v.bins[i] {
+				t.Errorf("Overlapping(%d, %d)[%d] = %v, expected %v", v.start, v.stop, i, bins[i], v.bins[i])
+				break
+			}
+		}
+	}
+}
+
+func TestCircularInvalid(t *testing.T) {
+	for _, length := range []int{0, -1, 1<<29 + 1} {
+		if c, error := NewCircularBinning(length, StandardBinning()); error == nil {
+			t.Errorf("NewCircularBinning(%d) = %v, expected error", length, c)
+		}
+	}
+	c, _ := NewCircularBinning(16569, StandardBinning())
+	for _, v := range []struct{ start, stop int }{{-1, 10}, {10, 16570}, {16570, 10}} {
+		if bin, error := c.Assign(v.start, v.stop); error == nil {
+			t.Errorf("Assign(%d, %d) = %d, expected error", v.start, v.stop, bin)
+		}
+		if bins, error := c.Overlapping(v.start, v.stop); error == nil {
+			t.Errorf("Overlapping(%d, %d) = %v, expected error", v.start, v.stop, bins)
+		}
+	}
+	if start, stop, error := c.Covered(586); error == nil {
+		t.Errorf("Covered(%d) = (%d, %d), expected error", 586, start, stop)
+	} else if !errors.Is(error, ErrInvalidBin) {
+		t.Errorf("Covered(%d) returned error %v, expected ErrInvalidBin", 586, error)
+	}
+}
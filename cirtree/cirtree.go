@@ -0,0 +1,266 @@
+// Package cirtree writes and searches the chromosome-interval R-tree (cirTree)
+// index used in bigBed and bigWig files, so that such files can be written
+// in pure Go. Leaf items of the tree are ranges of the indexed file holding
+// sorted records, which can be grouped along the bins of a binning scheme
+// with Group.
+// https://genome.ucsc.edu/goldenPath/help/bigBed.html
+package cirtree
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/martijnvermaat/binning"
+)
+
+// Magic is the signature at the start of a cirTree index.
+const Magic = 0x2468ACE0
+
+// Sizes of the header and node items in bytes.
+const (
+	headerSize   = 48
+	nodeHeader   = 4
+	leafItemSize = 32
+	nodeItemSize = 24
+)
+
+// An Item is a leaf item in the index: a range of Size bytes at Offset in the
+// indexed file with records from position Start on chromosome StartChrom up
+// to position End on chromosome EndChrom. Chromosomes are identified by
+// their index in the chromosome tree of the file.
+type Item struct {
+	StartChrom, Start uint32
+	EndChrom, End     uint32
+	Offset, Size      uint64
+}
+
+// A Record is a record in the indexed file, spanning Start:End on
+// chromosome Chrom and stored in Size bytes at Offset.
+type Record struct {
+	Chrom, Start, End uint32
+	Offset, Size      uint64
+}
+
+// Group groups records into leaf items of at most itemsPerSlot records. A new
+// item is started when the chromosome changes or when the start position of
+// a record is in a different bin on the smallest level of b than that of the
+// first record in the item, so that items do not straddle bin boundaries.
+// Records must be sorted and stored contiguously in the indexed file.
+func Group(records []Record, itemsPerSlot int, b binning.Binning) ([]Item, error) {
+	if itemsPerSlot < 1 {
+		return nil, errors.New(fmt.Sprintf("invalid number of items per slot: %d", itemsPerSlot))
+	}
+
+	var items []Item
+	var first, count int
+	for i, r := range records {
+		bin, err := b.Assign(int(r.Start), int(r.Start)+1)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			p := records[i-1]
+			if r.Chrom < p.Chrom || (r.Chrom == p.Chrom && r.Start < p.Start) {
+				return nil, errors.New(fmt.Sprintf("record %d is not sorted", i))
+			}
+			if r.Offset != p.Offset+p.Size {
+				return nil, errors.New(fmt.Sprintf("record %d is not stored after record %d", i, i-1))
+			}
+		}
+		if len(items) == 0 || count == itemsPerSlot || r.Chrom != items[len(items)-1].StartChrom || bin != first {
+			items = append(items, Item{StartChrom: r.Chrom, Start: r.Start, EndChrom: r.Chrom, End: r.End, Offset: r.Offset})
+			first, count = bin, 0
+		}
+		item := &items[len(items)-1]
+		item.End = max(item.End, r.End)
+		item.Size = r.Offset + r.Size - item.Offset
+		count++
+	}
+	return items, nil
+}
+
+// An Index is a cirTree index over Items, which must be sorted. Nodes have
+// at most BlockSize children and ItemsPerSlot is the number of records per
+// item recorded in the header. DataEnd is the end of the indexed data in
+// the file.
+type Index struct {
+	BlockSize    int
+	ItemsPerSlot int
+	Items        []Item
+	DataEnd      uint64
+}
+
+// levels returns the number of nodes per level of the tree, starting with
+// the leaves.
+func (x *Index) levels() []int {
+	levels := []int{max((len(x.Items)+x.BlockSize-1)/x.BlockSize, 1)}
+	for levels[len(levels)-1] > 1 {
+		n := levels[len(levels)-1]
+		levels = append(levels, (n+x.BlockSize-1)/x.BlockSize)
+	}
+	return levels
+}
+
+// bounds returns the items spanning the nodes on level with their child
+// ranges in the level below, starting with the leaves as nodes on level 0.
+func (x *Index) bounds(level int) []Item {
+	nodes := x.Items
+	for l := 0; l < level; l++ {
+		var parents []Item
+		for i := 0; i < len(nodes); i += x.BlockSize {
+			children := nodes[i:min(i+x.BlockSize, len(nodes))]
+			last := children[len(children)-1]
+			parent := Item{StartChrom: children[0].StartChrom, Start: children[0].Start, EndChrom: last.EndChrom, End: last.End}
+			for _, c := range children {
+				if c.EndChrom == parent.EndChrom {
+					parent.End = max(parent.End, c.End)
+				}
+			}
+			parents = append(parents, parent)
+		}
+		nodes = parents
+	}
+	return nodes
+}
+
+// Write writes the index to w, which is assumed to be at offset in the file
+// since child nodes are referred to by file offset. Nodes are written with
+// the root first and padded to BlockSize items. It returns the number of
+// bytes written.
+func (x *Index) Write(w io.Writer, offset int64) (int64, error) {
+	if x.BlockSize < 2 || x.BlockSize > 0xffff {
+		return 0, errors.New(fmt.Sprintf("invalid block size: %d", x.BlockSize))
+	}
+
+	data := make([]byte, 0, headerSize)
+	data = binary.LittleEndian.AppendUint32(data, Magic)
+	data = binary.LittleEndian.AppendUint32(data, uint32(x.BlockSize))
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(x.Items)))
+	levels := x.levels()
+	root := Item{}
+	if len(x.Items) > 0 {
+		root = x.bounds(len(levels))[0]
+	}
+	for _, n := range []uint32{root.StartChrom, root.Start, root.EndChrom, root.End} {
+		data = binary.LittleEndian.AppendUint32(data, n)
+	}
+	data = binary.LittleEndian.AppendUint64(data, x.DataEnd)
+	data = binary.LittleEndian.AppendUint32(data, uint32(x.ItemsPerSlot))
+	data = binary.LittleEndian.AppendUint32(data, 0)
+
+	// Offset of the first node on each level, the root level comes first.
+	starts := make([]int64, len(levels))
+	position := offset + headerSize
+	for level := len(levels) - 1; level >= 0; level-- {
+		starts[level] = position
+		position += int64(levels[level]) * x.nodeSize(level)
+	}
+
+	for level := len(levels) - 1; level >= 0; level-- {
+		items := x.bounds(level)
+		for i := 0; i < levels[level]; i++ {
+			children := items[min(i*x.BlockSize, len(items)):min((i+1)*x.BlockSize, len(items))]
+			data = append(data, byte(boolToInt(level == 0)), 0)
+			data = binary.LittleEndian.AppendUint16(data, uint16(len(children)))
+			for j, c := range children {
+				for _, n := range []uint32{c.StartChrom, c.Start, c.EndChrom, c.End} {
+					data = binary.LittleEndian.AppendUint32(data, n)
+				}
+				if level == 0 {
+					data = binary.LittleEndian.AppendUint64(data, c.Offset)
+					data = binary.LittleEndian.AppendUint64(data, c.Size)
+				} else {
+					child := int64(i*x.BlockSize + j)
+					data = binary.LittleEndian.AppendUint64(data, uint64(starts[level-1]+child*x.nodeSize(level-1)))
+				}
+			}
+			data = append(data, make([]byte, int(x.nodeSize(level))-nodeHeader-len(children)*x.itemSize(level))...)
+		}
+	}
+
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+func (x *Index) itemSize(level int) int {
+	if level == 0 {
+		return leafItemSize
+	}
+	return nodeItemSize
+}
+
+func (x *Index) nodeSize(level int) int64 {
+	return int64(nodeHeader + x.BlockSize*x.itemSize(level))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Search returns the leaf items overlapping the region start:end on
+// chromosome chrom in the cirTree index at offset in r.
+func Search(r io.ReaderAt, offset int64, chrom, start, end uint32) ([]Item, error) {
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(header) != Magic {
+		return nil, errors.New("invalid cirTree index: unknown magic")
+	}
+	blockSize := int(binary.LittleEndian.Uint32(header[4:]))
+
+	var items []Item
+	var search func(offset int64, depth int) error
+	search = func(offset int64, depth int) error {
+		if depth > 64 {
+			return errors.New("invalid cirTree index: too deep")
+		}
+		head := make([]byte, nodeHeader)
+		if _, err := r.ReadAt(head, offset); err != nil {
+			return err
+		}
+		leaf, count := head[0] == 1, int(binary.LittleEndian.Uint16(head[2:]))
+		if count > blockSize {
+			return errors.New(fmt.Sprintf("invalid cirTree index: node with %d items", count))
+		}
+		size := nodeItemSize
+		if leaf {
+			size = leafItemSize
+		}
+		node := make([]byte, count*size)
+		if _, err := r.ReadAt(node, offset+nodeHeader); err != nil {
+			return err
+		}
+		for i := 0; i < count; i++ {
+			b := node[i*size:]
+			item := Item{
+				StartChrom: binary.LittleEndian.Uint32(b),
+				Start:      binary.LittleEndian.Uint32(b[4:]),
+				EndChrom:   binary.LittleEndian.Uint32(b[8:]),
+				End:        binary.LittleEndian.Uint32(b[12:]),
+			}
+			// Compare (chromosome, position) pairs.
+			if item.StartChrom > chrom || (item.StartChrom == chrom && item.Start >= end) ||
+				item.EndChrom < chrom || (item.EndChrom == chrom && item.End <= start) {
+				continue
+			}
+			if leaf {
+				item.Offset = binary.LittleEndian.Uint64(b[16:])
+				item.Size = binary.LittleEndian.Uint64(b[24:])
+				items = append(items, item)
+			} else if err := search(int64(binary.LittleEndian.Uint64(b[16:])), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := search(offset+headerSize, 0); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
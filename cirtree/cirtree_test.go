@@ -0,0 +1,130 @@
+package cirtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/martijnvermaat/binning"
+)
+
+func TestGroup(t *testing.T) {
+	records := []Record{
+		{0, 100, 200, 0, 10},
+		{0, 150, 300, 10, 10},
+		{0, 200, 250, 20, 10},
+		{0, 1 << 17, 1<<17 + 10, 30, 10},
+		{1, 0, 50, 40, 10},
+	}
+	items, error := Group(records, 2, binning.StandardBinning())
+	if error != nil {
+		t.Fatalf("Group error: %v", error)
+	}
+	expected := []Item{
+		{0, 100, 0, 300, 0, 20},
+		{0, 200, 0, 250, 20, 10},
+		{0, 1 << 17, 0, 1<<17 + 10, 30, 10},
+		{1, 0, 1, 50, 40, 10},
+	}
+	if !reflect.DeepEqual(items, expected) {
+		t.Errorf("Group = %v, expected %v", items, expected)
+	}
+}
+
+func TestGroupInvalid(t *testing.T) {
+	tests := [][]Record{
+		{{0, 100, 200, 0, 10}, {0, 50, 300, 10, 10}},
+		{{1, 100, 200, 0, 10}, {0, 150, 300, 10, 10}},
+		{{0, 100, 200, 0, 10}, {0, 150, 300, 20, 10}},
+		{{0, 1 << 30, 1<<30 + 1, 0, 10}},
+	}
+	for _, records := range tests {
+		if _, error := Group(records, 10, binning.StandardBinning()); error == nil {
+			t.Errorf("Group(%v) did not return error", records)
+		}
+	}
+	if _, error := Group(nil, 0, binning.StandardBinning()); error == nil {
+		t.Errorf("Group(nil, 0) did not return error")
+	}
+}
+
+// testItems returns n items of 100 positions each, on chromosome 0 and 1.
+func testItems(n int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		chrom := uint32(2 * i / n)
+		items[i] = Item{chrom, uint32(i * 100), chrom, uint32(i*100 + 100), uint64(i * 50), 50}
+	}
+	return items
+}
+
+func TestWrite(t *testing.T) {
+	x := Index{BlockSize: 4, ItemsPerSlot: 512, Items: testItems(10), DataEnd: 500}
+
+	var buffer bytes.Buffer
+	prefix := make([]byte, 1000)
+	buffer.Write(prefix)
+	n, error := x.Write(&buffer, int64(len(prefix)))
+	if error != nil {
+		t.Fatalf("Write error: %v", error)
+	}
+
+	// 10 items in 3 leaf nodes under 1 root node.
+	expected := headerSize + (nodeHeader + 4*nodeItemSize) + 3*(nodeHeader+4*leafItemSize)
+	if n != int64(expected) || buffer.Len() != len(prefix)+expected {
+		t.Errorf("Write = %d, expected %d", n, expected)
+	}
+
+	// Magic, block size, item count (64 bits), start chromosome and position,
+	// end chromosome and position, data end (64 bits), items per slot and
+	// reserved.
+	expectedHeader := []uint32{Magic, 4, 10, 0, 0, 0, 1, 1000, 500, 0, 512, 0}
+	header := make([]uint32, len(expectedHeader))
+	binary.Read(bytes.NewReader(buffer.Bytes()[len(prefix):]), binary.LittleEndian, header)
+	if !reflect.DeepEqual(header, expectedHeader) {
+		t.Errorf("Write header = %v, expected %v", header, expectedHeader)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 10, 100} {
+		items := testItems(n)
+		x := Index{BlockSize: 3, ItemsPerSlot: 1, Items: items}
+		var buffer bytes.Buffer
+		buffer.Write([]byte{1, 2, 3})
+		x.Write(&buffer, 3)
+		r := bytes.NewReader(buffer.Bytes())
+
+		for _, chrom := range []uint32{0, 1, 2} {
+			for _, region := range [][2]uint32{{0, 1}, {250, 420}, {0, 100000}, {99, 100}, {100, 100}} {
+				var expected []Item
+				for _, item := range items {
+					if item.StartChrom == chrom && item.Start < region[1] && region[0] < item.End {
+						expected = append(expected, item)
+					}
+				}
+				found, error := Search(r, 3, chrom, region[0], region[1])
+				if error != nil {
+					t.Errorf("Search(%d, %d, %d) with %d items error: %v", chrom, region[0], region[1], n, error)
+				}
+				if !reflect.DeepEqual(found, expected) {
+					t.Errorf("Search(%d, %d, %d) with %d items = %v, expected %v", chrom, region[0], region[1], n, found, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestSearchInvalid(t *testing.T) {
+	if _, error := Search(bytes.NewReader(make([]byte, 100)), 0, 0, 0, 1); error == nil {
+		t.Errorf("Search did not return error for invalid magic")
+	}
+	if _, error := Search(bytes.NewReader(nil), 0, 0, 0, 1); error == nil {
+		t.Errorf("Search did not return error for empty input")
+	}
+	x := Index{BlockSize: 1}
+	if _, error := x.Write(&bytes.Buffer{}, 0); error == nil {
+		t.Errorf("Write did not return error for block size 1")
+	}
+}
@@ -0,0 +1,64 @@
+// Command bincompile compiles intervals in BED format into an index that can
+// be embedded in a Go program with go:embed and queried with
+// binning.OpenCompiledIndex without parsing the intervals at startup.
+//
+// Usage:
+//
+//	bincompile [-scheme name] [-o output] [input.bed]
+//
+// The intervals are read from standard input if no input file is given and
+// the index is written to standard output if no output file is given. The
+// binning scheme is looked up by name with binning.Lookup.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/martijnvermaat/binning"
+)
+
+func main() {
+	scheme := flag.String("scheme", "ucsc-standard", "name of the binning scheme")
+	output := flag.String("o", "", "output file (default standard output)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-scheme name] [-o output] [input.bed]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*scheme, flag.Arg(0), *output); err != nil {
+		fmt.Fprintf(os.Stderr, "bincompile: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(scheme, input, output string) error {
+	b, err := binning.Lookup(scheme)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if input != "" {
+		f, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, err := binning.CompileBED(r, b)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(output, data, 0o644)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/martijnvermaat/binning"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	input, output := filepath.Join(dir, "input.bed"), filepath.Join(dir, "index.bin")
+	if error := os.WriteFile(input, []byte("chr1\t100\t200\nchr2\t0\t10\n"), 0o644); error != nil {
+		t.Fatal(error)
+	}
+
+	if error := run("tabix", input, output); error != nil {
+		t.Fatalf("run error: %v", error)
+	}
+	data, error := os.ReadFile(output)
+	if error != nil {
+		t.Fatal(error)
+	}
+	c, error := binning.OpenCompiledIndex(data)
+	if error != nil {
+		t.Fatalf("OpenCompiledIndex error: %v", error)
+	}
+	if found, _ := c.Any("chr1", 150, 160); !found || c.Len() != 2 {
+		t.Errorf("compiled index Any(\"chr1\", 150, 160) = %v, Len() = %d, expected true, 2", found, c.Len())
+	}
+
+	if error := run("unknown", input, output); error == nil {
+		t.Errorf("run did not return error for unknown scheme")
+	}
+	if error := run("tabix", filepath.Join(dir, "missing.bed"), output); error == nil {
+		t.Errorf("run did not return error for missing input")
+	}
+}
@@ -0,0 +1,149 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// A ColumnarBinIndex stores values by interval like a BinIndex, but uses a
+// more compact memory layout: per bin, interval starts, interval stops and
+// value indexes are stored in parallel slices of 32-bit integers, and values
+// are stored in a single slice. It supports inserts and queries, but not
+// deleting or updating entries.
+type ColumnarBinIndex[T any] struct {
+	scheme Binning
+	bins   map[int]*binColumns
+	values []T
+}
+
+// binColumns holds the entries of one bin in a ColumnarBinIndex.
+type binColumns struct {
+	starts, stops, values []uint32
+}
+
+// NewColumnarBinIndex creates a new empty columnar index using binning scheme
+// b. It returns an error if positions in b do not fit in 32 bits.
+func NewColumnarBinIndex[T any](b Binning) (*ColumnarBinIndex[T], error) {
+	if uint64(b.MaxPosition) >= math.MaxUint32 {
+		return nil, errors.New(fmt.Sprintf("maximum position does not fit in 32 bits: %d", b.MaxPosition))
+	}
+	return &ColumnarBinIndex[T]{scheme: b, bins: make(map[int]*binColumns)}, nil
+}
+
+// Columnar returns a columnar index with the entries of the index, inserted
+// in the order of All. It returns an error if positions in the binning
+// scheme do not fit in 32 bits or the index has inverted intervals.
+func (x *BinIndex[T]) Columnar() (*ColumnarBinIndex[T], error) {
+	c, err := NewColumnarBinIndex[T](x.scheme)
+	if err != nil {
+		return nil, err
+	}
+	c.values = make([]T, 0, x.Len())
+	x.All()(func(e Entry[T]) bool {
+		err = c.Insert(e.Start, e.Stop, e.Value)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Scheme returns the binning scheme used by the index.
+func (x *ColumnarBinIndex[T]) Scheme() Binning {
+	return x.scheme
+}
+
+// Len returns the number of entries in the index.
+func (x *ColumnarBinIndex[T]) Len() int {
+	return len(x.values)
+}
+
+// Insert stores value for the interval start:stop. It returns an error if
+// the interval cannot be binned or the index is full. Since positions are
+// stored in 32 bits, inverted intervals are rejected with an
+// EmptyIntervalError and intervals are truncated first if the binning scheme
+// clamps.
+func (x *ColumnarBinIndex[T]) Insert(start, stop int, value T) error {
+	start, stop, err := x.scheme.checkRange(start, stop)
+	if err != nil {
+		return err
+	}
+	if stop < start {
+		return &EmptyIntervalError{Start: int64(start), Stop: int64(stop)}
+	}
+	bin, err := x.scheme.Assign(start, stop)
+	if err != nil {
+		return err
+	}
+	if uint64(len(x.values)) >= math.MaxUint32 {
+		return errors.New("index is full")
+	}
+	columns := x.bins[bin]
+	if columns == nil {
+		columns = &binColumns{}
+		x.bins[bin] = columns
+	}
+	columns.starts = append(columns.starts, uint32(start))
+	columns.stops = append(columns.stops, uint32(stop))
+	columns.values = append(columns.values, uint32(len(x.values)))
+	x.values = append(x.values, value)
+	return nil
+}
+
+// scan calls fn with the value index of each entry overlapping the interval
+// start:stop by at least one position, starting with the smallest bins,
+// until fn returns false. Empty intervals do not overlap anything.
+func (x *ColumnarBinIndex[T]) scan(start, stop int, fn func(i uint32) bool) error {
+	nextRange, err := x.scheme.ranges(start, stop)
+	if err != nil {
+		return err
+	}
+	for {
+		startBin, stopBin, ok := nextRange()
+		if !ok {
+			return nil
+		}
+		for bin := startBin; bin <= stopBin; bin++ {
+			columns := x.bins[bin]
+			if columns == nil {
+				continue
+			}
+			for i, s := range columns.starts {
+				if max(int(s), start) < min(int(columns.stops[i]), stop) && !fn(columns.values[i]) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// Overlapping returns the values for all intervals overlapping the interval
+// start:stop by at least one position. Values are ordered by bin, starting
+// with the smallest bins, and by insertion order within a bin.
+func (x *ColumnarBinIndex[T]) Overlapping(start, stop int) ([]T, error) {
+	values := []T{}
+	err := x.scan(start, stop, func(i uint32) bool {
+		values = append(values, x.values[i])
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// CountOverlapping returns the number of intervals overlapping the interval
+// start:stop by at least one position, without collecting their values.
+func (x *ColumnarBinIndex[T]) CountOverlapping(start, stop int) (int, error) {
+	n := 0
+	err := x.scan(start, stop, func(i uint32) bool {
+		n++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package binning
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestColumnarBinIndex(t *testing.T) {
+	x, error := newTestIndex(t).Columnar()
+	if error != nil {
+		t.Fatalf("Columnar() returned error: %v", error)
+	}
+	if n := x.Len(); n != len(indexIntervals) {
+		t.Errorf("Len() = %d, expected %d", n, len(indexIntervals))
+	}
+	for _, v := range []struct {
+		start, stop int
+		names       []string
+	}{
+		{0, 1, []string{"a"}},
+		{99, 100, []string{"a", "b"}},
+		{150, 1<<17 - 5, []string{}},
+		{1 << 17, 1<<17 + 1, []string{"c", "d"}},
+		{0, 1 << 29, []string{"a", "b", "c", "d", "e"}},
+	} {
+		if names, error := x.Overlapping(v.start, v.stop); error != nil {
+			t.Errorf("Overlapping(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if !sameElements(names, v.names) {
+			t.Errorf("Overlapping(%d, %d) = %v, expected %v", v.start, v.stop, names, v.names)
+		}
+		if n, error := x.CountOverlapping(v.start, v.stop); error != nil || n != len(v.names) {
+			t.Errorf("CountOverlapping(%d, %d) = (%d, %v), expected %d", v.start, v.stop, n, error, len(v.names))
+		}
+	}
+	if error := x.Insert(0, 1<<29+1, "g"); error == nil {
+		t.Errorf("Insert(%d, %d, %q) returned no error, expected error", 0, 1<<29+1, "g")
+	}
+	if error := x.Insert(10, -5, "h"); !errors.Is(error, ErrEmptyInterval) {
+		t.Errorf("Insert(%d, %d, %q) returned error %v, expected ErrEmptyInterval", 10, -5, "h", error)
+	}
+	if n := x.Len(); n != len(indexIntervals) {
+		t.Errorf("Len() = %d, expected %d", n, len(indexIntervals))
+	}
+
+	c, _ := NewColumnarBinIndex[string](StandardBinning().Clamped())
+	if error := c.Insert(-5, 10, "i"); error != nil {
+		t.Errorf("Insert(%d, %d, %q) on clamped scheme returned error: %v", -5, 10, "i", error)
+	}
+	if names, error := c.Overlapping(1<<29-1, 1<<29); error != nil || len(names) != 0 {
+		t.Errorf("Overlapping(%d, %d) on clamped scheme = (%v, %v), expected []", 1<<29-1, 1<<29, names, error)
+	}
+}
+
+func TestNewColumnarBinIndexInvalid(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("binning scheme requires 64-bit int")
+	}
+	if _, error := NewColumnarBinIndex[string](NewCSIBinning(30, 2)); error == nil {
+		t.Errorf("NewColumnarBinIndex() returned no error, expected error")
+	}
+}
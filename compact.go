@@ -0,0 +1,231 @@
+package binning
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+)
+
+// compactMagic starts the compact binary representation of an index.
+const compactMagic = "BINCMP"
+
+// compactVersion is the version of the compact binary representation
+// written by WriteCompactIndex.
+const compactVersion = 1
+
+// Sizes in bytes of a bin in the bin table (bin number and first record)
+// and of a record (start, stop and id) in the compact representation.
+const (
+	compactBinSize    = 16
+	compactRecordSize = 24
+)
+
+// compactHeader returns the header of the compact representation of an
+// index with binning scheme b, bins non-empty bins and records records.
+func compactHeader(b Binning, bins, records int) ([]byte, error) {
+	scheme, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte(compactMagic), compactVersion)
+	header = binary.BigEndian.AppendUint32(header, uint32(len(scheme)))
+	header = append(header, scheme...)
+	header = binary.BigEndian.AppendUint64(header, uint64(bins))
+	return binary.BigEndian.AppendUint64(header, uint64(records)), nil
+}
+
+// WriteCompactIndex writes the entries in index x to w in a compact binary
+// format that can be queried in place by a CompactIndex, for example after
+// memory-mapping the file. Values are stored as the fixed-size identifiers
+// returned by id, such as row numbers in a separate table. It returns the
+// number of bytes written.
+//
+// The format consists of a header with the binning scheme, a table with the
+// first record for each non-empty bin ordered by bin, and the records as
+// start and stop position and identifier, grouped by bin and ordered by
+// start position within a bin.
+func WriteCompactIndex[T any](w io.Writer, x *BinIndex[T], id func(T) uint64) (int64, error) {
+	c := &countingWriter{w: w}
+	writer := bufio.NewWriter(c)
+
+	bins := x.sortedBins()
+	header, err := compactHeader(x.scheme, len(bins), x.Len())
+	if err != nil {
+		return 0, err
+	}
+	writer.Write(header)
+
+	first := 0
+	for _, bin := range bins {
+		var entry [compactBinSize]byte
+		binary.BigEndian.PutUint64(entry[:], uint64(bin))
+		binary.BigEndian.PutUint64(entry[8:], uint64(first))
+		writer.Write(entry[:])
+		first += len(x.bins[bin])
+	}
+
+	for _, bin := range bins {
+		entries := slices.Clone(x.bins[bin])
+		slices.SortStableFunc(entries, func(a, b Entry[T]) int { return cmp.Compare(a.Start, b.Start) })
+		for _, e := range entries {
+			var record [compactRecordSize]byte
+			binary.BigEndian.PutUint64(record[:], uint64(e.Start))
+			binary.BigEndian.PutUint64(record[8:], uint64(e.Stop))
+			binary.BigEndian.PutUint64(record[16:], id(e.Value))
+			writer.Write(record[:])
+		}
+	}
+
+	err = writer.Flush()
+	return c.n, err
+}
+
+// A CompactIndex queries an index in the format written by
+// WriteCompactIndex directly on its binary representation, without
+// deserialization.
+type CompactIndex struct {
+	scheme  Binning
+	table   []byte
+	records []byte
+	close   func() error
+}
+
+// OpenCompactIndex returns a CompactIndex for the index in data, which is
+// used in place and must not be modified while the CompactIndex is in use.
+// The header and bin table are validated, the records are not.
+func OpenCompactIndex(data []byte) (*CompactIndex, error) {
+	prefix := len(compactMagic) + 5
+	if len(data) < prefix || string(data[:len(compactMagic)]) != compactMagic {
+		return nil, errors.New("not a compact index")
+	}
+	if version := data[len(compactMagic)]; version != compactVersion {
+		return nil, errors.New(fmt.Sprintf("unsupported compact index version: %d", version))
+	}
+	length := uint64(binary.BigEndian.Uint32(data[len(compactMagic)+1:]))
+	data = data[prefix:]
+	if uint64(len(data)) < length+16 {
+		return nil, errors.New("truncated compact index")
+	}
+	b, err := ParseScheme(data[:length])
+	if err != nil {
+		return nil, err
+	}
+	bins, records := binary.BigEndian.Uint64(data[length:]), binary.BigEndian.Uint64(data[length+8:])
+	data = data[length+16:]
+	if bins > uint64(len(data))/compactBinSize || records > uint64(len(data))/compactRecordSize ||
+		uint64(len(data)) != bins*compactBinSize+records*compactRecordSize {
+		return nil, errors.New(fmt.Sprintf("invalid compact index size for %d bins and %d records", bins, records))
+	}
+
+	c := &CompactIndex{scheme: b, table: data[:bins*compactBinSize], records: data[bins*compactBinSize:]}
+	for i := 0; i < int(bins); i++ {
+		bin, first := c.bin(i)
+		if bin < b.MinBin || bin > b.MaxBin || (i > 0 && bin <= c.binNumber(i-1)) {
+			return nil, errors.New(fmt.Sprintf("invalid bin in compact index: %d", bin))
+		}
+		if (i == 0 && first != 0) || (i > 0 && first < c.first(i-1)) || first > int(records) {
+			return nil, errors.New(fmt.Sprintf("invalid record offset in compact index: %d", first))
+		}
+	}
+	return c, nil
+}
+
+// bin returns the bin number and first record of entry i in the bin table.
+func (c *CompactIndex) bin(i int) (int, int) {
+	return c.binNumber(i), c.first(i)
+}
+
+func (c *CompactIndex) binNumber(i int) int {
+	return int(binary.BigEndian.Uint64(c.table[i*compactBinSize:]))
+}
+
+func (c *CompactIndex) first(i int) int {
+	if i == len(c.table)/compactBinSize {
+		return len(c.records) / compactRecordSize
+	}
+	return int(binary.BigEndian.Uint64(c.table[i*compactBinSize+8:]))
+}
+
+// Scheme returns the binning scheme used by the index.
+func (c *CompactIndex) Scheme() Binning {
+	return c.scheme
+}
+
+// Len returns the number of records in the index.
+func (c *CompactIndex) Len() int {
+	return len(c.records) / compactRecordSize
+}
+
+// Each calls fn for each record with an interval overlapping the interval
+// start:stop by at least one position, until fn returns false. Records are
+// ordered by bin, starting with the smallest bins, and by start position
+// within a bin. It does not allocate.
+func (c *CompactIndex) Each(start, stop int, fn func(start, stop int, id uint64) bool) error {
+	start, stop, err := c.scheme.checkRange(start, stop)
+	if err != nil {
+		return err
+	}
+	last := start
+	if stop > start {
+		last = stop - 1
+	}
+
+	bins := len(c.table) / compactBinSize
+	for level, shift := range c.scheme.shifts {
+		offset := c.scheme.binOffsets[level]
+		startBin, stopBin := offset+start>>shift, offset+last>>shift
+		i := sort.Search(bins, func(i int) bool { return c.binNumber(i) >= startBin })
+		for ; i < bins && c.binNumber(i) <= stopBin; i++ {
+			for r := c.first(i); r < c.first(i+1); r++ {
+				record := c.records[r*compactRecordSize:]
+				s, e := int(binary.BigEndian.Uint64(record)), int(binary.BigEndian.Uint64(record[8:]))
+				if s >= stop {
+					break
+				}
+				if max(s, start) < min(e, stop) && !fn(s, e, binary.BigEndian.Uint64(record[16:])) {
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// AppendOverlapping appends the identifiers of all records with intervals
+// overlapping the interval start:stop by at least one position to dst and
+// returns the extended slice, ordered as with Each.
+func (c *CompactIndex) AppendOverlapping(dst []uint64, start, stop int) ([]uint64, error) {
+	ids := dst
+	err := c.Each(start, stop, func(_, _ int, id uint64) bool {
+		ids = append(ids, id)
+		return true
+	})
+	if err != nil {
+		return dst, err
+	}
+	return ids, nil
+}
+
+// Overlapping returns the identifiers of all records with intervals
+// overlapping the interval start:stop by at least one position, ordered as
+// with Each.
+func (c *CompactIndex) Overlapping(start, stop int) ([]uint64, error) {
+	return c.AppendOverlapping([]uint64{}, start, stop)
+}
+
+// Close releases the resources held by the index, such as a memory-mapped
+// file. The index must not be used after Close.
+func (c *CompactIndex) Close() error {
+	if c.close == nil {
+		return nil
+	}
+	fn := c.close
+	c.close = nil
+	return fn()
+}
@@ -0,0 +1,42 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// OpenCompactIndexFile opens the file at path in the format written by
+// WriteCompactIndex. The file is memory-mapped, so opening it takes constant
+// time regardless of its size. Call Close to unmap the file.
+func OpenCompactIndexFile(path string) (*CompactIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 || int64(int(size)) != size {
+		return nil, errors.New(fmt.Sprintf("invalid compact index size: %d", size))
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	c, err := OpenCompactIndex(data)
+	if err != nil {
+		syscall.Munmap(data)
+		return nil, err
+	}
+	c.close = func() error { return syscall.Munmap(data) }
+	return c, nil
+}
@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package binning
+
+import "os"
+
+// OpenCompactIndexFile opens the file at path in the format written by
+// WriteCompactIndex. On this platform, the file is read into memory instead
+// of memory-mapped.
+func OpenCompactIndexFile(path string) (*CompactIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return OpenCompactIndex(data)
+}
@@ -0,0 +1,131 @@
+package binning
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// compactID maps the names in the test index to identifiers.
+func compactID(name string) uint64 {
+	return uint64(name[0] - 'a')
+}
+
+func newTestCompactIndex(t *testing.T) *CompactIndex {
+	var buffer bytes.Buffer
+	n, error := WriteCompactIndex(&buffer, newTestIndex(t), compactID)
+	if error != nil {
+		t.Fatalf("WriteCompactIndex error: %v", error)
+	}
+	if n != int64(buffer.Len()) {
+		t.Errorf("WriteCompactIndex = %d, expected %d", n, buffer.Len())
+	}
+	c, error := OpenCompactIndex(buffer.Bytes())
+	if error != nil {
+		t.Fatalf("OpenCompactIndex error: %v", error)
+	}
+	return c
+}
+
+func TestCompactIndexOverlapping(t *testing.T) {
+	x := newTestIndex(t)
+	c := newTestCompactIndex(t)
+	if c.Len() != x.Len() || !equalSchemes(c.Scheme(), x.Scheme()) {
+		t.Errorf("CompactIndex Len() = %d, Scheme() = %v, expected %d, %v", c.Len(), c.Scheme(), x.Len(), x.Scheme())
+	}
+
+	for _, v := range [][2]int{{0, 1}, {99, 101}, {150, 1 << 17}, {1<<17 - 1, 1<<17 + 1}, {200, 200}, {0, 1 << 29}, {4000000, 6000000}} {
+		expected, _ := x.Overlapping(v[0], v[1])
+		ids, error := c.Overlapping(v[0], v[1])
+		if error != nil {
+			t.Errorf("Overlapping(%d, %d) error: %v", v[0], v[1], error)
+			continue
+		}
+		names := []string{}
+		for _, id := range ids {
+			names = append(names, string(rune('a'+id)))
+		}
+		if !sameElements(names, expected) {
+			t.Errorf("Overlapping(%d, %d) = %v, expected %v", v[0], v[1], names, expected)
+		}
+	}
+
+	if _, error := c.Overlapping(0, 1<<30); error == nil {
+		t.Errorf("Overlapping(0, 1<<30) did not return error")
+	}
+}
+
+func TestCompactIndexEach(t *testing.T) {
+	c := newTestCompactIndex(t)
+	calls := 0
+	c.Each(0, 1<<29, func(start, stop int, id uint64) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("Each called fn %d times after returning false, expected 1", calls)
+	}
+
+	allocations := testing.AllocsPerRun(100, func() {
+		c.Each(0, 1<<29, func(start, stop int, id uint64) bool { return true })
+	})
+	if allocations > 0 {
+		t.Errorf("Each allocated %v times, expected 0", allocations)
+	}
+}
+
+func TestOpenCompactIndexFile(t *testing.T) {
+	var buffer bytes.Buffer
+	WriteCompactIndex(&buffer, newTestIndex(t), compactID)
+	path := filepath.Join(t.TempDir(), "index.bin")
+	if error := os.WriteFile(path, buffer.Bytes(), 0o644); error != nil {
+		t.Fatal(error)
+	}
+
+	c, error := OpenCompactIndexFile(path)
+	if error != nil {
+		t.Fatalf("OpenCompactIndexFile error: %v", error)
+	}
+	ids, error := c.Overlapping(0, 10)
+	if error != nil || len(ids) != 1 || ids[0] != 0 {
+		t.Errorf("Overlapping(0, 10) = %v, %v, expected [0]", ids, error)
+	}
+	if error := c.Close(); error != nil {
+		t.Errorf("Close error: %v", error)
+	}
+
+	if _, error := OpenCompactIndexFile(filepath.Join(t.TempDir(), "missing")); error == nil {
+		t.Errorf("OpenCompactIndexFile did not return error for missing file")
+	}
+}
+
+func TestOpenCompactIndexInvalid(t *testing.T) {
+	var buffer bytes.Buffer
+	WriteCompactIndex(&buffer, newTestIndex(t), compactID)
+	valid := buffer.Bytes()
+	header := len(valid) - 6*compactRecordSize - 4*compactBinSize
+
+	corrupt := func(offset int, b byte) []byte {
+		data := bytes.Clone(valid)
+		data[offset] = b
+		return data
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"magic", corrupt(0, 'X')},
+		{"version", corrupt(len(compactMagic), 2)},
+		{"truncated", valid[:len(valid)-1]},
+		{"extended", append(bytes.Clone(valid), 0)},
+		{"bin order", corrupt(header+compactBinSize+7, 0)},
+		{"first record", corrupt(header+15, 1)},
+	}
+	for _, test := range tests {
+		if _, error := OpenCompactIndex(test.data); error == nil {
+			t.Errorf("OpenCompactIndex(%s) did not return error", test.name)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package binning
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// compiledMagic starts a compiled index.
+const compiledMagic = "BINGEN"
+
+// compiledVersion is the version of the compiled index format written by
+// CompileIndexes.
+const compiledVersion = 1
+
+// CompileIndexes returns a compiled index for the per-chromosome indexes,
+// which can be embedded in a program with go:embed and queried with
+// OpenCompiledIndex. Values are stored as the identifiers returned by id.
+//
+// The format consists of a header with the number of chromosomes and, per
+// chromosome ordered by name, its name and the length of its index,
+// followed by the index for each chromosome in the format written by
+// WriteCompactIndex.
+func CompileIndexes[T any](indexes map[string]*BinIndex[T], id func(T) uint64) ([]byte, error) {
+	names := make([]string, 0, len(indexes))
+	for name := range indexes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	header := append([]byte(compiledMagic), compiledVersion)
+	header = binary.BigEndian.AppendUint32(header, uint32(len(names)))
+	var sections bytes.Buffer
+	for _, name := range names {
+		n, err := WriteCompactIndex(&sections, indexes[name], id)
+		if err != nil {
+			return nil, err
+		}
+		header = binary.BigEndian.AppendUint32(header, uint32(len(name)))
+		header = append(header, name...)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	return append(header, sections.Bytes()...), nil
+}
+
+// CompileBED reads intervals in BED format from r and returns a compiled
+// index for them with binning scheme b for every chromosome, as with
+// CompileIndexes. The identifier of an interval is its position in the
+// input, starting at 0. Empty lines, comment lines and track and browser
+// lines are skipped.
+func CompileBED(r io.Reader, b Binning) ([]byte, error) {
+	indexes := make(map[string]*BinIndex[uint64])
+	var n uint64
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "track") || strings.HasPrefix(text, "browser") {
+			continue
+		}
+		chrom, start, stop, err := FromBEDLine(text)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("line %d: %v", line, err))
+		}
+		if indexes[chrom] == nil {
+			indexes[chrom] = NewBinIndex[uint64](b)
+		}
+		if _, err := indexes[chrom].Insert(start, stop, n); err != nil {
+			return nil, errors.New(fmt.Sprintf("line %d: %v", line, err))
+		}
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return CompileIndexes(indexes, func(id uint64) uint64 { return id })
+}
+
+// A CompiledIndex queries a compiled index created by CompileIndexes or
+// CompileBED directly on its binary representation.
+type CompiledIndex struct {
+	names   []string
+	indexes map[string]*CompactIndex
+}
+
+// OpenCompiledIndex returns a CompiledIndex for the compiled index in data,
+// such as a byte slice embedded with go:embed. The data is used in place and
+// must not be modified while the CompiledIndex is in use.
+func OpenCompiledIndex(data []byte) (*CompiledIndex, error) {
+	prefix := len(compiledMagic) + 5
+	if len(data) < prefix || string(data[:len(compiledMagic)]) != compiledMagic {
+		return nil, errors.New("not a compiled index")
+	}
+	if version := data[len(compiledMagic)]; version != compiledVersion {
+		return nil, errors.New(fmt.Sprintf("unsupported compiled index version: %d", version))
+	}
+	count := int(binary.BigEndian.Uint32(data[len(compiledMagic)+1:]))
+	header := data[prefix:]
+
+	c := &CompiledIndex{indexes: make(map[string]*CompactIndex)}
+	var lengths []uint64
+	for i := 0; i < count; i++ {
+		if len(header) < 4 {
+			return nil, errors.New("truncated compiled index")
+		}
+		length := uint64(binary.BigEndian.Uint32(header))
+		if uint64(len(header)) < 12+length {
+			return nil, errors.New("truncated compiled index")
+		}
+		name := string(header[4 : 4+length])
+		if _, ok := c.indexes[name]; ok {
+			return nil, errors.New(fmt.Sprintf("duplicate chromosome in compiled index: %q", name))
+		}
+		c.names = append(c.names, name)
+		c.indexes[name] = nil
+		lengths = append(lengths, binary.BigEndian.Uint64(header[4+length:]))
+		header = header[12+length:]
+	}
+
+	sections := header
+	for i, name := range c.names {
+		if lengths[i] > uint64(len(sections)) {
+			return nil, errors.New("truncated compiled index")
+		}
+		index, err := OpenCompactIndex(sections[:lengths[i]])
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("chromosome %q: %v", name, err))
+		}
+		c.indexes[name] = index
+		sections = sections[lengths[i]:]
+	}
+	if len(sections) > 0 {
+		return nil, errors.New("trailing data in compiled index")
+	}
+	return c, nil
+}
+
+// Chromosomes returns the names of the chromosomes in the index, ordered by
+// name.
+func (c *CompiledIndex) Chromosomes() []string {
+	return slices.Clone(c.names)
+}
+
+// Len returns the number of records in the index.
+func (c *CompiledIndex) Len() int {
+	n := 0
+	for _, index := range c.indexes {
+		n += index.Len()
+	}
+	return n
+}
+
+// Each calls fn for each record on chromosome chrom with an interval
+// overlapping the interval start:stop by at least one position, as with
+// CompactIndex.Each. A chromosome without records in the index has no
+// overlapping records. It does not allocate.
+func (c *CompiledIndex) Each(chrom string, start, stop int, fn func(start, stop int, id uint64) bool) error {
+	index, ok := c.indexes[chrom]
+	if !ok {
+		return nil
+	}
+	return index.Each(start, stop, fn)
+}
+
+// Any reports whether any record on chromosome chrom has an interval
+// overlapping the interval start:stop by at least one position. It does not
+// allocate.
+func (c *CompiledIndex) Any(chrom string, start, stop int) (bool, error) {
+	found := false
+	err := c.Each(chrom, start, stop, func(_, _ int, _ uint64) bool {
+		found = true
+		return false
+	})
+	return found, err
+}
@@ -0,0 +1,97 @@
+package binning
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const compiledBED = `track name=blacklist
+# Comment.
+chr1	100	200	a
+chr2	0	10	b
+
+chr1	150	1000000	c
+`
+
+func TestCompileBED(t *testing.T) {
+	data, error := CompileBED(strings.NewReader(compiledBED), StandardBinning())
+	if error != nil {
+		t.Fatalf("CompileBED error: %v", error)
+	}
+	c, error := OpenCompiledIndex(data)
+	if error != nil {
+		t.Fatalf("OpenCompiledIndex error: %v", error)
+	}
+	if names := c.Chromosomes(); !reflect.DeepEqual(names, []string{"chr1", "chr2"}) || c.Len() != 3 {
+		t.Errorf("Chromosomes() = %v, Len() = %d, expected [chr1 chr2], 3", names, c.Len())
+	}
+
+	tests := []struct {
+		chrom       string
+		start, stop int
+		ids         []uint64
+	}{
+		{"chr1", 0, 120, []uint64{0}},
+		{"chr1", 180, 190, []uint64{0, 2}},
+		{"chr1", 500000, 500001, []uint64{2}},
+		{"chr2", 10, 20, nil},
+		{"chr3", 0, 100, nil},
+	}
+	for _, test := range tests {
+		var ids []uint64
+		error := c.Each(test.chrom, test.start, test.stop, func(_, _ int, id uint64) bool {
+			ids = append(ids, id)
+			return true
+		})
+		if error != nil || !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("Each(%q, %d, %d) = %v, %v, expected %v", test.chrom, test.start, test.stop, ids, error, test.ids)
+		}
+		if found, _ := c.Any(test.chrom, test.start, test.stop); found != (len(test.ids) > 0) {
+			t.Errorf("Any(%q, %d, %d) = %v", test.chrom, test.start, test.stop, found)
+		}
+	}
+
+	allocations := testing.AllocsPerRun(100, func() {
+		c.Any("chr1", 180, 190)
+	})
+	if allocations > 0 {
+		t.Errorf("Any allocated %v times, expected 0", allocations)
+	}
+}
+
+func TestCompileBEDInvalid(t *testing.T) {
+	for _, bed := range []string{"chr1\t100\n", "chr1\t200\t100\n", "chr1\t0\t1000000000\n"} {
+		if _, error := CompileBED(strings.NewReader(bed), StandardBinning()); error == nil {
+			t.Errorf("CompileBED(%q) did not return error", bed)
+		}
+	}
+}
+
+func TestOpenCompiledIndexInvalid(t *testing.T) {
+	valid, _ := CompileBED(strings.NewReader(compiledBED), StandardBinning())
+	empty, _ := CompileIndexes(map[string]*BinIndex[uint64]{}, func(id uint64) uint64 { return id })
+	if c, error := OpenCompiledIndex(empty); error != nil || c.Len() != 0 {
+		t.Errorf("OpenCompiledIndex(empty) = %v, %v", c, error)
+	}
+
+	version := bytes.Clone(valid)
+	version[len(compiledMagic)] = 2
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"magic", append([]byte("BINCMP"), valid[6:]...)},
+		{"version", version},
+		{"truncated header", valid[:20]},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing", append(bytes.Clone(valid), 0)},
+	}
+	for _, test := range tests {
+		if _, error := OpenCompiledIndex(test.data); error == nil {
+			t.Errorf("OpenCompiledIndex(%s) did not return error", test.name)
+		}
+	}
+}
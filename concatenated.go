@@ -0,0 +1,99 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A ConcatenatedBinning implements interval binning for a genome with all
+// chromosomes laid out end-to-end on a single absolute axis, in the order
+// they were given to NewGenomeBinning. Absolute positions are binned with a
+// single scheme on positions of type int64.
+type ConcatenatedBinning struct {
+	Scheme  Binning64
+	genome  GenomeBinning
+	offsets []int64
+}
+
+// NewConcatenatedBinning creates a new binning for genome g on a single
+// absolute axis. The scheme has smallest bins of 2^17 positions, 8 times
+// larger bins on each next level, and as many levels as needed to cover the
+// concatenated chromosomes.
+func NewConcatenatedBinning(g GenomeBinning) (ConcatenatedBinning, error) {
+	offsets := make([]int64, len(g.chromosomes)+1)
+	for i, c := range g.chromosomes {
+		if int64(c.Length) > math.MaxInt64-offsets[i] {
+			return ConcatenatedBinning{}, errors.New("invalid genome: total length does not fit in int64")
+		}
+		offsets[i+1] = offsets[i] + int64(c.Length)
+	}
+	depth := 0
+	for 17+3*depth < 62 && int64(1)<<uint(17+3*depth) < offsets[len(g.chromosomes)] {
+		depth++
+	}
+	if int64(1)<<uint(17+3*depth) < offsets[len(g.chromosomes)] {
+		return ConcatenatedBinning{}, errors.New(fmt.Sprintf("invalid genome: total length %d is too large", offsets[len(g.chromosomes)]))
+	}
+	return ConcatenatedBinning{Scheme: NewCSIScheme[int64](17, depth), genome: g, offsets: offsets}, nil
+}
+
+// Length returns the total length of the concatenated chromosomes.
+func (c ConcatenatedBinning) Length() int64 {
+	return c.offsets[len(c.offsets)-1]
+}
+
+// Absolute returns the absolute position of position pos on chromosome chrom.
+// Position pos may be equal to the chromosome length, so that interval stops
+// can be converted.
+func (c ConcatenatedBinning) Absolute(chrom string, pos int) (int64, error) {
+	i, err := c.genome.lookup(chrom)
+	if err != nil {
+		return 0, err
+	}
+	if chromosome := c.genome.chromosomes[i]; pos < 0 || pos > chromosome.Length {
+		return 0, &ChromosomeRangeError{Chrom: chromosome.Name, Start: pos, Stop: pos, Length: chromosome.Length}
+	}
+	return c.offsets[i] + int64(pos), nil
+}
+
+// Relative returns the chromosome and position on that chromosome of absolute
+// position pos.
+func (c ConcatenatedBinning) Relative(pos int64) (string, int, error) {
+	if pos < 0 || pos >= c.Length() {
+		return "", 0, errors.New(fmt.Sprintf("invalid absolute position: %d (total length is %d)", pos, c.Length()))
+	}
+	i := sort.Search(len(c.offsets), func(i int) bool { return c.offsets[i] > pos }) - 1
+	return c.genome.chromosomes[i].Name, int(pos - c.offsets[i]), nil
+}
+
+// absolute returns the absolute interval for the interval start:stop on
+// chromosome chrom.
+func (c ConcatenatedBinning) absolute(chrom string, start, stop int) (int64, int64, error) {
+	if _, err := c.genome.checkRange(chrom, start, stop); err != nil {
+		return 0, 0, err
+	}
+	offset := c.offsets[c.genome.index[chrom]]
+	return offset + int64(start), offset + int64(stop), nil
+}
+
+// Assign returns the smallest bin fitting the interval start:stop on
+// chromosome chrom.
+func (c ConcatenatedBinning) Assign(chrom string, start, stop int) (int64, error) {
+	start64, stop64, err := c.absolute(chrom, start, stop)
+	if err != nil {
+		return 0, err
+	}
+	return c.Scheme.Assign(start64, stop64)
+}
+
+// Overlapping returns bins for all intervals overlapping the interval
+// start:stop on chromosome chrom by at least one position.
+func (c ConcatenatedBinning) Overlapping(chrom string, start, stop int) ([]int64, error) {
+	start64, stop64, err := c.absolute(chrom, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return c.Scheme.Overlapping(start64, stop64)
+}
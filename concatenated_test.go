@@ -0,0 +1,69 @@
+package binning
+
+import "testing"
+
+func TestConcatenatedBinning(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	c, error := NewConcatenatedBinning(g)
+	if error != nil {
+		t.Fatalf("NewConcatenatedBinning() returned error: %v", error)
+	}
+	if length := c.Length(); length != 248956422+46709983+16569 {
+		t.Errorf("Length() = %d, expected %d", length, 248956422+46709983+16569)
+	}
+	if c.Scheme.MaxPosition != 1<<29-1 {
+		t.Errorf("Scheme.MaxPosition = %d, expected %d", c.Scheme.MaxPosition, 1<<29-1)
+	}
+	for _, v := range []struct {
+		chrom string
+		pos   int
+		abs   int64
+	}{
+		{"chr1", 0, 0},
+		{"chr1", 248956421, 248956421},
+		{"chr21", 0, 248956422},
+		{"chrM", 16568, 248956422 + 46709983 + 16568},
+	} {
+		if abs, error := c.Absolute(v.chrom, v.pos); error != nil {
+			t.Errorf("Absolute(%q, %d) returned error: %v", v.chrom, v.pos, error)
+		} else if abs != v.abs {
+			t.Errorf("Absolute(%q, %d) = %d, expected %d", v.chrom, v.pos, abs, v.abs)
+		}
+		if chrom, pos, error := c.Relative(v.abs); error != nil {
+			t.Errorf("Relative(%d) returned error: %v", v.abs, error)
+		} else if chrom != v.chrom || pos != v.pos {
+			t.Errorf("Relative(%d) = (%q, %d), expected (%q, %d)", v.abs, chrom, pos, v.chrom, v.pos)
+		}
+	}
+	if abs, error := c.Absolute("chrM", 16570); error == nil {
+		t.Errorf("Absolute(%q, %d) = %d, expected error", "chrM", 16570, abs)
+	}
+	if chrom, pos, error := c.Relative(c.Length()); error == nil {
+		t.Errorf("Relative(%d) = (%q, %d), expected error", c.Length(), chrom, pos)
+	}
+	if bin, error := c.Assign("chr21", 0, 1); error != nil {
+		t.Errorf("Assign(%q, %d, %d) returned error: %v", "chr21", 0, 1, error)
+	} else if bin != 585+248956422>>17 {
+		t.Errorf("Assign(%q, %d, %d) = %d, expected %d", "chr21", 0, 1, bin, 585+248956422>>17)
+	}
+	if bins, error := c.Overlapping("chrM", 0, 16569); error != nil {
+		t.Errorf("Overlapping(%q, %d, %d) returned error: %v", "chrM", 0, 16569, error)
+	} else if len(bins) != 5 {
+		t.Errorf("len(Overlapping(%q, %d, %d)) = %d, expected %d", "chrM", 0, 16569, len(bins), 5)
+	}
+}
+
+func TestConcatenatedBinningLarge(t *testing.T) {
+	c, error := NewConcatenatedBinning(GRCh38())
+	if error != nil {
+		t.Fatalf("NewConcatenatedBinning() returned error: %v", error)
+	}
+	if c.Scheme.MaxPosition != 1<<32-1 {
+		t.Errorf("Scheme.MaxPosition = %d, expected %d", c.Scheme.MaxPosition, int64(1<<32-1))
+	}
+	if chrom, pos, error := c.Relative(c.Length() - 1); error != nil {
+		t.Errorf("Relative(%d) returned error: %v", c.Length()-1, error)
+	} else if chrom != "chrM" || pos != 16568 {
+		t.Errorf("Relative(%d) = (%q, %d), expected (%q, %d)", c.Length()-1, chrom, pos, "chrM", 16568)
+	}
+}
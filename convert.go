@@ -0,0 +1,57 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FromOneBasedClosed converts the one-based closed interval start..end, as
+// used in VCF and GFF files, to the zero-based open-ended interval used in
+// this package. It returns an error if the interval does not start at a
+// positive position or ends before it starts.
+func FromOneBasedClosed[T Integer](start, end T) (T, T, error) {
+	if start < 1 || end < start {
+		return 0, 0, errors.New(fmt.Sprintf("invalid one-based interval: %d-%d", start, end))
+	}
+	return start - 1, end, nil
+}
+
+// ToOneBasedClosed converts the zero-based open-ended interval start:stop to
+// a one-based closed interval, as used in VCF and GFF files. It returns an
+// error if the interval starts at a negative position or is empty, since
+// empty intervals cannot be represented as one-based closed intervals.
+func ToOneBasedClosed[T Integer](start, stop T) (T, T, error) {
+	if start < 0 || stop <= start {
+		return 0, 0, errors.New(fmt.Sprintf("invalid interval: %d-%d", start, stop))
+	}
+	return start + 1, stop, nil
+}
+
+// FromBEDLine returns the chromosome and the zero-based open-ended interval
+// in the first three fields of a line from a BED file. It returns an error if
+// the line does not have at least three tab-separated fields or does not
+// describe a valid interval. Header and comment lines are not accepted.
+func FromBEDLine(line string) (string, int, int, error) {
+	fields := strings.Split(strings.TrimRight(line, "\r\n"), "\t")
+	if len(fields) < 3 {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid BED line: expected at least 3 fields, found %d", len(fields)))
+	}
+	chrom := fields[0]
+	if chrom == "" || strings.HasPrefix(chrom, "#") || chrom == "track" || chrom == "browser" {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid BED line: invalid chromosome %q", chrom))
+	}
+	start, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid BED line: invalid start %q", fields[1]))
+	}
+	stop, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid BED line: invalid end %q", fields[2]))
+	}
+	if start < 0 || stop < start {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid BED line: invalid interval %d-%d", start, stop))
+	}
+	return chrom, start, stop, nil
+}
@@ -0,0 +1,64 @@
+package binning
+
+import "testing"
+
+func TestFromOneBasedClosed(t *testing.T) {
+	for _, v := range []struct{ start, end, expectedStart, expectedStop int }{
+		{1, 1, 0, 1},
+		{1, 100, 0, 100},
+		{5, 5, 4, 5},
+	} {
+		if start, stop, error := FromOneBasedClosed(v.start, v.end); error != nil {
+			t.Errorf("FromOneBasedClosed(%d, %d) returned error: %v", v.start, v.end, error)
+		} else if start != v.expectedStart || stop != v.expectedStop {
+			t.Errorf("FromOneBasedClosed(%d, %d) = (%d, %d), expected (%d, %d)", v.start, v.end, start, stop, v.expectedStart, v.expectedStop)
+		} else if start, end, error := ToOneBasedClosed(start, stop); error != nil {
+			t.Errorf("ToOneBasedClosed(%d, %d) returned error: %v", v.expectedStart, v.expectedStop, error)
+		} else if start != v.start || end != v.end {
+			t.Errorf("ToOneBasedClosed(%d, %d) = (%d, %d), expected (%d, %d)", v.expectedStart, v.expectedStop, start, end, v.start, v.end)
+		}
+	}
+	for _, v := range []struct{ start, end int }{{0, 1}, {-1, 1}, {5, 4}} {
+		if start, stop, error := FromOneBasedClosed(v.start, v.end); error == nil {
+			t.Errorf("FromOneBasedClosed(%d, %d) = (%d, %d), expected error", v.start, v.end, start, stop)
+		}
+	}
+	for _, v := range []struct{ start, stop int }{{0, 0}, {-1, 1}, {5, 4}} {
+		if start, end, error := ToOneBasedClosed(v.start, v.stop); error == nil {
+			t.Errorf("ToOneBasedClosed(%d, %d) = (%d, %d), expected error", v.start, v.stop, start, end)
+		}
+	}
+}
+
+func TestFromBEDLine(t *testing.T) {
+	for _, v := range []struct {
+		line        string
+		chrom       string
+		start, stop int
+	}{
+		{"chr1\t0\t100", "chr1", 0, 100},
+		{"chr1\t74012\t173034\tfeature\t0\t+\n", "chr1", 74012, 173034},
+		{"chrX\t5\t5\r\n", "chrX", 5, 5},
+	} {
+		if chrom, start, stop, error := FromBEDLine(v.line); error != nil {
+			t.Errorf("FromBEDLine(%q) returned error: %v", v.line, error)
+		} else if chrom != v.chrom || start != v.start || stop != v.stop {
+			t.Errorf("FromBEDLine(%q) = (%q, %d, %d), expected (%q, %d, %d)", v.line, chrom, start, stop, v.chrom, v.start, v.stop)
+		}
+	}
+	for _, line := range []string{
+		"",
+		"chr1\t0",
+		"chr1 0 100",
+		"#chr1\t0\t100",
+		"track\tname=x\tdescription=y",
+		"chr1\t-1\t100",
+		"chr1\t100\t99",
+		"chr1\tabc\t100",
+		"\t0\t100",
+	} {
+		if chrom, start, stop, error := FromBEDLine(line); error == nil {
+			t.Errorf("FromBEDLine(%q) = (%q, %d, %d), expected error", line, chrom, start, stop)
+		}
+	}
+}
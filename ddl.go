@@ -0,0 +1,145 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// regionColumnTypes returns the types of the chromosome, start, stop and bin
+// columns of a region table in dialect d.
+func regionColumnTypes(d Dialect) [4]string {
+	switch d {
+	case SQLite:
+		return [4]string{"TEXT NOT NULL", "INTEGER NOT NULL", "INTEGER NOT NULL", "INTEGER NOT NULL"}
+	case MySQL:
+		return [4]string{"VARCHAR(255) NOT NULL", "BIGINT NOT NULL", "BIGINT NOT NULL", "INT UNSIGNED NOT NULL"}
+	case BigQuery:
+		return [4]string{"STRING NOT NULL", "INT64 NOT NULL", "INT64 NOT NULL", "INT64 NOT NULL"}
+	case ClickHouse:
+		return [4]string{"LowCardinality(String)", "UInt64", "UInt64", "UInt32"}
+	default:
+		return [4]string{"VARCHAR(255) NOT NULL", "BIGINT NOT NULL", "BIGINT NOT NULL", "INTEGER NOT NULL"}
+	}
+}
+
+// CreateRegionTableSQL returns SQL statements creating table with columns for
+// the chromosome, start and stop positions, and bin of a region followed by
+// columns, and an index on the chromosome, bin and start columns in that
+// order. Queries with the condition of OverlappingRegionSQLArgs use this
+// index to select bins on one chromosome and limit the rows within a bin by
+// start position. BigQuery has no indexes, so the table is clustered on
+// these columns instead. For ClickHouse, the table is a MergeTree ordered by
+// these columns. With the WithRangeTypes option, the statements also create
+// a GiST index on the chromosome and the range of the start and stop columns,
+// which needs the btree_gist extension. The statements should be executed one
+// by one.
+func CreateRegionTableSQL(table string, columns []TableColumn, options ...SQLOption) ([]string, error) {
+	c := sqlOptions(options)
+	if c.ranges && c.dialect != PostgreSQL {
+		return nil, errors.New("range types require the PostgreSQL dialect")
+	}
+	name, err := c.dialect.Quote(table)
+	if err != nil {
+		return nil, err
+	}
+
+	types := regionColumnTypes(c.dialect)
+	all := make([]TableColumn, 0, 4+len(columns))
+	for i, column := range c.columns {
+		all = append(all, TableColumn{column, types[i]})
+	}
+	all = append(all, columns...)
+
+	var s strings.Builder
+	s.WriteString("CREATE TABLE " + name + " (\n")
+	for i, column := range all {
+		quoted, err := c.dialect.Quote(column.Name)
+		if err != nil {
+			return nil, err
+		}
+		if column.Type == "" {
+			return nil, errors.New(fmt.Sprintf("no type for column %q", column.Name))
+		}
+		s.WriteString("  " + quoted + " " + column.Type)
+		if i < len(all)-1 {
+			s.WriteByte(',')
+		}
+		s.WriteByte('\n')
+	}
+	s.WriteString(")")
+
+	key, err := quoteColumns(c.dialect, []string{c.columns[0], c.columns[3], c.columns[1]})
+	if err != nil {
+		return nil, err
+	}
+	if c.dialect == BigQuery {
+		return []string{s.String() + "\nCLUSTER BY " + key}, nil
+	}
+	if c.dialect == ClickHouse {
+		return []string{s.String() + "\nENGINE = MergeTree\nORDER BY (" + key + ")"}, nil
+	}
+	index, err := CreateRegionIndexSQL(table, options...)
+	if err != nil {
+		return nil, err
+	}
+	statements := []string{s.String(), index}
+	if !c.ranges {
+		return statements, nil
+	}
+
+	parts := strings.Split(table, ".")
+	index, _ = c.dialect.Quote(parts[len(parts)-1] + "_chrom_range")
+	chrom, _ := c.dialect.Quote(c.columns[0])
+	start, _ := c.dialect.Quote(c.columns[1])
+	stop, _ := c.dialect.Quote(c.columns[2])
+	return append(statements,
+		"CREATE EXTENSION IF NOT EXISTS btree_gist",
+		"CREATE INDEX "+index+" ON "+name+" USING gist ("+chrom+", ("+int8range(start, stop)+"))"), nil
+}
+
+// AddBinColumnSQL returns an SQL statement adding a bin column to an existing
+// table of regions, to be populated with BackfillBins. The column allows NULL
+// values, which mark rows without a bin yet. As for BackfillBins, ClickHouse
+// is not supported.
+func AddBinColumnSQL(table string, options ...SQLOption) (string, error) {
+	c := sqlOptions(options)
+	if c.dialect == ClickHouse {
+		return "", errors.New("dialect does not support updates")
+	}
+	name, err := c.dialect.Quote(table)
+	if err != nil {
+		return "", err
+	}
+	bin, err := c.dialect.Quote(c.columns[3])
+	if err != nil {
+		return "", err
+	}
+	columnType := strings.TrimSuffix(regionColumnTypes(c.dialect)[3], " NOT NULL")
+	return "ALTER TABLE " + name + " ADD COLUMN " + bin + " " + columnType, nil
+}
+
+// CreateRegionIndexSQL returns an SQL statement creating the index of
+// CreateRegionTableSQL on the chromosome, bin and start columns of an
+// existing table of regions, such as after populating its bin column with
+// BackfillBins.
+func CreateRegionIndexSQL(table string, options ...SQLOption) (string, error) {
+	c := sqlOptions(options)
+	if c.dialect == BigQuery || c.dialect == ClickHouse {
+		return "", errors.New("dialect does not support indexes")
+	}
+	name, err := c.dialect.Quote(table)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(table, ".")
+	index, err := c.dialect.Quote(parts[len(parts)-1] + "_chrom_bin_start")
+	if err != nil {
+		return "", err
+	}
+	key, err := quoteColumns(c.dialect, []string{c.columns[0], c.columns[3], c.columns[1]})
+	if err != nil {
+		return "", err
+	}
+	return "CREATE INDEX " + index + " ON " + name + " (" + key + ")", nil
+}
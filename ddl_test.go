@@ -0,0 +1,137 @@
+package binning
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCreateRegionTableSQL(t *testing.T) {
+	statements, error := CreateRegionTableSQL("public.genes", []TableColumn{{"name", "TEXT"}}, WithDialect(PostgreSQL))
+	if error != nil {
+		t.Fatalf("CreateRegionTableSQL error: %v", error)
+	}
+	expected := []string{
+		"CREATE TABLE \"public\".\"genes\" (\n" +
+			"  \"chrom\" VARCHAR(255) NOT NULL,\n" +
+			"  \"start\" BIGINT NOT NULL,\n" +
+			"  \"stop\" BIGINT NOT NULL,\n" +
+			"  \"bin\" INTEGER NOT NULL,\n" +
+			"  \"name\" TEXT\n" +
+			")",
+		`CREATE INDEX "genes_chrom_bin_start" ON "public"."genes" ("chrom", "bin", "start")`,
+	}
+	if !slices.Equal(statements, expected) {
+		t.Errorf("CreateRegionTableSQL = %q, expected %q", statements, expected)
+	}
+
+	statements, _ = CreateRegionTableSQL("genes", nil, WithDialect(SQLite), WithRegionColumns("c", "s", "e", "b"))
+	expected = []string{
+		"CREATE TABLE \"genes\" (\n" +
+			"  \"c\" TEXT NOT NULL,\n" +
+			"  \"s\" INTEGER NOT NULL,\n" +
+			"  \"e\" INTEGER NOT NULL,\n" +
+			"  \"b\" INTEGER NOT NULL\n" +
+			")",
+		`CREATE INDEX "genes_chrom_bin_start" ON "genes" ("c", "b", "s")`,
+	}
+	if !slices.Equal(statements, expected) {
+		t.Errorf("CreateRegionTableSQL = %q, expected %q", statements, expected)
+	}
+
+	if _, error := CreateRegionTableSQL("", nil); error == nil {
+		t.Errorf("CreateRegionTableSQL without table did not return error")
+	}
+	if _, error := CreateRegionTableSQL("genes", []TableColumn{{"name", ""}}); error == nil {
+		t.Errorf("CreateRegionTableSQL with column without type did not return error")
+	}
+}
+
+func TestCreateRegionTableSQLRangeTypes(t *testing.T) {
+	statements, error := CreateRegionTableSQL("genes", nil, WithDialect(PostgreSQL), WithRangeTypes())
+	if error != nil {
+		t.Fatalf("CreateRegionTableSQL error: %v", error)
+	}
+	expected := []string{
+		"CREATE EXTENSION IF NOT EXISTS btree_gist",
+		`CREATE INDEX "genes_chrom_range" ON "genes" USING gist ("chrom", (int8range("start", "stop")))`,
+	}
+	if len(statements) != 4 || !slices.Equal(statements[2:], expected) {
+		t.Errorf("CreateRegionTableSQL = %q, expected range index statements %q", statements, expected)
+	}
+
+	for _, d := range []Dialect{DefaultDialect, BigQuery, ClickHouse} {
+		if _, error := CreateRegionTableSQL("genes", nil, WithDialect(d), WithRangeTypes()); error == nil || error.Error() != "range types require the PostgreSQL dialect" {
+			t.Errorf("CreateRegionTableSQL with range types for dialect %v returned error %v, expected range types error", d, error)
+		}
+	}
+}
+
+func TestCreateRegionTableSQLBigQuery(t *testing.T) {
+	statements, error := CreateRegionTableSQL("genomics.genes", nil, WithDialect(BigQuery))
+	if error != nil {
+		t.Fatalf("CreateRegionTableSQL error: %v", error)
+	}
+	expected := []string{
+		"CREATE TABLE `genomics`.`genes` (\n" +
+			"  `chrom` STRING NOT NULL,\n" +
+			"  `start` INT64 NOT NULL,\n" +
+			"  `stop` INT64 NOT NULL,\n" +
+			"  `bin` INT64 NOT NULL\n" +
+			")\n" +
+			"CLUSTER BY `chrom`, `bin`, `start`",
+	}
+	if !slices.Equal(statements, expected) {
+		t.Errorf("CreateRegionTableSQL = %q, expected %q", statements, expected)
+	}
+}
+
+func TestCreateRegionTableSQLClickHouse(t *testing.T) {
+	statements, error := CreateRegionTableSQL("variants", []TableColumn{{"ref", "String"}}, WithDialect(ClickHouse))
+	if error != nil {
+		t.Fatalf("CreateRegionTableSQL error: %v", error)
+	}
+	expected := []string{
+		"CREATE TABLE `variants` (\n" +
+			"  `chrom` LowCardinality(String),\n" +
+			"  `start` UInt64,\n" +
+			"  `stop` UInt64,\n" +
+			"  `bin` UInt32,\n" +
+			"  `ref` String\n" +
+			")\n" +
+			"ENGINE = MergeTree\n" +
+			"ORDER BY (`chrom`, `bin`, `start`)",
+	}
+	if !slices.Equal(statements, expected) {
+		t.Errorf("CreateRegionTableSQL = %q, expected %q", statements, expected)
+	}
+}
+
+func TestAddBinColumnSQL(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{PostgreSQL, `ALTER TABLE "genes" ADD COLUMN "bin" INTEGER`},
+		{MySQL, "ALTER TABLE `genes` ADD COLUMN `bin` INT UNSIGNED"},
+		{BigQuery, "ALTER TABLE `genes` ADD COLUMN `bin` INT64"},
+	}
+	for _, test := range tests {
+		statement, error := AddBinColumnSQL("genes", WithDialect(test.dialect))
+		if error != nil || statement != test.expected {
+			t.Errorf("AddBinColumnSQL = %q, %v, expected %q", statement, error, test.expected)
+		}
+	}
+	if statement, error := AddBinColumnSQL("genes", WithDialect(ClickHouse)); error == nil {
+		t.Errorf("AddBinColumnSQL for ClickHouse = %q, expected error", statement)
+	}
+}
+
+func TestCreateRegionIndexSQL(t *testing.T) {
+	statement, error := CreateRegionIndexSQL("public.genes", WithDialect(PostgreSQL))
+	if expected := `CREATE INDEX "genes_chrom_bin_start" ON "public"."genes" ("chrom", "bin", "start")`; error != nil || statement != expected {
+		t.Errorf("CreateRegionIndexSQL = %q, %v, expected %q", statement, error, expected)
+	}
+	if _, error := CreateRegionIndexSQL("genes", WithDialect(BigQuery)); error == nil {
+		t.Errorf("CreateRegionIndexSQL for BigQuery did not return error")
+	}
+}
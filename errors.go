@@ -0,0 +1,143 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrOutOfRange is matched by errors.Is for any OutOfRangeError.
+	ErrOutOfRange = errors.New("interval out of range")
+
+	// ErrInvalidBin is matched by errors.Is for any InvalidBinError.
+	ErrInvalidBin = errors.New("not a valid bin number")
+
+	// ErrEmptyInterval is matched by errors.Is for any EmptyIntervalError.
+	ErrEmptyInterval = errors.New("empty or inverted interval")
+
+	// ErrInvalidScheme is matched by errors.Is for any InvalidSchemeError.
+	ErrInvalidScheme = errors.New("invalid binning scheme")
+
+	// ErrContigNotIndexed is matched by errors.Is for any
+	// ContigNotIndexedError.
+	ErrContigNotIndexed = errors.New("contig not indexed")
+
+	// ErrBinMismatch is matched by errors.Is for any BinMismatchError.
+	ErrBinMismatch = errors.New("bin does not cover interval")
+)
+
+// An OutOfRangeError records an interval that is not within the range of
+// positions supported by a binning scheme.
+type OutOfRangeError struct {
+	Start, Stop int64
+	MaxPosition int64
+}
+
+func (e *OutOfRangeError) Error() string {
+	return fmt.Sprintf("interval out of range: %d-%d (maximum position is %d)", e.Start, e.Stop, e.MaxPosition)
+}
+
+// Is reports whether target is ErrOutOfRange.
+func (e *OutOfRangeError) Is(target error) bool {
+	return target == ErrOutOfRange
+}
+
+// A ChromosomeRangeError records an interval that is not within the
+// positions of a chromosome in a genome binning. It is matched by errors.Is
+// for ErrOutOfRange.
+type ChromosomeRangeError struct {
+	Chrom       string
+	Start, Stop int
+	Length      int
+}
+
+func (e *ChromosomeRangeError) Error() string {
+	return fmt.Sprintf("interval out of range for chromosome %q: %d-%d (chromosome length is %d)", e.Chrom, e.Start, e.Stop, e.Length)
+}
+
+// Is reports whether target is ErrOutOfRange.
+func (e *ChromosomeRangeError) Is(target error) bool {
+	return target == ErrOutOfRange
+}
+
+// An InvalidBinError records a bin number that does not exist in a binning
+// scheme. If Length is not zero, the bin exists in the binning scheme but
+// starts beyond the end of a sequence of that length.
+type InvalidBinError struct {
+	Bin            int64
+	MinBin, MaxBin int64
+	Length         int64
+}
+
+func (e *InvalidBinError) Error() string {
+	if e.Length != 0 {
+		return fmt.Sprintf("not a valid bin number: %d (bin starts beyond sequence length %d)", e.Bin, e.Length)
+	}
+	return fmt.Sprintf("not a valid bin number: %d (must be >= %d and <= %d)", e.Bin, e.MinBin, e.MaxBin)
+}
+
+// Is reports whether target is ErrInvalidBin.
+func (e *InvalidBinError) Is(target error) bool {
+	return target == ErrInvalidBin
+}
+
+// An EmptyIntervalError records an empty or inverted interval passed to a
+// strict binning scheme.
+type EmptyIntervalError struct {
+	Start, Stop int64
+}
+
+func (e *EmptyIntervalError) Error() string {
+	return fmt.Sprintf("empty or inverted interval: %d-%d", e.Start, e.Stop)
+}
+
+// Is reports whether target is ErrEmptyInterval.
+func (e *EmptyIntervalError) Is(target error) bool {
+	return target == ErrEmptyInterval
+}
+
+// A BinMismatchError records a bin that does not cover an interval. Assigned
+// is the smallest bin fitting the interval.
+type BinMismatchError struct {
+	Bin, Assigned int64
+	Start, Stop   int64
+}
+
+func (e *BinMismatchError) Error() string {
+	return fmt.Sprintf("bin %d does not cover interval %d-%d (smallest bin fitting the interval is %d)", e.Bin, e.Start, e.Stop, e.Assigned)
+}
+
+// Is reports whether target is ErrBinMismatch.
+func (e *BinMismatchError) Is(target error) bool {
+	return target == ErrBinMismatch
+}
+
+// An InvalidSchemeError records an inconsistency in the parameters of a
+// binning scheme.
+type InvalidSchemeError struct {
+	Reason string
+}
+
+func (e *InvalidSchemeError) Error() string {
+	return "invalid binning scheme: " + e.Reason
+}
+
+// Is reports whether target is ErrInvalidScheme.
+func (e *InvalidSchemeError) Is(target error) bool {
+	return target == ErrInvalidScheme
+}
+
+// A ContigNotIndexedError records a query for a contig that was excluded
+// from a genome binning by a filter.
+type ContigNotIndexedError struct {
+	Chrom string
+}
+
+func (e *ContigNotIndexedError) Error() string {
+	return fmt.Sprintf("contig not indexed: %q", e.Chrom)
+}
+
+// Is reports whether target is ErrContigNotIndexed.
+func (e *ContigNotIndexedError) Is(target error) bool {
+	return target == ErrContigNotIndexed
+}
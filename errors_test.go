@@ -0,0 +1,123 @@
+package binning
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOutOfRangeError(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range invalidIntervals {
+		_, error := b.Assign(v.start, v.stop)
+		if !errors.Is(error, ErrOutOfRange) {
+			t.Errorf("Assign(%d, %d) returned error %v, expected ErrOutOfRange", v.start, v.stop, error)
+		}
+		var e *OutOfRangeError
+		if !errors.As(error, &e) {
+			t.Errorf("Assign(%d, %d) returned error %v, expected OutOfRangeError", v.start, v.stop, error)
+		} else if e.Start != int64(v.start) || e.Stop != int64(v.stop) || e.MaxPosition != 1<<29-1 {
+			t.Errorf("Assign(%d, %d) returned error %#v, expected Start %d, Stop %d, MaxPosition %d",
+				v.start, v.stop, e, v.start, v.stop, 1<<29-1)
+		}
+		if errors.Is(error, ErrInvalidBin) {
+			t.Errorf("Assign(%d, %d) returned error %v, expected no ErrInvalidBin", v.start, v.stop, error)
+		}
+	}
+}
+
+func TestInvalidBinError(t *testing.T) {
+	b := ExtendedBinning()
+	for _, bin := range []int{-1, 4680, 25746} {
+		_, _, error := b.Covered(bin)
+		if !errors.Is(error, ErrInvalidBin) {
+			t.Errorf("Covered(%d) returned error %v, expected ErrInvalidBin", bin, error)
+		}
+		var e *InvalidBinError
+		if !errors.As(error, &e) {
+			t.Errorf("Covered(%d) returned error %v, expected InvalidBinError", bin, error)
+		} else if e.Bin != int64(bin) || e.MinBin != 4681 || e.MaxBin != 25745 {
+			t.Errorf("Covered(%d) returned error %#v, expected Bin %d, MinBin %d, MaxBin %d", bin, e, bin, 4681, 25745)
+		}
+	}
+}
+
+func TestEmptyIntervalError(t *testing.T) {
+	b := StandardBinning().Strict()
+	for _, v := range []struct{ start, stop int }{{0, 0}, {10, 5}, {1<<29 - 1, 1<<29 - 1}} {
+		_, error := b.Assign(v.start, v.stop)
+		if !errors.Is(error, ErrEmptyInterval) {
+			t.Errorf("Assign(%d, %d) returned error %v, expected ErrEmptyInterval", v.start, v.stop, error)
+		}
+		var e *EmptyIntervalError
+		if !errors.As(error, &e) {
+			t.Errorf("Assign(%d, %d) returned error %v, expected EmptyIntervalError", v.start, v.stop, error)
+		} else if e.Start != int64(v.start) || e.Stop != int64(v.stop) {
+			t.Errorf("Assign(%d, %d) returned error %#v, expected Start %d, Stop %d", v.start, v.stop, e, v.start, v.stop)
+		}
+		if bins, error := b.Overlapping(v.start, v.stop); !errors.Is(error, ErrEmptyInterval) {
+			t.Errorf("Overlapping(%d, %d) = %v, %v, expected ErrEmptyInterval", v.start, v.stop, bins, error)
+		}
+	}
+	for _, v := range intervalBins {
+		if bin, error := b.Assign(v.start, v.stop); error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+	if _, error := StandardBinning().Assign(0, 0); error != nil {
+		t.Errorf("Assign(%d, %d) returned error: %v", 0, 0, error)
+	}
+}
+
+func TestInvalidSchemeError(t *testing.T) {
+	b := NewBinning(1<<29-1, []int{585, 73, 9, 1}, 17, 3)
+	if bin, error := b.Assign(0, 1<<29); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("Assign(%d, %d) = %d, %v, expected ErrInvalidScheme", 0, 1<<29, bin, error)
+	}
+
+	b = NewBinning(1<<29-1, []int{}, 17, 3)
+	if bin, error := b.Assign(0, 1); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("Assign(%d, %d) = %d, %v, expected ErrInvalidScheme", 0, 1, bin, error)
+	}
+	if start, stop, error := b.Covered(0); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("Covered(%d) = (%d, %d), %v, expected ErrInvalidScheme", 0, start, stop, error)
+	}
+
+	for _, v := range invalidSchemes {
+		_, error := NewValidatedBinning(v.maxPosition, v.binOffsets, v.shiftFirst, v.shiftNext)
+		if !errors.Is(error, ErrInvalidScheme) {
+			t.Errorf("NewValidatedBinning(%d, %v, %d, %d) returned error %v, expected ErrInvalidScheme",
+				v.maxPosition, v.binOffsets, v.shiftFirst, v.shiftNext, error)
+		}
+	}
+}
+
+func TestChromosomeRangeError(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	for _, v := range []struct {
+		chrom       string
+		start, stop int
+	}{
+		{"chrM", 0, 16570},
+		{"chrM", 16569, 16570},
+		{"chr21", 46709983, 46709983},
+		{"chr1", -1, 10},
+	} {
+		_, error := g.Assign(v.chrom, v.start, v.stop)
+		if !errors.Is(error, ErrOutOfRange) {
+			t.Errorf("Assign(%q, %d, %d) returned error %v, expected ErrOutOfRange", v.chrom, v.start, v.stop, error)
+		}
+		var e *ChromosomeRangeError
+		if !errors.As(error, &e) {
+			t.Errorf("Assign(%q, %d, %d) returned error %v, expected ChromosomeRangeError", v.chrom, v.start, v.stop, error)
+		} else if e.Chrom != v.chrom || e.Start != v.start || e.Stop != v.stop {
+			t.Errorf("Assign(%q, %d, %d) returned error %#v, expected Chrom %q, Start %d, Stop %d",
+				v.chrom, v.start, v.stop, e, v.chrom, v.start, v.stop)
+		}
+	}
+	error := &ChromosomeRangeError{Chrom: "chrM", Start: 0, Stop: 16570, Length: 16569}
+	if expected := `interval out of range for chromosome "chrM": 0-16570 (chromosome length is 16569)`; error.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", error.Error(), expected)
+	}
+}
@@ -3,6 +3,7 @@ package binning_test
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/martijnvermaat/binning"
 )
@@ -35,3 +36,41 @@ func ExampleBinning_Overlapping() {
 	fmt.Println(bins)
 	// Output: [585 73 9 1 0]
 }
+
+// This example shows querying an index of named intervals.
+func ExampleBinIndex_Overlapping() {
+	x := binning.NewBinIndex[string](binning.StandardBinning())
+	x.Insert(11873, 14409, "DDX11L1")
+	x.Insert(14403, 29570, "WASH7P")
+	x.Insert(69090, 70008, "OR4F5")
+
+	genes, error := x.Overlapping(14000, 15000)
+	if error != nil {
+		log.Fatal("BinIndex.Overlapping:", error)
+	}
+
+	fmt.Println(genes)
+	// Output: [DDX11L1 WASH7P]
+}
+
+// This example shows querying a compiled index. In a program, the compiled
+// index would typically be created with the bincompile command and embedded
+// with go:embed.
+func ExampleCompiledIndex_Any() {
+	data, error := binning.CompileBED(strings.NewReader("chr1\t10000\t10468\nchr1\t207666\t207738\n"), binning.StandardBinning())
+	if error != nil {
+		log.Fatal("CompileBED:", error)
+	}
+	blacklist, error := binning.OpenCompiledIndex(data)
+	if error != nil {
+		log.Fatal("OpenCompiledIndex:", error)
+	}
+
+	found, error := blacklist.Any("chr1", 10400, 10500)
+	if error != nil {
+		log.Fatal("CompiledIndex.Any:", error)
+	}
+
+	fmt.Println(found)
+	// Output: true
+}
@@ -0,0 +1,267 @@
+package binning
+
+import (
+	"bufio"
+	"cmp"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// A RecordSource calls fn for each record of a dataset with its interval
+// start:stop and identifier, stopping at the first error. It must produce
+// the same records each time it is called.
+type RecordSource func(fn func(start, stop int, id uint64) error) error
+
+// compactRecord is a record in the compact index format.
+type compactRecord struct {
+	start, stop int64
+	id          uint64
+}
+
+func (r compactRecord) append(data []byte) []byte {
+	data = binary.BigEndian.AppendUint64(data, uint64(r.start))
+	data = binary.BigEndian.AppendUint64(data, uint64(r.stop))
+	return binary.BigEndian.AppendUint64(data, r.id)
+}
+
+func decodeCompactRecord(data []byte) compactRecord {
+	return compactRecord{
+		start: int64(binary.BigEndian.Uint64(data)),
+		stop:  int64(binary.BigEndian.Uint64(data[8:])),
+		id:    binary.BigEndian.Uint64(data[16:]),
+	}
+}
+
+func compareCompactRecords(a, b compactRecord) int {
+	if c := cmp.Compare(a.start, b.start); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.stop, b.stop); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.id, b.id)
+}
+
+// slotBuffer is the number of records buffered per bin while writing
+// records to their slots.
+const slotBuffer = 64
+
+// BuildCompactIndexFile writes an index in the format written by
+// WriteCompactIndex to the file at path for the records from source, without
+// holding the records in memory. The first pass over source counts the
+// records per bin, the second pass writes each record to its slot in the
+// file. Records are then sorted within their bin using about memory bytes,
+// spilling sorted runs to temporary files next to the output file for bins
+// that do not fit.
+func BuildCompactIndexFile(path string, b Binning, source RecordSource, memory int) error {
+	counts := make(map[int]int)
+	err := source(func(start, stop int, _ uint64) error {
+		bin, err := b.Assign(start, stop)
+		if err != nil {
+			return err
+		}
+		counts[bin]++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	bins := make([]int, 0, len(counts))
+	total := 0
+	for bin, count := range counts {
+		bins = append(bins, bin)
+		total += count
+	}
+	slices.Sort(bins)
+
+	header, err := compactHeader(b, len(bins), total)
+	if err != nil {
+		return err
+	}
+	// The next free slot and the slot after the last slot per bin.
+	next := make(map[int]int64, len(bins))
+	end := make(map[int]int64, len(bins))
+	first := 0
+	for _, bin := range bins {
+		header = binary.BigEndian.AppendUint64(header, uint64(bin))
+		header = binary.BigEndian.AppendUint64(header, uint64(first))
+		next[bin] = int64(first)
+		first += counts[bin]
+		end[bin] = int64(first)
+	}
+	base := int64(len(header))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(header); err != nil {
+		return err
+	}
+
+	buffers := make(map[int][]byte)
+	flush := func(bin int) error {
+		buffer := buffers[bin]
+		n := int64(len(buffer) / compactRecordSize)
+		if next[bin]+n > end[bin] {
+			return errors.New("record source produced more records on the second pass")
+		}
+		if _, err := f.WriteAt(buffer, base+next[bin]*compactRecordSize); err != nil {
+			return err
+		}
+		next[bin] += n
+		buffers[bin] = buffer[:0]
+		return nil
+	}
+	err = source(func(start, stop int, id uint64) error {
+		bin, err := b.Assign(start, stop)
+		if err != nil {
+			return err
+		}
+		if _, ok := counts[bin]; !ok {
+			return errors.New("record source produced different records on the second pass")
+		}
+		buffers[bin] = compactRecord{int64(start), int64(stop), id}.append(buffers[bin])
+		if len(buffers[bin]) == slotBuffer*compactRecordSize {
+			return flush(bin)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, bin := range bins {
+		if err := flush(bin); err != nil {
+			return err
+		}
+		if next[bin] != end[bin] {
+			return errors.New("record source produced fewer records on the second pass")
+		}
+		offset := base + (end[bin]-int64(counts[bin]))*compactRecordSize
+		if err := sortSlots(f, offset, counts[bin], memory, filepath.Dir(path)); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// sortSlots sorts the n records at offset in f using about memory bytes. If
+// the records do not fit, sorted runs are written to a temporary file in dir
+// and merged back into f.
+func sortSlots(f *os.File, offset int64, n, memory int, dir string) error {
+	run := max(memory/compactRecordSize, 1)
+	records := make([]compactRecord, 0, min(n, run))
+	data := make([]byte, min(n, run)*compactRecordSize)
+
+	sortRun := func(start, count int) ([]byte, error) {
+		data := data[:count*compactRecordSize]
+		if _, err := f.ReadAt(data, offset+int64(start)*compactRecordSize); err != nil {
+			return nil, err
+		}
+		records = records[:0]
+		for i := 0; i < count; i++ {
+			records = append(records, decodeCompactRecord(data[i*compactRecordSize:]))
+		}
+		slices.SortFunc(records, compareCompactRecords)
+		data = data[:0]
+		for _, r := range records {
+			data = r.append(data)
+		}
+		return data, nil
+	}
+
+	if n <= run {
+		sorted, err := sortRun(0, n)
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteAt(sorted, offset)
+		return err
+	}
+
+	spill, err := os.CreateTemp(dir, "binning-runs-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(spill.Name())
+	defer spill.Close()
+
+	var runs mergeRuns
+	for start := 0; start < n; start += run {
+		count := min(run, n-start)
+		sorted, err := sortRun(start, count)
+		if err != nil {
+			return err
+		}
+		if _, err := spill.WriteAt(sorted, int64(start)*compactRecordSize); err != nil {
+			return err
+		}
+		section := io.NewSectionReader(spill, int64(start)*compactRecordSize, int64(count)*compactRecordSize)
+		runs = append(runs, &mergeRun{reader: bufio.NewReader(section)})
+	}
+
+	for _, r := range runs {
+		if err := r.advance(); err != nil {
+			return err
+		}
+	}
+	heap.Init(&runs)
+	writer := bufio.NewWriter(io.NewOffsetWriter(f, offset))
+	var record []byte
+	for runs.Len() > 0 {
+		r := runs[0]
+		record = r.current.append(record[:0])
+		writer.Write(record)
+		if err := r.advance(); err != nil {
+			return err
+		}
+		if r.done {
+			heap.Pop(&runs)
+		} else {
+			heap.Fix(&runs, 0)
+		}
+	}
+	return writer.Flush()
+}
+
+// A mergeRun is a sorted run of records read while merging runs.
+type mergeRun struct {
+	reader  *bufio.Reader
+	current compactRecord
+	done    bool
+}
+
+func (r *mergeRun) advance() error {
+	var data [compactRecordSize]byte
+	if _, err := io.ReadFull(r.reader, data[:]); err == io.EOF {
+		r.done = true
+		return nil
+	} else if err != nil {
+		return errors.New(fmt.Sprintf("reading sorted run: %v", err))
+	}
+	r.current = decodeCompactRecord(data[:])
+	return nil
+}
+
+// mergeRuns implements heap.Interface ordering runs by their current record.
+type mergeRuns []*mergeRun
+
+func (h mergeRuns) Len() int           { return len(h) }
+func (h mergeRuns) Less(i, j int) bool { return compareCompactRecords(h[i].current, h[j].current) < 0 }
+func (h mergeRuns) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *mergeRuns) Push(x any)        { *h = append(*h, x.(*mergeRun)) }
+func (h *mergeRuns) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
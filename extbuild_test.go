@@ -0,0 +1,83 @@
+package binning
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// sliceSource returns a RecordSource for intervals with their position as
+// identifier.
+func sliceSource(intervals []Interval) RecordSource {
+	return func(fn func(start, stop int, id uint64) error) error {
+		for i, v := range intervals {
+			if err := fn(v.Start, v.Stop, uint64(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestBuildCompactIndexFile(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	intervals := make([]Interval, 1000)
+	for i := range intervals {
+		start := random.Intn(1 << 20)
+		intervals[i] = Interval{start, start + 1 + random.Intn(1<<(random.Intn(4)*5))}
+	}
+
+	for _, memory := range []int{1 << 20, 100, 0} {
+		path := filepath.Join(t.TempDir(), "index")
+		if error := BuildCompactIndexFile(path, StandardBinning(), sliceSource(intervals), memory); error != nil {
+			t.Fatalf("BuildCompactIndexFile(%d) error: %v", memory, error)
+		}
+		data, _ := os.ReadFile(path)
+		c, error := OpenCompactIndex(data)
+		if error != nil {
+			t.Fatalf("OpenCompactIndex error: %v", error)
+		}
+		if c.Len() != len(intervals) {
+			t.Errorf("Len() = %d, expected %d", c.Len(), len(intervals))
+		}
+		for _, v := range [][2]int{{0, 1}, {1000, 2000}, {1 << 19, 1<<19 + 1}, {0, 1 << 21}} {
+			ids, error := c.Overlapping(v[0], v[1])
+			if error != nil {
+				t.Fatalf("Overlapping(%d, %d) error: %v", v[0], v[1], error)
+			}
+			expected := []uint64{}
+			for i, w := range intervals {
+				if max(w.Start, v[0]) < min(w.Stop, v[1]) {
+					expected = append(expected, uint64(i))
+				}
+			}
+			slices.Sort(ids)
+			if !slices.Equal(ids, expected) {
+				t.Errorf("memory %d: Overlapping(%d, %d) = %d ids, expected %d", memory, v[0], v[1], len(ids), len(expected))
+			}
+		}
+		entries, _ := os.ReadDir(filepath.Dir(path))
+		if len(entries) != 1 {
+			t.Errorf("memory %d: %d files left in directory, expected 1", memory, len(entries))
+		}
+	}
+}
+
+func TestBuildCompactIndexFileChangingSource(t *testing.T) {
+	passes := 0
+	source := func(fn func(start, stop int, id uint64) error) error {
+		passes++
+		for i := 0; i < 10+passes; i++ {
+			if err := fn(i, i+1, uint64(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	path := filepath.Join(t.TempDir(), "index")
+	if error := BuildCompactIndexFile(path, StandardBinning(), source, 1<<20); error == nil {
+		t.Errorf("BuildCompactIndexFile with changing source did not return error")
+	}
+}
@@ -0,0 +1,69 @@
+package binning
+
+import "path"
+
+// Filter returns a copy of the genome binning with only the chromosomes for
+// which keep returns true. Queries for the other chromosomes, or their
+// aliases, return a ContigNotIndexedError. Chromosome indexes, as used by
+// GlobalBin, refer to the remaining chromosomes.
+func (g GenomeBinning) Filter(keep func(Chromosome) bool) GenomeBinning {
+	f := GenomeBinning{
+		index:    make(map[string]int),
+		excluded: make(map[string]bool, len(g.excluded)),
+	}
+	for name := range g.excluded {
+		f.excluded[name] = true
+	}
+	aliases := g.chromosomeAliases()
+	for i, c := range g.chromosomes {
+		if !keep(c) {
+			f.excluded[c.Name] = true
+			for _, alias := range aliases[i] {
+				f.excluded[alias] = true
+			}
+			continue
+		}
+		f.index[c.Name] = len(f.chromosomes)
+		for _, alias := range aliases[i] {
+			f.index[alias] = len(f.chromosomes)
+		}
+		f.chromosomes = append(f.chromosomes, c)
+		f.schemes = append(f.schemes, g.schemes[i])
+	}
+	return f
+}
+
+// matchContig reports whether the chromosome name matches any of the
+// patterns. Patterns use the syntax of path.Match, malformed patterns do not
+// match any name.
+func matchContig(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// IncludeContigs returns a filter for use with Filter that keeps chromosomes
+// with a name matching any of the patterns, such as "chr*". Patterns use the
+// syntax of path.Match.
+func IncludeContigs(patterns ...string) func(Chromosome) bool {
+	return func(c Chromosome) bool {
+		return matchContig(c.Name, patterns)
+	}
+}
+
+// ExcludeContigs returns a filter for use with Filter that drops chromosomes
+// with a name matching any of the patterns, such as "*_alt". Patterns use the
+// syntax of path.Match.
+func ExcludeContigs(patterns ...string) func(Chromosome) bool {
+	return func(c Chromosome) bool {
+		return !matchContig(c.Name, patterns)
+	}
+}
+
+// PrimaryContigs is a filter for use with Filter that drops alternate loci,
+// fix and novel patches, unlocalized and unplaced contigs, and decoy
+// sequences, as named in UCSC-style assemblies.
+var PrimaryContigs = ExcludeContigs("*_alt", "*_fix", "*_hap*", "*_random", "chrUn*", "*_decoy", "chrEBV")
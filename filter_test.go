@@ -0,0 +1,66 @@
+package binning
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testContigs = "chr1\t248956422\nchr1_KI270706v1_random\t175055\nchr21\t46709983\n" +
+	"chr21_GL383579v2_alt\t201197\nchrUn_KI270302v1\t2274\nchrM\t16569\nchrEBV\t171823\n"
+
+func TestFilter(t *testing.T) {
+	g, error := ReadChromSizes(strings.NewReader(testContigs))
+	if error != nil {
+		t.Fatalf("ReadChromSizes() returned error: %v", error)
+	}
+	g, _ = g.WithAliases(Aliases{"MT": "chrM", "21_alt": "chr21_GL383579v2_alt"})
+	f := g.Filter(PrimaryContigs)
+	chromosomes := f.Chromosomes()
+	if len(chromosomes) != len(testChromosomes) {
+		t.Fatalf("len(Filter(PrimaryContigs).Chromosomes()) = %d, expected %d", len(chromosomes), len(testChromosomes))
+	}
+	for i, c := range chromosomes {
+		if c != testChromosomes[i] {
+			t.Errorf("Filter(PrimaryContigs).Chromosomes()[%d] = %v, expected %v", i, c, testChromosomes[i])
+		}
+	}
+	if bin, error := f.Assign("MT", 0, 1); error != nil || bin != 585 {
+		t.Errorf("Assign(%q, %d, %d) = (%d, %v), expected %d", "MT", 0, 1, bin, error, 585)
+	}
+	for _, chrom := range []string{"chr21_GL383579v2_alt", "21_alt", "chrUn_KI270302v1", "chrEBV"} {
+		bin, error := f.Assign(chrom, 0, 1)
+		if !errors.Is(error, ErrContigNotIndexed) {
+			t.Errorf("Assign(%q, %d, %d) = (%d, %v), expected ErrContigNotIndexed", chrom, 0, 1, bin, error)
+		}
+		var e *ContigNotIndexedError
+		if !errors.As(error, &e) || e.Chrom != chrom {
+			t.Errorf("Assign(%q, %d, %d) returned error %v, expected ContigNotIndexedError for %q", chrom, 0, 1, error, chrom)
+		}
+	}
+	if _, error := f.Assign("chr2", 0, 1); error == nil || errors.Is(error, ErrContigNotIndexed) {
+		t.Errorf("Assign(%q, %d, %d) returned error %v, expected unknown chromosome", "chr2", 0, 1, error)
+	}
+	if key, error := f.GlobalBin("chrM", 585); error != nil || key != 2<<32|585 {
+		t.Errorf("GlobalBin(%q, %d) = (%d, %v), expected %d", "chrM", 585, key, error, uint64(2<<32|585))
+	}
+}
+
+func TestIncludeExcludeContigs(t *testing.T) {
+	g, _ := ReadChromSizes(strings.NewReader(testContigs))
+	for _, v := range []struct {
+		keep        func(Chromosome) bool
+		chromosomes int
+	}{
+		{IncludeContigs("chr1", "chr1_*"), 2},
+		{IncludeContigs("chr?"), 2},
+		{IncludeContigs("chr[12]*"), 4},
+		{ExcludeContigs("*_*"), 4},
+		{ExcludeContigs("["), 7},
+		{IncludeContigs(), 0},
+	} {
+		if n := len(g.Filter(v.keep).Chromosomes()); n != v.chromosomes {
+			t.Errorf("len(Filter().Chromosomes()) = %d, expected %d", n, v.chromosomes)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// A Chromosome is a named sequence of a given length.
+type Chromosome struct {
+	Name   string
+	Length int
+}
+
+// A Region is the interval Start:Stop on chromosome Chrom.
+type Region struct {
+	Chrom       string
+	Start, Stop int
+}
+
+// A GenomeBinning implements interval binning for a genome consisting of
+// several chromosomes. Each chromosome is binned using the standard binning
+// scheme if it fits, or the extended binning scheme otherwise.
+type GenomeBinning struct {
+	chromosomes []Chromosome
+	schemes     []Binning
+	index       map[string]int
+	excluded    map[string]bool
+}
+
+// NewGenomeBinning creates a new binning for a genome with the given
+// chromosomes. It returns an error if a chromosome name occurs more than once
+// or a chromosome length is not supported by any binning scheme.
+func NewGenomeBinning(chromosomes []Chromosome) (GenomeBinning, error) {
+	g := GenomeBinning{
+		chromosomes: make([]Chromosome, len(chromosomes)),
+		schemes:     make([]Binning, len(chromosomes)),
+		index:       make(map[string]int, len(chromosomes)),
+	}
+
+	standard, extended := StandardBinning(), ExtendedBinning()
+
+	for i, c := range chromosomes {
+		if _, ok := g.index[c.Name]; ok {
+			return GenomeBinning{}, errors.New(fmt.Sprintf("duplicate chromosome: %q", c.Name))
+		}
+		switch {
+		case c.Length < 1:
+			return GenomeBinning{}, errors.New(fmt.Sprintf("invalid length for chromosome %q: %d", c.Name, c.Length))
+		case c.Length-1 <= standard.MaxPosition:
+			g.schemes[i] = standard
+		case c.Length-1 <= extended.MaxPosition && extended.Validate() == nil:
+			g.schemes[i] = extended
+		default:
+			return GenomeBinning{}, errors.New(fmt.Sprintf("invalid length for chromosome %q: %d (maximum length is %d)",
+				c.Name, c.Length, extended.MaxPosition))
+		}
+		g.chromosomes[i] = c
+		g.index[c.Name] = i
+	}
+
+	return g, nil
+}
+
+// Chromosomes returns the chromosomes in the genome, in the order they were
+// given to NewGenomeBinning.
+func (g GenomeBinning) Chromosomes() []Chromosome {
+	return append([]Chromosome(nil), g.chromosomes...)
+}
+
+// lookup returns the index of chromosome chrom.
+func (g GenomeBinning) lookup(chrom string) (int, error) {
+	i, ok := g.index[chrom]
+	if !ok && g.excluded[chrom] {
+		return 0, &ContigNotIndexedError{Chrom: chrom}
+	}
+	if !ok {
+		return 0, errors.New(fmt.Sprintf("unknown chromosome: %q", chrom))
+	}
+	return i, nil
+}
+
+// checkRange returns the binning scheme for chromosome chrom and an error if
+// the interval start:stop is not on the chromosome.
+func (g GenomeBinning) checkRange(chrom string, start, stop int) (Binning, error) {
+	i, err := g.lookup(chrom)
+	if err != nil {
+		return Binning{}, err
+	}
+	if c := g.chromosomes[i]; start < 0 || start >= c.Length || stop > c.Length {
+		return Binning{}, &ChromosomeRangeError{Chrom: c.Name, Start: start, Stop: stop, Length: c.Length}
+	}
+	return g.schemes[i], nil
+}
+
+// Scheme returns the binning scheme used for chromosome chrom.
+func (g GenomeBinning) Scheme(chrom string) (Binning, error) {
+	i, err := g.lookup(chrom)
+	if err != nil {
+		return Binning{}, err
+	}
+	return g.schemes[i], nil
+}
+
+// Assign returns the smallest bin fitting the interval start:stop on
+// chromosome chrom.
+func (g GenomeBinning) Assign(chrom string, start, stop int) (int, error) {
+	b, err := g.checkRange(chrom, start, stop)
+	if err != nil {
+		return 0, err
+	}
+	return b.Assign(start, stop)
+}
+
+// Overlapping returns bins for all intervals overlapping the interval
+// start:stop on chromosome chrom by at least one position.
+func (g GenomeBinning) Overlapping(chrom string, start, stop int) ([]int, error) {
+	b, err := g.checkRange(chrom, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return b.Overlapping(start, stop)
+}
+
+// OverlappingRegions returns bins for all intervals overlapping any of the
+// regions by at least one position. Bins are grouped by chromosome name (with
+// aliases resolved), sorted and without duplicates.
+func (g GenomeBinning) OverlappingRegions(regions []Region) (map[string][]int, error) {
+	bins := make(map[string][]int)
+	for _, r := range regions {
+		b, err := g.checkRange(r.Chrom, r.Start, r.Stop)
+		if err != nil {
+			return nil, err
+		}
+		overlapping, err := b.Overlapping(r.Start, r.Stop)
+		if err != nil {
+			return nil, err
+		}
+		chrom := g.chromosomes[g.index[r.Chrom]].Name
+		bins[chrom] = append(bins[chrom], overlapping...)
+	}
+	for chrom := range bins {
+		slices.Sort(bins[chrom])
+		bins[chrom] = slices.Compact(bins[chrom])
+	}
+	return bins, nil
+}
+
+// Containing returns bins for all intervals completely containing the
+// interval start:stop on chromosome chrom.
+func (g GenomeBinning) Containing(chrom string, start, stop int) ([]int, error) {
+	b, err := g.checkRange(chrom, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return b.Containing(start, stop)
+}
+
+// Contained returns bins for all intervals completely contained by the
+// interval start:stop on chromosome chrom.
+func (g GenomeBinning) Contained(chrom string, start, stop int) ([]int, error) {
+	b, err := g.checkRange(chrom, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return b.Contained(start, stop)
+}
+
+// Covered returns the interval on chromosome chrom covered by bin, clipped to
+// the chromosome.
+func (g GenomeBinning) Covered(chrom string, bin int) (int, int, error) {
+	i, err := g.lookup(chrom)
+	if err != nil {
+		return 0, 0, err
+	}
+	start, stop, err := g.schemes[i].Covered(bin)
+	if err != nil {
+		return 0, 0, err
+	}
+	if length := g.chromosomes[i].Length; start >= length {
+		b := g.schemes[i]
+		return 0, 0, &InvalidBinError{Bin: int64(bin), MinBin: int64(b.MinBin), MaxBin: int64(b.MaxBin), Length: int64(length)}
+	} else if stop > length {
+		stop = length
+	}
+	return start, stop, nil
+}
@@ -0,0 +1,134 @@
+package binning
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+var testChromosomes = []Chromosome{
+	{"chr1", 248956422},
+	{"chr21", 46709983},
+	{"chrM", 16569},
+}
+
+func TestGenomeAssign(t *testing.T) {
+	g, error := NewGenomeBinning(testChromosomes)
+	if error != nil {
+		t.Fatalf("NewGenomeBinning() returned error: %v", error)
+	}
+	for _, v := range []struct {
+		chrom            string
+		start, stop, bin int
+	}{
+		{"chr1", 0, 1, 585},
+		{"chr1", 74012, 173034, 73},
+		{"chr1", 248956421, 248956422, 585 + 248956421>>17},
+		{"chr21", 0, 46709983, 1},
+		{"chrM", 0, 16569, 585},
+	} {
+		if bin, error := g.Assign(v.chrom, v.start, v.stop); error != nil {
+			t.Errorf("Assign(%q, %d, %d) returned error: %v", v.chrom, v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("Assign(%q, %d, %d) = %d, expected %d", v.chrom, v.start, v.stop, bin, v.bin)
+		}
+	}
+	for _, v := range []struct {
+		chrom       string
+		start, stop int
+	}{
+		{"chr2", 0, 1},
+		{"chrM", 0, 16570},
+		{"chrM", 16569, 16569},
+		{"chr1", -1, 10},
+	} {
+		if bin, error := g.Assign(v.chrom, v.start, v.stop); error == nil {
+			t.Errorf("Assign(%q, %d, %d) = %d, expected error", v.chrom, v.start, v.stop, bin)
+		}
+	}
+	if _, error := g.Assign("chrM", 0, 16570); !errors.Is(error, ErrOutOfRange) {
+		t.Errorf("Assign(%q, %d, %d) returned error %v, expected ErrOutOfRange", "chrM", 0, 16570, error)
+	}
+}
+
+func TestGenomeExtended(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("extended binning scheme requires 64-bit int")
+	}
+	g, error := NewGenomeBinning([]Chromosome{{"chr1", 248956422}, {"long", 1 << 30}})
+	if error != nil {
+		t.Fatalf("NewGenomeBinning() returned error: %v", error)
+	}
+	if b, error := g.Scheme("long"); error != nil {
+		t.Errorf("Scheme(%q) returned error: %v", "long", error)
+	} else if b.MinBin != 4681 {
+		t.Errorf("Scheme(%q).MinBin = %d, expected %d", "long", b.MinBin, 4681)
+	}
+	if bin, error := g.Assign("long", 1<<30-1, 1<<30); error != nil {
+		t.Errorf("Assign(%q, %d, %d) returned error: %v", "long", 1<<30-1, 1<<30, error)
+	} else if bin != 9362+(1<<30-1)>>17 {
+		t.Errorf("Assign(%q, %d, %d) = %d, expected %d", "long", 1<<30-1, 1<<30, bin, 9362+(1<<30-1)>>17)
+	}
+}
+
+func TestNewGenomeBinningInvalid(t *testing.T) {
+	for _, chromosomes := range [][]Chromosome{
+		{{"chr1", 100}, {"chr1", 200}},
+		{{"chr1", 0}},
+		{{"chr1", -5}},
+	} {
+		if _, error := NewGenomeBinning(chromosomes); error == nil {
+			t.Errorf("NewGenomeBinning(%v) returned no error, expected error", chromosomes)
+		}
+	}
+}
+
+func TestGenomeCovered(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	if start, stop, error := g.Covered("chrM", 585); error != nil {
+		t.Errorf("Covered(%q, %d) returned error: %v", "chrM", 585, error)
+	} else if start != 0 || stop != 16569 {
+		t.Errorf("Covered(%q, %d) = (%d, %d), expected (%d, %d)", "chrM", 585, start, stop, 0, 16569)
+	}
+	if start, stop, error := g.Covered("chrM", 586); error == nil {
+		t.Errorf("Covered(%q, %d) = (%d, %d), expected error", "chrM", 586, start, stop)
+	} else if !errors.Is(error, ErrInvalidBin) {
+		t.Errorf("Covered(%q, %d) returned error %v, expected ErrInvalidBin", "chrM", 586, error)
+	}
+}
+
+func TestGenomeOverlappingRegions(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	g, _ = g.WithAliases(Aliases{"MT": "chrM"})
+	bins, error := g.OverlappingRegions([]Region{
+		{"chr1", 0, 1},
+		{"chrM", 100, 200},
+		{"chr1", 1 << 17, 1<<17 + 1},
+		{"MT", 0, 10},
+	})
+	if error != nil {
+		t.Fatalf("OverlappingRegions() returned error: %v", error)
+	}
+	expected := map[string][]int{
+		"chr1": {0, 1, 9, 73, 585, 586},
+		"chrM": {0, 1, 9, 73, 585},
+	}
+	if len(bins) != len(expected) {
+		t.Errorf("len(OverlappingRegions()) = %d, expected %d", len(bins), len(expected))
+	}
+	for chrom, e := range expected {
+		if len(bins[chrom]) != len(e) {
+			t.Errorf("len(OverlappingRegions()[%q]) = %d, expected %d", chrom, len(bins[chrom]), len(e))
+			continue
+		}
+		for i := range e {
+			if bins[chrom][i] != e[i] {
+				t.Errorf("OverlappingRegions()[%q][%d] = %d, expected %d", chrom, i, bins[chrom][i], e[i])
+				break
+			}
+		}
+	}
+	if bins, error := g.OverlappingRegions([]Region{{"chr1", 0, 1}, {"chr2", 0, 1}}); error == nil {
+		t.Errorf("OverlappingRegions() = %v, expected error", bins)
+	}
+}
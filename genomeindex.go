@@ -0,0 +1,141 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// A GenomeIndex stores values by interval on the chromosomes of a genome,
+// using a separate BinIndex per chromosome. Each chromosome has its own lock,
+// so operations on different chromosomes do not contend. It is safe for
+// concurrent use.
+type GenomeIndex[T any] struct {
+	genome GenomeBinning
+	shards []*genomeShard[T]
+}
+
+// genomeShard is the index for one chromosome. The index is created on the
+// first insert.
+type genomeShard[T any] struct {
+	mu    sync.RWMutex
+	index *BinIndex[T]
+}
+
+// NewGenomeIndex creates a new empty index for genome g.
+func NewGenomeIndex[T any](g GenomeBinning) *GenomeIndex[T] {
+	x := &GenomeIndex[T]{genome: g, shards: make([]*genomeShard[T], len(g.chromosomes))}
+	for i := range x.shards {
+		x.shards[i] = &genomeShard[T]{}
+	}
+	return x
+}
+
+// Genome returns the genome binning used by the index.
+func (x *GenomeIndex[T]) Genome() GenomeBinning {
+	return x.genome
+}
+
+// shard returns the shard for chromosome chrom.
+func (x *GenomeIndex[T]) shard(chrom string) (*genomeShard[T], error) {
+	i, err := x.genome.lookup(chrom)
+	if err != nil {
+		return nil, err
+	}
+	return x.shards[i], nil
+}
+
+// shardRange returns the shard for chromosome chrom and an error if the
+// interval start:stop is not on the chromosome.
+func (x *GenomeIndex[T]) shardRange(chrom string, start, stop int) (*genomeShard[T], error) {
+	if _, err := x.genome.checkRange(chrom, start, stop); err != nil {
+		return nil, err
+	}
+	return x.shard(chrom)
+}
+
+// Len returns the number of entries in the index.
+func (x *GenomeIndex[T]) Len() int {
+	n := 0
+	for _, s := range x.shards {
+		s.mu.RLock()
+		if s.index != nil {
+			n += s.index.Len()
+		}
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Insert stores value for the interval start:stop on chromosome chrom and
+// returns a handle for the new entry. Handles are only unique per chromosome.
+func (x *GenomeIndex[T]) Insert(chrom string, start, stop int, value T) (Handle, error) {
+	s, err := x.shardRange(chrom, start, stop)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index == nil {
+		s.index = NewBinIndex[T](x.genome.schemes[x.genome.index[chrom]])
+	}
+	return s.index.Insert(start, stop, value)
+}
+
+// Delete removes the entry with handle h on chromosome chrom.
+func (x *GenomeIndex[T]) Delete(chrom string, h Handle) error {
+	s, err := x.shard(chrom)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.index == nil {
+		return errors.New(fmt.Sprintf("unknown handle: %d", h))
+	}
+	return s.index.Delete(h)
+}
+
+// Overlapping returns the values for all intervals on chromosome chrom
+// overlapping the interval start:stop by at least one position. See
+// BinIndex.Overlapping.
+func (x *GenomeIndex[T]) Overlapping(chrom string, start, stop int) ([]T, error) {
+	s, err := x.shardRange(chrom, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.index == nil {
+		return []T{}, nil
+	}
+	return s.index.Overlapping(start, stop)
+}
+
+// CountOverlapping returns the number of intervals on chromosome chrom
+// overlapping the interval start:stop by at least one position.
+func (x *GenomeIndex[T]) CountOverlapping(chrom string, start, stop int) (int, error) {
+	s, err := x.shardRange(chrom, start, stop)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.index == nil {
+		return 0, nil
+	}
+	return s.index.CountOverlapping(start, stop)
+}
+
+// Release removes all entries on chromosome chrom, releasing the memory used
+// for them.
+func (x *GenomeIndex[T]) Release(chrom string) error {
+	s, err := x.shard(chrom)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.index = nil
+	s.mu.Unlock()
+	return nil
+}
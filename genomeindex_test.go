@@ -0,0 +1,80 @@
+package binning
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestGenomeIndex(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	g, _ = g.WithAliases(Aliases{"MT": "chrM"})
+	x := NewGenomeIndex[string](g)
+	x.Insert("chr1", 0, 100, "a")
+	b, _ := x.Insert("chr1", 50, 150, "b")
+	x.Insert("chr21", 0, 100, "c")
+	x.Insert("MT", 16000, 16569, "d")
+	if n := x.Len(); n != 4 {
+		t.Errorf("Len() = %d, expected %d", n, 4)
+	}
+	for _, v := range []struct {
+		chrom       string
+		start, stop int
+		names       []string
+	}{
+		{"chr1", 0, 200, []string{"a", "b"}},
+		{"chr21", 0, 200, []string{"c"}},
+		{"chrM", 0, 16569, []string{"d"}},
+		{"chrM", 0, 16000, []string{}},
+	} {
+		if names, error := x.Overlapping(v.chrom, v.start, v.stop); error != nil {
+			t.Errorf("Overlapping(%q, %d, %d) returned error: %v", v.chrom, v.start, v.stop, error)
+		} else if !sameElements(names, v.names) {
+			t.Errorf("Overlapping(%q, %d, %d) = %v, expected %v", v.chrom, v.start, v.stop, names, v.names)
+		}
+		if n, error := x.CountOverlapping(v.chrom, v.start, v.stop); error != nil || n != len(v.names) {
+			t.Errorf("CountOverlapping(%q, %d, %d) = (%d, %v), expected %d", v.chrom, v.start, v.stop, n, error, len(v.names))
+		}
+	}
+	if h, error := x.Insert("chrM", 0, 16570, "e"); !errors.Is(error, ErrOutOfRange) {
+		t.Errorf("Insert(%q, %d, %d, %q) = (%d, %v), expected ErrOutOfRange", "chrM", 0, 16570, "e", h, error)
+	}
+	if error := x.Delete("chr1", b); error != nil {
+		t.Errorf("Delete(%q, %d) returned error: %v", "chr1", b, error)
+	}
+	if error := x.Delete("chr1", b); error == nil {
+		t.Errorf("Delete(%q, %d) returned no error, expected error", "chr1", b)
+	}
+	if error := x.Release("chr21"); error != nil {
+		t.Errorf("Release(%q) returned error: %v", "chr21", error)
+	}
+	if names, _ := x.Overlapping("chr21", 0, 200); len(names) != 0 {
+		t.Errorf("Overlapping(%q, %d, %d) = %v, expected %v", "chr21", 0, 200, names, []string{})
+	}
+	if n := x.Len(); n != 2 {
+		t.Errorf("Len() = %d, expected %d", n, 2)
+	}
+	if error := x.Release("chr2"); error == nil {
+		t.Errorf("Release(%q) returned no error, expected error", "chr2")
+	}
+}
+
+func TestGenomeIndexConcurrent(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	x := NewGenomeIndex[int](g)
+	var wg sync.WaitGroup
+	for _, c := range testChromosomes {
+		wg.Add(1)
+		go func(chrom string) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				x.Insert(chrom, i, i+10, i)
+				x.CountOverlapping(chrom, 0, 1000)
+			}
+		}(c.Name)
+	}
+	wg.Wait()
+	if n := x.Len(); n != 3000 {
+		t.Errorf("Len() = %d, expected %d", n, 3000)
+	}
+}
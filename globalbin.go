@@ -0,0 +1,49 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+)
+
+// GlobalBin returns a single key encoding the chromosome index chromIndex and
+// bin, with the chromosome index in the upper and the bin in the lower 32
+// bits. Keys sort by chromosome index, then by bin. It returns an error if
+// either value is negative or does not fit in 32 bits.
+func GlobalBin(chromIndex, bin int) (uint64, error) {
+	if chromIndex < 0 || uint64(chromIndex) > 1<<32-1 {
+		return 0, errors.New(fmt.Sprintf("invalid chromosome index: %d", chromIndex))
+	}
+	if bin < 0 || uint64(bin) > 1<<32-1 {
+		return 0, errors.New(fmt.Sprintf("invalid bin: %d", bin))
+	}
+	return uint64(chromIndex)<<32 | uint64(bin), nil
+}
+
+// SplitGlobalBin returns the chromosome index and bin encoded in key. It is
+// the inverse of GlobalBin.
+func SplitGlobalBin(key uint64) (int, int) {
+	return int(key >> 32), int(key & (1<<32 - 1))
+}
+
+// GlobalBin returns a single key encoding chromosome chrom and bin, using the
+// index of chrom in the genome. See GlobalBin.
+func (g GenomeBinning) GlobalBin(chrom string, bin int) (uint64, error) {
+	i, err := g.lookup(chrom)
+	if err != nil {
+		return 0, err
+	}
+	if _, _, err := g.Covered(chrom, bin); err != nil {
+		return 0, err
+	}
+	return GlobalBin(i, bin)
+}
+
+// SplitGlobalBin returns the chromosome and bin encoded in key. It is the
+// inverse of GenomeBinning.GlobalBin.
+func (g GenomeBinning) SplitGlobalBin(key uint64) (string, int, error) {
+	i, bin := SplitGlobalBin(key)
+	if i >= len(g.chromosomes) {
+		return "", 0, errors.New(fmt.Sprintf("invalid chromosome index: %d", i))
+	}
+	return g.chromosomes[i].Name, bin, nil
+}
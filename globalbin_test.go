@@ -0,0 +1,64 @@
+package binning
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestGlobalBin(t *testing.T) {
+	previous := uint64(0)
+	for i, v := range []struct{ chromIndex, bin int }{
+		{0, 0},
+		{0, 585},
+		{0, 4680},
+		{1, 0},
+		{1, 9},
+		{24, 25745},
+		{math.MaxInt32, math.MaxInt32},
+	} {
+		key, error := GlobalBin(v.chromIndex, v.bin)
+		if error != nil {
+			t.Errorf("GlobalBin(%d, %d) returned error: %v", v.chromIndex, v.bin, error)
+			continue
+		}
+		if i > 0 && key <= previous {
+			t.Errorf("GlobalBin(%d, %d) = %d, expected > %d", v.chromIndex, v.bin, key, previous)
+		}
+		previous = key
+		if chromIndex, bin := SplitGlobalBin(key); chromIndex != v.chromIndex || bin != v.bin {
+			t.Errorf("SplitGlobalBin(%d) = (%d, %d), expected (%d, %d)", key, chromIndex, bin, v.chromIndex, v.bin)
+		}
+	}
+	invalid := []struct{ chromIndex, bin int }{{-1, 0}, {0, -1}}
+	if strconv.IntSize == 64 {
+		invalid = append(invalid, struct{ chromIndex, bin int }{math.MaxInt, 0}, struct{ chromIndex, bin int }{0, math.MaxInt})
+	}
+	for _, v := range invalid {
+		if key, error := GlobalBin(v.chromIndex, v.bin); error == nil {
+			t.Errorf("GlobalBin(%d, %d) = %d, expected error", v.chromIndex, v.bin, key)
+		}
+	}
+}
+
+func TestGenomeGlobalBin(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	key, error := g.GlobalBin("chr21", 73)
+	if error != nil {
+		t.Fatalf("GlobalBin(%q, %d) returned error: %v", "chr21", 73, error)
+	}
+	if key != 1<<32|73 {
+		t.Errorf("GlobalBin(%q, %d) = %d, expected %d", "chr21", 73, key, uint64(1<<32|73))
+	}
+	if chrom, bin, error := g.SplitGlobalBin(key); error != nil {
+		t.Errorf("SplitGlobalBin(%d) returned error: %v", key, error)
+	} else if chrom != "chr21" || bin != 73 {
+		t.Errorf("SplitGlobalBin(%d) = (%q, %d), expected (%q, %d)", key, chrom, bin, "chr21", 73)
+	}
+	if key, error := g.GlobalBin("chrM", 586); error == nil {
+		t.Errorf("GlobalBin(%q, %d) = %d, expected error", "chrM", 586, key)
+	}
+	if chrom, bin, error := g.SplitGlobalBin(3 << 32); error == nil {
+		t.Errorf("SplitGlobalBin(%d) = (%q, %d), expected error", uint64(3<<32), chrom, bin)
+	}
+}
@@ -0,0 +1,612 @@
+package binning
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// A Handle identifies an entry in a BinIndex.
+type Handle uint64
+
+// An Entry is an interval with a value stored in a BinIndex.
+type Entry[T any] struct {
+	Start, Stop int
+	Value       T
+	Handle      Handle
+}
+
+// A BinIndex stores values by interval and answers overlap queries by
+// inspecting only the bins that can contain overlapping intervals. Each
+// value is stored in the smallest bin fitting its interval.
+type BinIndex[T any] struct {
+	scheme  Binning
+	bins    map[int][]Entry[T]
+	handles map[Handle]int
+	next    Handle
+
+	// After a snapshot, bins not in owned and handles if sharedHandles
+	// are shared with another index and must be copied before modifying.
+	cow           bool
+	owned         map[int]bool
+	sharedHandles bool
+
+	// Per-bin Bloom filters of keys, if enabled.
+	key    func(T) string
+	blooms map[int]bloomFilter
+}
+
+// NewBinIndex creates a new empty index using binning scheme b.
+func NewBinIndex[T any](b Binning) *BinIndex[T] {
+	return &BinIndex[T]{scheme: b, bins: make(map[int][]Entry[T]), handles: make(map[Handle]int)}
+}
+
+// Scheme returns the binning scheme used by the index.
+func (x *BinIndex[T]) Scheme() Binning {
+	return x.scheme
+}
+
+// Len returns the number of entries in the index.
+func (x *BinIndex[T]) Len() int {
+	return len(x.handles)
+}
+
+// modify returns the entries in bin, copying them first if they are shared
+// with another index.
+func (x *BinIndex[T]) modify(bin int) []Entry[T] {
+	if x.cow && !x.owned[bin] {
+		if entries, ok := x.bins[bin]; ok {
+			x.bins[bin] = slices.Clone(entries)
+		}
+		x.owned[bin] = true
+	}
+	return x.bins[bin]
+}
+
+// modifyHandles copies the handles first if they are shared with another
+// index.
+func (x *BinIndex[T]) modifyHandles() {
+	if x.sharedHandles {
+		x.handles = maps.Clone(x.handles)
+		x.sharedHandles = false
+	}
+}
+
+// Snapshot returns a copy of the index. The copy shares storage with the
+// original until either is modified, after which only the modified bins are
+// copied. The copy can be queried while the original is modified, but
+// Snapshot itself must not be called concurrently with modifications.
+func (x *BinIndex[T]) Snapshot() *BinIndex[T] {
+	x.cow, x.owned, x.sharedHandles = true, make(map[int]bool), true
+	return &BinIndex[T]{
+		scheme:        x.scheme,
+		bins:          maps.Clone(x.bins),
+		handles:       x.handles,
+		next:          x.next,
+		cow:           true,
+		owned:         make(map[int]bool),
+		sharedHandles: true,
+		key:           x.key,
+		blooms:        maps.Clone(x.blooms),
+	}
+}
+
+// Insert stores value for the interval start:stop and returns a handle for
+// the new entry. It returns an error if the interval cannot be binned.
+func (x *BinIndex[T]) Insert(start, stop int, value T) (Handle, error) {
+	bin, err := x.scheme.Assign(start, stop)
+	if err != nil {
+		return 0, err
+	}
+	x.next++
+	x.bins[bin] = append(x.modify(bin), Entry[T]{Start: start, Stop: stop, Value: value, Handle: x.next})
+	x.addKey(bin, value)
+	x.modifyHandles()
+	x.handles[x.next] = bin
+	return x.next, nil
+}
+
+// find returns the bin and position within the bin of the entry with handle
+// h. It returns an error if there is no such entry.
+func (x *BinIndex[T]) find(h Handle) (int, int, error) {
+	bin, ok := x.handles[h]
+	if !ok {
+		return 0, 0, errors.New(fmt.Sprintf("unknown handle: %d", h))
+	}
+	i := slices.IndexFunc(x.bins[bin], func(e Entry[T]) bool { return e.Handle == h })
+	return bin, i, nil
+}
+
+// Delete removes the entry with handle h. It returns an error if there is no
+// such entry.
+func (x *BinIndex[T]) Delete(h Handle) error {
+	bin, i, err := x.find(h)
+	if err != nil {
+		return err
+	}
+	if entries := slices.Delete(x.modify(bin), i, i+1); len(entries) > 0 {
+		x.bins[bin] = entries
+	} else {
+		delete(x.bins, bin)
+	}
+	x.modifyHandles()
+	delete(x.handles, h)
+	return nil
+}
+
+// Update changes the interval of the entry with handle h to start:stop,
+// moving it to another bin if needed. The handle remains valid. It returns an
+// error if there is no such entry or the interval cannot be binned, in which
+// case the entry is not changed.
+func (x *BinIndex[T]) Update(h Handle, start, stop int) error {
+	bin, i, err := x.find(h)
+	if err != nil {
+		return err
+	}
+	newBin, err := x.scheme.Assign(start, stop)
+	if err != nil {
+		return err
+	}
+	if newBin == bin {
+		entries := x.modify(bin)
+		entries[i].Start, entries[i].Stop = start, stop
+		return nil
+	}
+	e := x.bins[bin][i]
+	e.Start, e.Stop = start, stop
+	x.Delete(h)
+	x.bins[newBin] = append(x.modify(newBin), e)
+	x.addKey(newBin, e.Value)
+	x.handles[h] = newBin
+	return nil
+}
+
+// scan calls fn for each entry overlapping the interval start:stop by at
+// least one position, starting with the smallest bins, until fn returns
+// false. Empty intervals do not overlap anything. If keep is not nil, only
+// entries with a value for which keep returns true are considered.
+func (x *BinIndex[T]) scan(start, stop int, keep func(T) bool, fn func(e *Entry[T]) bool) error {
+	nextRange, err := x.scheme.ranges(start, stop)
+	if err != nil {
+		return err
+	}
+	for {
+		startBin, stopBin, ok := nextRange()
+		if !ok {
+			return nil
+		}
+		for bin := startBin; bin <= stopBin; bin++ {
+			entries := x.bins[bin]
+			for i := range entries {
+				if max(entries[i].Start, start) >= min(entries[i].Stop, stop) || keep != nil && !keep(entries[i].Value) {
+					continue
+				}
+				if !fn(&entries[i]) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// Overlapping returns the values for all intervals overlapping the interval
+// start:stop by at least one position. Values are ordered by bin, starting
+// with the smallest bins, and by insertion order within a bin.
+func (x *BinIndex[T]) Overlapping(start, stop int, options ...QueryOption[T]) ([]T, error) {
+	values, err := x.AppendOverlapping([]T{}, start, stop, options...)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// AppendOverlapping is like Overlapping but appends the values to dst and
+// returns the extended slice. Reusing dst across queries avoids allocating a
+// new result slice for each query. On error, dst is returned unchanged.
+func (x *BinIndex[T]) AppendOverlapping(dst []T, start, stop int, options ...QueryOption[T]) ([]T, error) {
+	values := dst
+	err := x.scan(start, stop, queryOptions(options).keep, func(e *Entry[T]) bool {
+		values = append(values, e.Value)
+		return true
+	})
+	if err != nil {
+		return dst, err
+	}
+	return values, nil
+}
+
+// Any reports whether any interval overlaps the interval start:stop by at
+// least one position. It stops at the first overlapping interval found.
+func (x *BinIndex[T]) Any(start, stop int, options ...QueryOption[T]) (bool, error) {
+	found := false
+	err := x.scan(start, stop, queryOptions(options).keep, func(e *Entry[T]) bool {
+		found = true
+		return false
+	})
+	if err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+// FirstN returns the values for at most n intervals overlapping the interval
+// start:stop by at least one position. It stops as soon as n values are
+// found. Values are the first n in the order of Overlapping.
+func (x *BinIndex[T]) FirstN(start, stop, n int, options ...QueryOption[T]) ([]T, error) {
+	values := []T{}
+	if n < 1 {
+		_, _, err := x.scheme.checkRange(start, stop)
+		return values, err
+	}
+	err := x.scan(start, stop, queryOptions(options).keep, func(e *Entry[T]) bool {
+		values = append(values, e.Value)
+		return len(values) < n
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// At returns the values for all intervals containing position pos. Only the
+// single bin per level containing pos is inspected. Values are ordered as
+// with Overlapping.
+func (x *BinIndex[T]) At(pos int, options ...QueryOption[T]) ([]T, error) {
+	values, err := x.AppendAt([]T{}, pos, options...)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// AppendAt is like At but appends the values to dst and returns the extended
+// slice. On error, dst is returned unchanged.
+func (x *BinIndex[T]) AppendAt(dst []T, pos int, options ...QueryOption[T]) ([]T, error) {
+	if _, _, err := x.scheme.checkRange(pos, pos+1); err != nil {
+		return dst, err
+	}
+	keep := queryOptions(options).keep
+	for level, shift := range x.scheme.shifts {
+		for _, e := range x.bins[x.scheme.binOffsets[level]+pos>>shift] {
+			if e.Start <= pos && pos < e.Stop && (keep == nil || keep(e.Value)) {
+				dst = append(dst, e.Value)
+			}
+		}
+	}
+	return dst, nil
+}
+
+// CountOverlapping returns the number of intervals overlapping the interval
+// start:stop by at least one position, without collecting their values.
+func (x *BinIndex[T]) CountOverlapping(start, stop int, options ...QueryOption[T]) (int, error) {
+	n := 0
+	err := x.scan(start, stop, queryOptions(options).keep, func(e *Entry[T]) bool {
+		n++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Distance returns the distance from position pos to the nearest position in
+// the interval of the entry, or 0 if the interval contains pos. An empty
+// interval contains no positions; its distance is taken from its start.
+func (e Entry[T]) Distance(pos int) int {
+	switch {
+	case pos < e.Start:
+		return e.Start - pos
+	case pos >= e.Stop:
+		return pos - max(e.Start, e.Stop-1)
+	}
+	return 0
+}
+
+// Nearest returns the entry nearest to position pos and its distance to pos.
+// Of entries at equal distance, the first in the order of All is returned. It
+// returns an error if pos cannot be binned or there are no non-empty
+// intervals in the index. Entries with empty intervals are never returned.
+func (x *BinIndex[T]) Nearest(pos int) (Entry[T], int, error) {
+	entries, err := x.NearestN(pos, 1)
+	if err != nil {
+		return Entry[T]{}, 0, err
+	}
+	if len(entries) == 0 {
+		return Entry[T]{}, 0, errors.New("no entries in index")
+	}
+	return entries[0], entries[0].Distance(pos), nil
+}
+
+// NearestN returns the n entries nearest to position pos, ordered by distance
+// to pos and then as with All. Fewer entries are returned if the index does
+// not have n non-empty intervals. Candidates are found by querying windows
+// around pos of increasing size, starting with the smallest bin size.
+func (x *BinIndex[T]) NearestN(pos, n int) ([]Entry[T], error) {
+	if _, _, err := x.scheme.checkRange(pos, pos+1); err != nil {
+		return nil, err
+	}
+	if n < 1 {
+		return []Entry[T]{}, nil
+	}
+	limit := x.scheme.MaxPosition + 1
+	for radius := 1 << x.scheme.shifts[0]; ; radius *= 2 {
+		start, stop := max(pos-radius, 0), min(pos+radius, limit-1)+1
+		entries := []Entry[T]{}
+		err := x.scan(start, stop, nil, func(e *Entry[T]) bool {
+			entries = append(entries, *e)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		slices.SortFunc(entries, func(a, b Entry[T]) int {
+			if c := cmp.Compare(a.Distance(pos), b.Distance(pos)); c != 0 {
+				return c
+			}
+			return compareEntries(a, b)
+		})
+		// Any entry within radius of pos overlaps the window, so the
+		// nearest n are final if they are all within radius.
+		if len(entries) >= n && entries[n-1].Distance(pos) <= radius {
+			return entries[:n], nil
+		}
+		if start == 0 && stop == limit {
+			return entries[:min(n, len(entries))], nil
+		}
+	}
+}
+
+// ClosestUpstream returns the entry with an interval ending before position
+// pos that is nearest to pos, and its distance to pos. Only the coordinate
+// axis is considered, not strands. Entries containing pos are not returned.
+// Of entries at equal distance, the first in the order of All is returned. It
+// returns an error if pos cannot be binned or there is no such entry.
+func (x *BinIndex[T]) ClosestUpstream(pos int) (Entry[T], int, error) {
+	return x.closest(pos, -1)
+}
+
+// ClosestDownstream returns the entry with an interval starting after
+// position pos that is nearest to pos, and its distance to pos. See
+// ClosestUpstream.
+func (x *BinIndex[T]) ClosestDownstream(pos int) (Entry[T], int, error) {
+	return x.closest(pos, 1)
+}
+
+// closest returns the nearest entry strictly upstream (direction -1) or
+// downstream (direction 1) of pos, querying windows on that side of pos of
+// increasing size.
+func (x *BinIndex[T]) closest(pos, direction int) (Entry[T], int, error) {
+	if _, _, err := x.scheme.checkRange(pos, pos+1); err != nil {
+		return Entry[T]{}, 0, err
+	}
+	limit := x.scheme.MaxPosition + 1
+	for radius := 1 << x.scheme.shifts[0]; ; radius *= 2 {
+		start, stop := max(pos-radius, 0), pos
+		if direction > 0 {
+			start, stop = pos+1, min(pos+radius, limit-1)+1
+		}
+		var best Entry[T]
+		found := false
+		// The window is empty if pos is the first or last position.
+		if start < stop {
+			err := x.scan(start, stop, nil, func(e *Entry[T]) bool {
+				if (direction < 0 && e.Stop > pos) || (direction > 0 && e.Start <= pos) {
+					return true
+				}
+				if c := cmp.Compare(e.Distance(pos), best.Distance(pos)); !found || c < 0 || c == 0 && compareEntries(*e, best) < 0 {
+					best, found = *e, true
+				}
+				return true
+			})
+			if err != nil {
+				return Entry[T]{}, 0, err
+			}
+		}
+		// Any entry within radius of pos on this side overlaps the window.
+		if found && best.Distance(pos) <= radius {
+			return best, best.Distance(pos), nil
+		}
+		if start == 0 && direction < 0 || stop == limit && direction > 0 {
+			if found {
+				return best, best.Distance(pos), nil
+			}
+			return Entry[T]{}, 0, errors.New("no entries in index")
+		}
+	}
+}
+
+// compareEntries orders entries by start, stop and insertion order.
+func compareEntries[T any](a, b Entry[T]) int {
+	if c := cmp.Compare(a.Start, b.Start); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Stop, b.Stop); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Handle, b.Handle)
+}
+
+// All returns an iterator over all entries in the index, ordered by start,
+// then by stop, then by insertion order. The index must not be modified
+// during iteration.
+func (x *BinIndex[T]) All() func(yield func(Entry[T]) bool) {
+	return func(yield func(Entry[T]) bool) {
+		entries := make([]Entry[T], 0, x.Len())
+		for _, bin := range x.bins {
+			entries = append(entries, bin...)
+		}
+		slices.SortFunc(entries, compareEntries[T])
+		for _, e := range entries {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// MergedRegions returns the union of all intervals in the index as a sorted
+// list of non-overlapping intervals. Overlapping and adjacent intervals are
+// merged and empty intervals are ignored. The intervals in each bin are
+// merged first, so only the merged intervals per bin are sorted together.
+func (x *BinIndex[T]) MergedRegions() []Interval {
+	merged := []Interval{}
+	for _, entries := range x.bins {
+		intervals := make([]Interval, len(entries))
+		for i, e := range entries {
+			intervals[i] = Interval{Start: e.Start, Stop: e.Stop}
+		}
+		merged = append(merged, mergeIntervals(intervals)...)
+	}
+	return mergeIntervals(merged)
+}
+
+// Gaps returns the parts of the interval start:stop not covered by any
+// interval in the index, as a sorted list of non-overlapping intervals.
+func (x *BinIndex[T]) Gaps(start, stop int) ([]Interval, error) {
+	covered := []Interval{}
+	err := x.scan(start, stop, nil, func(e *Entry[T]) bool {
+		covered = append(covered, Interval{Start: max(e.Start, start), Stop: min(e.Stop, stop)})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	gaps := []Interval{}
+	for _, c := range mergeIntervals(covered) {
+		if start < c.Start {
+			gaps = append(gaps, Interval{Start: start, Stop: c.Start})
+		}
+		start = c.Stop
+	}
+	if start < stop {
+		gaps = append(gaps, Interval{Start: start, Stop: stop})
+	}
+	return gaps, nil
+}
+
+// Subtract returns the entries in the index with the regions removed from
+// their intervals, in the order of All. Entries are split where a region lies
+// within their interval, with each part keeping the value and handle of the
+// entry. Entries completely covered by the regions and entries with empty
+// intervals are omitted. The index is not modified.
+func (x *BinIndex[T]) Subtract(regions []Interval) []Entry[T] {
+	masked := mergeIntervals(slices.Clone(regions))
+	result := []Entry[T]{}
+	x.All()(func(e Entry[T]) bool {
+		// First region ending after the start of the entry.
+		i, _ := slices.BinarySearchFunc(masked, e.Start, func(r Interval, start int) int {
+			return cmp.Compare(r.Stop, start+1)
+		})
+		start := e.Start
+		for ; i < len(masked) && masked[i].Start < e.Stop; i++ {
+			if start < masked[i].Start {
+				result = append(result, Entry[T]{Start: start, Stop: masked[i].Start, Value: e.Value, Handle: e.Handle})
+			}
+			start = max(start, masked[i].Stop)
+		}
+		if start < e.Stop {
+			result = append(result, Entry[T]{Start: start, Stop: e.Stop, Value: e.Value, Handle: e.Handle})
+		}
+		return true
+	})
+	return result
+}
+
+// Coverage returns the depth of coverage by the intervals in the index for
+// each position in the interval start:stop.
+func (x *BinIndex[T]) Coverage(start, stop int) ([]int, error) {
+	if stop < start {
+		return nil, &EmptyIntervalError{Start: int64(start), Stop: int64(stop)}
+	}
+	depth := make([]int, stop-start+1)
+	err := x.scan(start, stop, nil, func(e *Entry[T]) bool {
+		depth[max(e.Start, start)-start]++
+		depth[min(e.Stop, stop)-start]--
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(depth); i++ {
+		depth[i] += depth[i-1]
+	}
+	return depth[:stop-start], nil
+}
+
+// CoverageWindows returns the mean depth of coverage by the intervals in the
+// index for consecutive windows of size positions in the interval
+// start:stop. The last window is shorter if the interval length is not a
+// multiple of size.
+func (x *BinIndex[T]) CoverageWindows(start, stop, size int) ([]float64, error) {
+	if size < 1 {
+		return nil, errors.New(fmt.Sprintf("invalid window size: %d", size))
+	}
+	depth, err := x.Coverage(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	windows := make([]float64, 0, (len(depth)+size-1)/size)
+	for i := 0; i < len(depth); i += size {
+		sum := 0
+		for _, d := range depth[i:min(i+size, len(depth))] {
+			sum += d
+		}
+		windows = append(windows, float64(sum)/float64(min(size, len(depth)-i)))
+	}
+	return windows, nil
+}
+
+// Cluster groups the entries in the index into clusters of entries with
+// intervals at most maxGap positions apart. Entries are taken in the order of
+// All and an entry joins the current cluster if it starts at most maxGap
+// positions after the end of any interval in the cluster. Clusters are
+// returned in the same order. It returns an error if maxGap is negative.
+func (x *BinIndex[T]) Cluster(maxGap int) ([][]Entry[T], error) {
+	if maxGap < 0 {
+		return nil, errors.New(fmt.Sprintf("invalid maximum gap: %d", maxGap))
+	}
+	entries := make([]Entry[T], 0, x.Len())
+	for _, bin := range x.bins {
+		entries = append(entries, bin...)
+	}
+	slices.SortFunc(entries, compareEntries[T])
+	clusters := [][]Entry[T]{}
+	stop := 0
+	for _, e := range entries {
+		if n := len(clusters); n > 0 && e.Start-stop <= maxGap {
+			clusters[n-1] = append(clusters[n-1], e)
+			stop = max(stop, e.Stop)
+		} else {
+			clusters = append(clusters, []Entry[T]{e})
+			stop = e.Stop
+		}
+	}
+	return clusters, nil
+}
+
+// ReduceByBin groups the values in index x by bin on the given level and
+// returns the result of fn for each bin with at least one value. Levels are
+// numbered starting with 0 for the smallest bins. Each entry is grouped in
+// the bin on that level containing the start of its interval, so entries
+// spanning several bins are only counted once. Values are passed to fn in
+// the order of All. It returns an error if there is no such level.
+func ReduceByBin[T, R any](x *BinIndex[T], level int, fn func(bin int, values []T) R) (map[int]R, error) {
+	if level < 0 || level >= len(x.scheme.shifts) {
+		return nil, errors.New(fmt.Sprintf("invalid level: %d", level))
+	}
+	offset, shift := x.scheme.binOffsets[level], x.scheme.shifts[level]
+	groups := make(map[int][]T)
+	x.All()(func(e Entry[T]) bool {
+		bin := offset + e.Start>>shift
+		groups[bin] = append(groups[bin], e.Value)
+		return true
+	})
+	results := make(map[int]R, len(groups))
+	for bin, values := range groups {
+		results[bin] = fn(bin, values)
+	}
+	return results, nil
+}
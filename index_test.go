@@ -0,0 +1,608 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var indexIntervals = []struct {
+	start, stop int
+	name        string
+}{
+	{0, 100, "a"},
+	{50, 150, "b"},
+	{1 << 17, 1<<17 + 10, "c"},
+	{1<<17 - 5, 1<<17 + 5, "d"},
+	{1000000, 5000000, "e"},
+	{200, 200, "f"},
+}
+
+func newTestIndex(t *testing.T) *BinIndex[string] {
+	x := NewBinIndex[string](StandardBinning())
+	for _, v := range indexIntervals {
+		if _, error := x.Insert(v.start, v.stop, v.name); error != nil {
+			t.Fatalf("Insert(%d, %d, %q) returned error: %v", v.start, v.stop, v.name, error)
+		}
+	}
+	return x
+}
+
+func TestBinIndexOverlapping(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		start, stop int
+		names       []string
+	}{
+		{0, 1, []string{"a"}},
+		{99, 100, []string{"a", "b"}},
+		{100, 101, []string{"b"}},
+		{150, 1<<17 - 5, []string{}},
+		{1<<17 - 1, 1 << 17, []string{"d"}},
+		{1 << 17, 1<<17 + 1, []string{"c", "d"}},
+		{0, 1 << 29, []string{"a", "b", "c", "d", "e"}},
+		{4999999, 5000000, []string{"e"}},
+		{200, 201, []string{}},
+	} {
+		names, error := x.Overlapping(v.start, v.stop)
+		if error != nil {
+			t.Errorf("Overlapping(%d, %d) returned error: %v", v.start, v.stop, error)
+			continue
+		}
+		if !sameElements(names, v.names) {
+			t.Errorf("Overlapping(%d, %d) = %v, expected %v", v.start, v.stop, names, v.names)
+		}
+		if n, error := x.CountOverlapping(v.start, v.stop); error != nil {
+			t.Errorf("CountOverlapping(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if n != len(v.names) {
+			t.Errorf("CountOverlapping(%d, %d) = %d, expected %d", v.start, v.stop, n, len(v.names))
+		}
+	}
+	if names, error := x.Overlapping(0, 1<<29+1); !errors.Is(error, ErrOutOfRange) {
+		t.Errorf("Overlapping(%d, %d) = (%v, %v), expected ErrOutOfRange", 0, 1<<29+1, names, error)
+	}
+	if n, error := x.CountOverlapping(0, 1<<29+1); !errors.Is(error, ErrOutOfRange) {
+		t.Errorf("CountOverlapping(%d, %d) = (%d, %v), expected ErrOutOfRange", 0, 1<<29+1, n, error)
+	}
+}
+
+func TestBinIndexAt(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		pos   int
+		names []string
+	}{
+		{0, []string{"a"}},
+		{50, []string{"a", "b"}},
+		{100, []string{"b"}},
+		{150, []string{}},
+		{200, []string{}},
+		{1 << 17, []string{"c", "d"}},
+		{1<<17 + 5, []string{"c"}},
+		{4999999, []string{"e"}},
+	} {
+		if names, error := x.At(v.pos); error != nil {
+			t.Errorf("At(%d) returned error: %v", v.pos, error)
+		} else if !sameElements(names, v.names) {
+			t.Errorf("At(%d) = %v, expected %v", v.pos, names, v.names)
+		}
+	}
+	for _, pos := range []int{-1, 1 << 29} {
+		if names, error := x.At(pos); !errors.Is(error, ErrOutOfRange) {
+			t.Errorf("At(%d) = (%v, %v), expected ErrOutOfRange", pos, names, error)
+		}
+	}
+}
+
+func TestBinIndexAnyFirstN(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		start, stop int
+		any         bool
+	}{
+		{0, 1, true},
+		{150, 1<<17 - 5, false},
+		{200, 201, false},
+		{0, 1 << 29, true},
+	} {
+		if any, error := x.Any(v.start, v.stop); error != nil {
+			t.Errorf("Any(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if any != v.any {
+			t.Errorf("Any(%d, %d) = %v, expected %v", v.start, v.stop, any, v.any)
+		}
+	}
+	for _, v := range []struct {
+		start, stop, n int
+		names          []string
+	}{
+		{0, 1 << 29, 2, []string{"a", "b"}},
+		{0, 1 << 29, 0, []string{}},
+		{0, 1 << 29, 10, []string{"a", "b", "d", "c", "e"}},
+		{1 << 17, 1<<17 + 1, 1, []string{"c"}},
+	} {
+		names, error := x.FirstN(v.start, v.stop, v.n)
+		if error != nil {
+			t.Errorf("FirstN(%d, %d, %d) returned error: %v", v.start, v.stop, v.n, error)
+			continue
+		}
+		expected, _ := x.Overlapping(v.start, v.stop)
+		if len(names) != len(v.names) || !sameElements(names, expected[:len(names)]) {
+			t.Errorf("FirstN(%d, %d, %d) = %v, expected %v", v.start, v.stop, v.n, names, v.names)
+		}
+	}
+	if any, error := x.Any(-1, 10); error == nil {
+		t.Errorf("Any(%d, %d) = %v, expected error", -1, 10, any)
+	}
+	if names, error := x.FirstN(-1, 10, 0); error == nil {
+		t.Errorf("FirstN(%d, %d, %d) = %v, expected error", -1, 10, 0, names)
+	}
+}
+
+func TestBinIndexInsertInvalid(t *testing.T) {
+	x := NewBinIndex[string](StandardBinning())
+	if h, error := x.Insert(-1, 10, "a"); error == nil {
+		t.Errorf("Insert(%d, %d, %q) = %d, expected error", -1, 10, "a", h)
+	}
+}
+
+func TestBinIndexDelete(t *testing.T) {
+	x := NewBinIndex[string](StandardBinning())
+	a, _ := x.Insert(0, 100, "a")
+	b, _ := x.Insert(50, 150, "b")
+	c, _ := x.Insert(60, 70, "c")
+	if error := x.Delete(b); error != nil {
+		t.Fatalf("Delete(%d) returned error: %v", b, error)
+	}
+	if names, _ := x.Overlapping(0, 200); !sameElements(names, []string{"a", "c"}) {
+		t.Errorf("Overlapping(%d, %d) = %v, expected %v", 0, 200, names, []string{"a", "c"})
+	}
+	if error := x.Delete(b); error == nil {
+		t.Errorf("Delete(%d) returned no error, expected error", b)
+	}
+	x.Delete(a)
+	x.Delete(c)
+	if names, _ := x.Overlapping(0, 200); len(names) != 0 {
+		t.Errorf("Overlapping(%d, %d) = %v, expected %v", 0, 200, names, []string{})
+	}
+	if len(x.bins) != 0 {
+		t.Errorf("len(bins) = %d, expected %d", len(x.bins), 0)
+	}
+}
+
+func TestBinIndexUpdate(t *testing.T) {
+	x := NewBinIndex[string](StandardBinning())
+	a, _ := x.Insert(0, 100, "a")
+	x.Insert(50, 150, "b")
+	for _, v := range []struct {
+		start, stop int
+		bin         int
+	}{
+		{10, 20, 585},
+		{1 << 17, 1<<17 + 10, 586},
+		{0, 1 << 20, 73},
+	} {
+		if error := x.Update(a, v.start, v.stop); error != nil {
+			t.Errorf("Update(%d, %d, %d) returned error: %v", a, v.start, v.stop, error)
+			continue
+		}
+		if bin := x.handles[a]; bin != v.bin {
+			t.Errorf("Update(%d, %d, %d) moved entry to bin %d, expected %d", a, v.start, v.stop, bin, v.bin)
+		}
+		if names, _ := x.Overlapping(v.stop-1, v.stop); !sameElements(names, []string{"a"}) {
+			t.Errorf("Overlapping(%d, %d) = %v, expected %v", v.stop-1, v.stop, names, []string{"a"})
+		}
+	}
+	if error := x.Update(a, -1, 10); error == nil {
+		t.Errorf("Update(%d, %d, %d) returned no error, expected error", a, -1, 10)
+	}
+	if names, _ := x.Overlapping(1<<20-1, 1<<20); !sameElements(names, []string{"a"}) {
+		t.Errorf("Overlapping(%d, %d) = %v, expected %v", 1<<20-1, 1<<20, names, []string{"a"})
+	}
+	if error := x.Update(42, 0, 10); error == nil {
+		t.Errorf("Update(%d, %d, %d) returned no error, expected error", 42, 0, 10)
+	}
+}
+
+func TestBinIndexAll(t *testing.T) {
+	x := newTestIndex(t)
+	if n := x.Len(); n != len(indexIntervals) {
+		t.Errorf("Len() = %d, expected %d", n, len(indexIntervals))
+	}
+	names := []string{}
+	x.All()(func(e Entry[string]) bool {
+		names = append(names, e.Value)
+		return true
+	})
+	expected := []string{"a", "b", "f", "d", "c", "e"}
+	if len(names) != len(expected) {
+		t.Fatalf("All() yielded %v, expected %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("All() yielded %v, expected %v", names, expected)
+			break
+		}
+	}
+	n := 0
+	x.All()(func(e Entry[string]) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("All() yielded %d entries after stopping, expected %d", n, 2)
+	}
+}
+
+func TestBinIndexNearest(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		pos      int
+		name     string
+		distance int
+	}{
+		{0, "a", 0},
+		{120, "b", 0},
+		{150, "b", 1},
+		{60000, "b", 60000 - 149},
+		{100000, "d", 1<<17 - 5 - 100000},
+		{1<<17 + 9, "c", 0},
+		{1<<17 + 20, "c", 11},
+		{600000, "e", 400000},
+		{500000, "c", 500000 - 1<<17 - 9},
+		{900000, "e", 100000},
+		{1<<29 - 1, "e", 1<<29 - 5000000},
+	} {
+		if e, distance, error := x.Nearest(v.pos); error != nil {
+			t.Errorf("Nearest(%d) returned error: %v", v.pos, error)
+		} else if e.Value != v.name || distance != v.distance {
+			t.Errorf("Nearest(%d) = (%q, %d), expected (%q, %d)", v.pos, e.Value, distance, v.name, v.distance)
+		}
+	}
+	if e, distance, error := x.Nearest(1 << 29); error == nil {
+		t.Errorf("Nearest(%d) = (%q, %d), expected error", 1<<29, e.Value, distance)
+	}
+	empty := NewBinIndex[string](StandardBinning())
+	empty.Insert(10, 10, "empty")
+	if e, distance, error := empty.Nearest(0); error == nil {
+		t.Errorf("Nearest(%d) = (%q, %d), expected error", 0, e.Value, distance)
+	}
+}
+
+func TestBinIndexClosest(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		pos                      int
+		upstream, downstream     string
+		upDistance, downDistance int
+	}{
+		{50, "", "d", 0, 1<<17 - 55},
+		{120, "a", "d", 21, 1<<17 - 125},
+		{150, "b", "d", 1, 1<<17 - 155},
+		{1<<17 + 7, "d", "e", 3, 1000000 - 1<<17 - 7},
+		{1 << 20, "c", "", 1<<20 - 1<<17 - 9, 0},
+		{6000000, "e", "", 1000001, 0},
+	} {
+		e, distance, error := x.ClosestUpstream(v.pos)
+		if v.upstream == "" && error == nil {
+			t.Errorf("ClosestUpstream(%d) = (%q, %d), expected error", v.pos, e.Value, distance)
+		} else if v.upstream != "" && (error != nil || e.Value != v.upstream || distance != v.upDistance) {
+			t.Errorf("ClosestUpstream(%d) = (%q, %d, %v), expected (%q, %d)", v.pos, e.Value, distance, error, v.upstream, v.upDistance)
+		}
+		e, distance, error = x.ClosestDownstream(v.pos)
+		if v.downstream == "" && error == nil {
+			t.Errorf("ClosestDownstream(%d) = (%q, %d), expected error", v.pos, e.Value, distance)
+		} else if v.downstream != "" && (error != nil || e.Value != v.downstream || distance != v.downDistance) {
+			t.Errorf("ClosestDownstream(%d) = (%q, %d, %v), expected (%q, %d)", v.pos, e.Value, distance, error, v.downstream, v.downDistance)
+		}
+	}
+
+	strict := NewBinIndex[string](StandardBinning().Strict())
+	strict.Insert(10, 20, "a")
+	if _, _, error := strict.ClosestUpstream(0); error == nil || errors.Is(error, ErrEmptyInterval) {
+		t.Errorf("ClosestUpstream(%d) on strict scheme returned error %v, expected no entries", 0, error)
+	}
+	if _, _, error := strict.ClosestDownstream(1<<29 - 1); error == nil || errors.Is(error, ErrOutOfRange) {
+		t.Errorf("ClosestDownstream(%d) on strict scheme returned error %v, expected no entries", 1<<29-1, error)
+	}
+	invalid := NewBinIndex[string](Binning{})
+	if _, _, error := invalid.ClosestUpstream(0); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("ClosestUpstream(%d) on invalid scheme returned error %v, expected ErrInvalidScheme", 0, error)
+	}
+	if _, error := invalid.NearestN(0, 1); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("NearestN(%d, %d) on invalid scheme returned error %v, expected ErrInvalidScheme", 0, 1, error)
+	}
+}
+
+func TestBinIndexNearestN(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		pos, n int
+		names  []string
+	}{
+		{0, 2, []string{"a", "b"}},
+		{160, 3, []string{"b", "a", "d"}},
+		{1 << 20, 3, []string{"e", "c", "d"}},
+		{0, 10, []string{"a", "b", "d", "c", "e"}},
+		{0, 0, []string{}},
+	} {
+		entries, error := x.NearestN(v.pos, v.n)
+		if error != nil {
+			t.Errorf("NearestN(%d, %d) returned error: %v", v.pos, v.n, error)
+			continue
+		}
+		names := []string{}
+		for _, e := range entries {
+			names = append(names, e.Value)
+		}
+		if len(names) != len(v.names) {
+			t.Errorf("NearestN(%d, %d) = %v, expected %v", v.pos, v.n, names, v.names)
+			continue
+		}
+		for i := range names {
+			if names[i] != v.names[i] {
+				t.Errorf("NearestN(%d, %d) = %v, expected %v", v.pos, v.n, names, v.names)
+				break
+			}
+		}
+	}
+}
+
+func TestBinIndexSnapshot(t *testing.T) {
+	x := NewBinIndex[string](StandardBinning())
+	a, _ := x.Insert(0, 100, "a")
+	b, _ := x.Insert(50, 150, "b")
+	snapshot := x.Snapshot()
+	x.Insert(60, 70, "c")
+	x.Delete(a)
+	x.Update(b, 1<<17, 1<<17+10)
+	snapshot.Insert(80, 90, "d")
+	if names, _ := x.Overlapping(0, 1<<18); !sameElements(names, []string{"b", "c"}) {
+		t.Errorf("Overlapping(%d, %d) = %v, expected %v", 0, 1<<18, names, []string{"b", "c"})
+	}
+	if names, _ := snapshot.Overlapping(0, 200); !sameElements(names, []string{"a", "b", "d"}) {
+		t.Errorf("Snapshot().Overlapping(%d, %d) = %v, expected %v", 0, 200, names, []string{"a", "b", "d"})
+	}
+	if n := snapshot.Len(); n != 3 {
+		t.Errorf("Snapshot().Len() = %d, expected %d", n, 3)
+	}
+	if n := x.Len(); n != 2 {
+		t.Errorf("Len() = %d, expected %d", n, 2)
+	}
+}
+
+func TestBinIndexSnapshotConcurrent(t *testing.T) {
+	x := NewBinIndex[int](StandardBinning())
+	for i := 0; i < 1000; i++ {
+		x.Insert(i, i+10, i)
+	}
+	snapshot := x.Snapshot()
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			if n, _ := snapshot.CountOverlapping(0, 2000); n != 1000 {
+				t.Errorf("Snapshot().CountOverlapping(%d, %d) = %d, expected %d", 0, 2000, n, 1000)
+			}
+		}
+		done <- true
+	}()
+	for i := 0; i < 1000; i++ {
+		x.Insert(i, i+10, i)
+		x.Delete(Handle(i + 1))
+	}
+	<-done
+}
+
+func TestBinIndexMergedRegions(t *testing.T) {
+	x := newTestIndex(t)
+	x.Insert(150, 160, "g")
+	x.Insert(1<<17+10, 1<<18, "h")
+	expected := []Interval{{0, 160}, {1<<17 - 5, 1 << 18}, {1000000, 5000000}}
+	if merged := x.MergedRegions(); !equalIntervals(merged, expected) {
+		t.Errorf("MergedRegions() = %v, expected %v", merged, expected)
+	}
+	if merged := NewBinIndex[string](StandardBinning()).MergedRegions(); len(merged) != 0 {
+		t.Errorf("MergedRegions() = %v, expected %v", merged, []Interval{})
+	}
+}
+
+func TestBinIndexSubtract(t *testing.T) {
+	x := newTestIndex(t)
+	entries := x.Subtract([]Interval{{20, 30}, {90, 120}, {25, 40}, {1<<17 - 10, 1<<17 + 20}, {4000000, 6000000}})
+	expected := []struct {
+		name        string
+		start, stop int
+	}{
+		{"a", 0, 20},
+		{"a", 40, 90},
+		{"b", 50, 90},
+		{"b", 120, 150},
+		{"e", 1000000, 4000000},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Subtract() returned %d entries, expected %d", len(entries), len(expected))
+	}
+	for i, v := range expected {
+		if e := entries[i]; e.Value != v.name || e.Start != v.start || e.Stop != v.stop {
+			t.Errorf("Subtract()[%d] = (%q, %d, %d), expected (%q, %d, %d)", i, e.Value, e.Start, e.Stop, v.name, v.start, v.stop)
+		}
+	}
+	if n := len(x.Subtract(nil)); n != 5 {
+		t.Errorf("len(Subtract(nil)) = %d, expected %d", n, 5)
+	}
+	if n := x.Len(); n != len(indexIntervals) {
+		t.Errorf("Len() = %d, expected %d", n, len(indexIntervals))
+	}
+}
+
+func TestBinIndexCoverage(t *testing.T) {
+	x := newTestIndex(t)
+	depth, error := x.Coverage(95, 155)
+	if error != nil {
+		t.Fatalf("Coverage(%d, %d) returned error: %v", 95, 155, error)
+	}
+	if len(depth) != 60 {
+		t.Fatalf("len(Coverage(%d, %d)) = %d, expected %d", 95, 155, len(depth), 60)
+	}
+	for i, d := range depth {
+		expected := 0
+		if pos := 95 + i; pos < 100 {
+			expected = 2
+		} else if pos < 150 {
+			expected = 1
+		}
+		if d != expected {
+			t.Errorf("Coverage(%d, %d)[%d] = %d, expected %d", 95, 155, i, d, expected)
+		}
+	}
+	windows, error := x.CoverageWindows(0, 250, 100)
+	if error != nil {
+		t.Fatalf("CoverageWindows(%d, %d, %d) returned error: %v", 0, 250, 100, error)
+	}
+	expected := []float64{1.5, 0.5, 0}
+	if len(windows) != len(expected) {
+		t.Fatalf("CoverageWindows(%d, %d, %d) = %v, expected %v", 0, 250, 100, windows, expected)
+	}
+	for i := range expected {
+		if windows[i] != expected[i] {
+			t.Errorf("CoverageWindows(%d, %d, %d) = %v, expected %v", 0, 250, 100, windows, expected)
+			break
+		}
+	}
+	if depth, error := x.Coverage(10, 5); error == nil {
+		t.Errorf("Coverage(%d, %d) = %v, expected error", 10, 5, depth)
+	}
+	if windows, error := x.CoverageWindows(0, 10, 0); error == nil {
+		t.Errorf("CoverageWindows(%d, %d, %d) = %v, expected error", 0, 10, 0, windows)
+	}
+}
+
+func TestBinIndexAppend(t *testing.T) {
+	x := newTestIndex(t)
+	buffer := make([]string, 0, 10)
+	names, error := x.AppendOverlapping(buffer[:0], 0, 200)
+	if error != nil {
+		t.Fatalf("AppendOverlapping(%d, %d) returned error: %v", 0, 200, error)
+	}
+	if !sameElements(names, []string{"a", "b"}) || &names[0] != &buffer[:1][0] {
+		t.Errorf("AppendOverlapping(%d, %d) = %v, expected %v in buffer", 0, 200, names, []string{"a", "b"})
+	}
+	names, _ = x.AppendAt(names, 1<<17)
+	if !sameElements(names, []string{"a", "b", "c", "d"}) {
+		t.Errorf("AppendAt(%d) = %v, expected %v", 1<<17, names, []string{"a", "b", "c", "d"})
+	}
+	if names, error := x.AppendAt(names, -1); error == nil || len(names) != 4 {
+		t.Errorf("AppendAt(%d) = (%v, %v), expected unchanged slice and error", -1, names, error)
+	}
+}
+
+func TestBinIndexGaps(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		start, stop int
+		gaps        []Interval
+	}{
+		{0, 100, []Interval{}},
+		{0, 300, []Interval{{150, 300}}},
+		{150, 300, []Interval{{150, 300}}},
+		{1<<17 - 10, 1<<17 + 20, []Interval{{1<<17 - 10, 1<<17 - 5}, {1<<17 + 10, 1<<17 + 20}}},
+		{100, 100, []Interval{}},
+	} {
+		if gaps, error := x.Gaps(v.start, v.stop); error != nil {
+			t.Errorf("Gaps(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if !equalIntervals(gaps, v.gaps) {
+			t.Errorf("Gaps(%d, %d) = %v, expected %v", v.start, v.stop, gaps, v.gaps)
+		}
+	}
+	if gaps, error := x.Gaps(-1, 10); error == nil {
+		t.Errorf("Gaps(%d, %d) = %v, expected error", -1, 10, gaps)
+	}
+}
+
+func TestBinIndexCluster(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		maxGap   int
+		clusters [][]string
+	}{
+		{0, [][]string{{"a", "b"}, {"f"}, {"d", "c"}, {"e"}}},
+		{50, [][]string{{"a", "b", "f"}, {"d", "c"}, {"e"}}},
+		{1000000, [][]string{{"a", "b", "f", "d", "c", "e"}}},
+	} {
+		clusters, error := x.Cluster(v.maxGap)
+		if error != nil {
+			t.Errorf("Cluster(%d) returned error: %v", v.maxGap, error)
+			continue
+		}
+		names := [][]string{}
+		for _, cluster := range clusters {
+			names = append(names, []string{})
+			for _, e := range cluster {
+				names[len(names)-1] = append(names[len(names)-1], e.Value)
+			}
+		}
+		if fmt.Sprint(names) != fmt.Sprint(v.clusters) {
+			t.Errorf("Cluster(%d) = %v, expected %v", v.maxGap, names, v.clusters)
+		}
+	}
+	if clusters, error := x.Cluster(-1); error == nil {
+		t.Errorf("Cluster(%d) = %v, expected error", -1, clusters)
+	}
+}
+
+func TestReduceByBin(t *testing.T) {
+	x := newTestIndex(t)
+	for _, v := range []struct {
+		level  int
+		counts map[int]int
+	}{
+		{0, map[int]int{585: 4, 586: 1, 592: 1}},
+		{1, map[int]int{73: 6}},
+		{4, map[int]int{0: 6}},
+	} {
+		counts, error := ReduceByBin(x, v.level, func(bin int, names []string) int { return len(names) })
+		if error != nil {
+			t.Errorf("ReduceByBin(%d) returned error: %v", v.level, error)
+			continue
+		}
+		if fmt.Sprint(counts) != fmt.Sprint(v.counts) {
+			t.Errorf("ReduceByBin(%d) = %v, expected %v", v.level, counts, v.counts)
+		}
+	}
+	for _, level := range []int{-1, 5} {
+		if counts, error := ReduceByBin(x, level, func(bin int, names []string) int { return len(names) }); error == nil {
+			t.Errorf("ReduceByBin(%d) = %v, expected error", level, counts)
+		}
+	}
+}
+
+// equalIntervals reports whether a and b are equal.
+func equalIntervals(a, b []Interval) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameElements reports whether a and b contain the same elements, in any
+// order.
+func sameElements(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, s := range a {
+		counts[s]++
+	}
+	for _, s := range b {
+		counts[s]--
+		if counts[s] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package binning
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// indexMagic starts the binary representation of a BinIndex.
+const indexMagic = "BINIDX"
+
+// indexVersion is the version of the binary representation of a BinIndex
+// written by WriteTo.
+const indexVersion = 1
+
+// maxSchemeLength is the maximum length of the binning scheme in the binary
+// representation of a BinIndex, well above the length of any scheme written
+// by WriteTo, to avoid large allocations on corrupt input.
+const maxSchemeLength = 1 << 16
+
+// indexData is the gob-encoded part of the binary representation of a
+// BinIndex.
+type indexData[T any] struct {
+	Next    Handle
+	Entries []Entry[T]
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// WriteTo implements the io.WriterTo interface. The index is written with a
+// header containing a format version and the binning scheme as JSON,
+// followed by the entries and handles encoded with encoding/gob, so values
+// must be of a type that gob can encode.
+func (x *BinIndex[T]) WriteTo(w io.Writer) (int64, error) {
+	c := &countingWriter{w: w}
+
+	scheme, err := json.Marshal(x.scheme)
+	if err != nil {
+		return c.n, err
+	}
+	header := append([]byte(indexMagic), indexVersion)
+	header = binary.BigEndian.AppendUint32(header, uint32(len(scheme)))
+	if _, err := c.Write(append(header, scheme...)); err != nil {
+		return c.n, err
+	}
+
+	data := indexData[T]{Next: x.next, Entries: make([]Entry[T], 0, x.Len())}
+	x.All()(func(e Entry[T]) bool {
+		data.Entries = append(data.Entries, e)
+		return true
+	})
+	err = gob.NewEncoder(c).Encode(data)
+	return c.n, err
+}
+
+// ReadFrom implements the io.ReaderFrom interface. It replaces the contents
+// and binning scheme of the index with an index written by WriteTo. Handles
+// remain valid. Since the entries are decoded with encoding/gob, more bytes
+// than written by WriteTo may be read from r.
+func (x *BinIndex[T]) ReadFrom(r io.Reader) (int64, error) {
+	c := &countingReader{r: r}
+
+	header := make([]byte, len(indexMagic)+5)
+	if _, err := io.ReadFull(c, header); err != nil {
+		return c.n, err
+	}
+	if string(header[:len(indexMagic)]) != indexMagic {
+		return c.n, errors.New("not a binary index")
+	}
+	if version := header[len(indexMagic)]; version != indexVersion {
+		return c.n, errors.New(fmt.Sprintf("unsupported binary index version: %d", version))
+	}
+	length := binary.BigEndian.Uint32(header[len(indexMagic)+1:])
+	if length > maxSchemeLength {
+		return c.n, errors.New(fmt.Sprintf("binning scheme too long in binary index: %d bytes", length))
+	}
+	scheme := make([]byte, length)
+	if _, err := io.ReadFull(c, scheme); err != nil {
+		return c.n, err
+	}
+	b, err := ParseScheme(scheme)
+	if err != nil {
+		return c.n, err
+	}
+
+	var data indexData[T]
+	if err := gob.NewDecoder(c).Decode(&data); err != nil {
+		return c.n, err
+	}
+	index := NewBinIndex[T](b)
+	index.next = data.Next
+	for _, e := range data.Entries {
+		bin, err := b.Assign(e.Start, e.Stop)
+		if err != nil {
+			return c.n, err
+		}
+		if _, ok := index.handles[e.Handle]; ok || e.Handle == 0 || e.Handle > data.Next {
+			return c.n, errors.New(fmt.Sprintf("invalid handle in binary index: %d", e.Handle))
+		}
+		index.bins[bin] = append(index.bins[bin], e)
+		index.handles[e.Handle] = bin
+	}
+
+	*x = *index
+	return c.n, nil
+}
@@ -0,0 +1,59 @@
+package binning
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBinIndexWriteTo(t *testing.T) {
+	x := newTestIndex(t)
+	var buffer bytes.Buffer
+	n, error := x.WriteTo(&buffer)
+	if error != nil {
+		t.Fatalf("WriteTo() returned error: %v", error)
+	}
+	if n != int64(buffer.Len()) {
+		t.Errorf("WriteTo() = %d, expected %d", n, buffer.Len())
+	}
+	data := buffer.Bytes()
+
+	y := NewBinIndex[string](TabixBinning())
+	if _, error := y.ReadFrom(bytes.NewReader(data)); error != nil {
+		t.Fatalf("ReadFrom() returned error: %v", error)
+	}
+	if !equalSchemes(y.Scheme(), StandardBinning()) {
+		t.Errorf("ReadFrom().Scheme() = %v, expected %v", y.Scheme(), StandardBinning())
+	}
+	if n := y.Len(); n != x.Len() {
+		t.Errorf("ReadFrom().Len() = %d, expected %d", n, x.Len())
+	}
+	for _, v := range []struct{ start, stop int }{{0, 1}, {99, 100}, {0, 1 << 29}} {
+		expected, _ := x.Overlapping(v.start, v.stop)
+		if names, _ := y.Overlapping(v.start, v.stop); !sameElements(names, expected) {
+			t.Errorf("ReadFrom().Overlapping(%d, %d) = %v, expected %v", v.start, v.stop, names, expected)
+		}
+	}
+	if error := y.Delete(2); error != nil {
+		t.Errorf("ReadFrom().Delete(%d) returned error: %v", 2, error)
+	}
+	if h, _ := y.Insert(0, 1, "g"); h != Handle(len(indexIntervals)+1) {
+		t.Errorf("ReadFrom().Insert(%d, %d, %q) = %d, expected %d", 0, 1, "g", h, len(indexIntervals)+1)
+	}
+
+	for _, invalid := range [][]byte{
+		nil,
+		[]byte("BINIDY"),
+		append([]byte("BINIDX"), 2, 0, 0, 0, 0),
+		append([]byte("BINIDX"), 1, 0xff, 0xff, 0xff, 0xff),
+		data[:len(data)-1],
+	} {
+		z := NewBinIndex[string](StandardBinning())
+		if _, error := z.ReadFrom(bytes.NewReader(invalid)); error == nil {
+			t.Errorf("ReadFrom(%q) returned no error, expected error", invalid)
+		}
+	}
+	z := NewBinIndex[int](StandardBinning())
+	if _, error := z.ReadFrom(bytes.NewReader(data)); error == nil {
+		t.Errorf("ReadFrom() with different value type returned no error, expected error")
+	}
+}
@@ -0,0 +1,62 @@
+package binning
+
+import (
+	"errors"
+	"slices"
+)
+
+// sortedBins returns the bins in use in index x in ascending order.
+func (x *BinIndex[T]) sortedBins() []int {
+	bins := make([]int, 0, len(x.bins))
+	for bin := range x.bins {
+		bins = append(bins, bin)
+	}
+	slices.Sort(bins)
+	return bins
+}
+
+// Intersect returns an iterator over all pairs of entries from a and b with
+// intervals overlapping by at least one position. Only bins that can contain
+// overlapping intervals are compared: for each bin in a, the bin itself and
+// the bins containing it in b, and for each bin in b, the bins containing it
+// in a. Pairs are yielded grouped by bin. It returns an error if a and b use
+// different binning schemes. The indexes must not be modified during
+// iteration.
+func Intersect[T, U any](a *BinIndex[T], b *BinIndex[U]) (func(yield func(Entry[T], Entry[U]) bool), error) {
+	if !equalSchemes(a.scheme, b.scheme) {
+		return nil, errors.New("indexes use different binning schemes")
+	}
+	s := a.scheme
+
+	return func(yield func(Entry[T], Entry[U]) bool) {
+		// Entries from b in the same bin as or a larger bin than the entry
+		// from a.
+		for _, bin := range a.sortedBins() {
+			level := s.level(bin)
+			start := (bin - s.binOffsets[level]) << s.shifts[level]
+			for l := level; l < len(s.shifts); l++ {
+				for _, ea := range a.bins[bin] {
+					for _, eb := range b.bins[s.binOffsets[l]+start>>s.shifts[l]] {
+						if max(ea.Start, eb.Start) < min(ea.Stop, eb.Stop) && !yield(ea, eb) {
+							return
+						}
+					}
+				}
+			}
+		}
+		// Entries from b in a smaller bin than the entry from a.
+		for _, bin := range b.sortedBins() {
+			level := s.level(bin)
+			start := (bin - s.binOffsets[level]) << s.shifts[level]
+			for l := level + 1; l < len(s.shifts); l++ {
+				for _, eb := range b.bins[bin] {
+					for _, ea := range a.bins[s.binOffsets[l]+start>>s.shifts[l]] {
+						if max(ea.Start, eb.Start) < min(ea.Stop, eb.Stop) && !yield(ea, eb) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,46 @@
+package binning
+
+import "testing"
+
+func TestIntersect(t *testing.T) {
+	a := newTestIndex(t)
+	b := NewBinIndex[int](StandardBinning())
+	for i, v := range []struct{ start, stop int }{
+		{90, 95},
+		{140, 1 << 17},
+		{0, 1 << 29},
+		{4999999, 5000001},
+		{300, 400},
+	} {
+		b.Insert(v.start, v.stop, i)
+	}
+	next, error := Intersect(a, b)
+	if error != nil {
+		t.Fatalf("Intersect() returned error: %v", error)
+	}
+	pairs := map[string]int{}
+	next(func(ea Entry[string], eb Entry[int]) bool {
+		pairs[ea.Value+string(rune('0'+eb.Value))]++
+		return true
+	})
+	expected := []string{"a0", "b0", "b1", "d1", "a2", "b2", "c2", "d2", "e2", "e3"}
+	if len(pairs) != len(expected) {
+		t.Errorf("Intersect() yielded %v, expected %v", pairs, expected)
+	}
+	for _, pair := range expected {
+		if pairs[pair] != 1 {
+			t.Errorf("Intersect() yielded %q %d times, expected %d", pair, pairs[pair], 1)
+		}
+	}
+	n := 0
+	next(func(ea Entry[string], eb Entry[int]) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Intersect() yielded %d pairs after stopping, expected %d", n, 1)
+	}
+	if _, error := Intersect(a, NewBinIndex[int](TabixBinning())); error == nil {
+		t.Errorf("Intersect() with different schemes returned no error, expected error")
+	}
+}
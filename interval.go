@@ -0,0 +1,32 @@
+package binning
+
+import (
+	"cmp"
+	"slices"
+)
+
+// An Interval is the interval Start:Stop.
+type Interval struct {
+	Start, Stop int
+}
+
+// mergeIntervals returns the union of intervals as a sorted list of
+// non-overlapping, non-adjacent intervals. Empty intervals are ignored. The
+// intervals are sorted in place.
+func mergeIntervals(intervals []Interval) []Interval {
+	slices.SortFunc(intervals, func(a, b Interval) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+	merged := []Interval{}
+	for _, i := range intervals {
+		switch {
+		case i.Stop <= i.Start:
+			continue
+		case len(merged) > 0 && i.Start <= merged[len(merged)-1].Stop:
+			merged[len(merged)-1].Stop = max(merged[len(merged)-1].Stop, i.Stop)
+		default:
+			merged = append(merged, i)
+		}
+	}
+	return merged
+}
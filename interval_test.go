@@ -0,0 +1,19 @@
+package binning
+
+import "testing"
+
+func TestMergeIntervals(t *testing.T) {
+	for _, v := range []struct {
+		intervals, merged []Interval
+	}{
+		{[]Interval{}, []Interval{}},
+		{[]Interval{{5, 10}, {0, 3}}, []Interval{{0, 3}, {5, 10}}},
+		{[]Interval{{5, 10}, {0, 5}}, []Interval{{0, 10}}},
+		{[]Interval{{0, 10}, {2, 4}, {8, 12}}, []Interval{{0, 12}}},
+		{[]Interval{{3, 3}, {0, 1}}, []Interval{{0, 1}}},
+	} {
+		if merged := mergeIntervals(append([]Interval(nil), v.intervals...)); !equalIntervals(merged, v.merged) {
+			t.Errorf("mergeIntervals(%v) = %v, expected %v", v.intervals, merged, v.merged)
+		}
+	}
+}
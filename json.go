@@ -0,0 +1,156 @@
+package binning
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// schemeDescriptor is the JSON representation of a binning scheme. Either
+// ShiftFirst and ShiftNext or Shifts must be set, and either BinOffsets or
+// Levels must be set unless Shifts is set.
+type schemeDescriptor[T Integer] struct {
+	MaxPosition T      `json:"maxPosition"`
+	BinOffsets  []T    `json:"binOffsets,omitempty"`
+	Levels      int    `json:"levels,omitempty"`
+	ShiftFirst  uint   `json:"shiftFirst,omitempty"`
+	ShiftNext   uint   `json:"shiftNext,omitempty"`
+	Shifts      []uint `json:"shifts,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b Scheme[T]) MarshalJSON() ([]byte, error) {
+	d := schemeDescriptor[T]{
+		MaxPosition: b.MaxPosition,
+		BinOffsets:  b.binOffsets,
+		Shifts:      b.shifts,
+	}
+	if len(b.shifts) > 1 && slices.Equal(b.shifts, uniformShifts(len(b.shifts), b.shifts[0], b.shifts[1]-b.shifts[0])) {
+		d.ShiftFirst, d.ShiftNext, d.Shifts = b.shifts[0], b.shifts[1]-b.shifts[0], nil
+	}
+	return json.Marshal(d)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. See ParseScheme
+// for a description of the accepted document.
+func (b *Scheme[T]) UnmarshalJSON(data []byte) error {
+	var d schemeDescriptor[T]
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&d); err != nil {
+		return err
+	}
+
+	var scheme Scheme[T]
+	var err error
+
+	switch {
+	case d.BinOffsets != nil && d.Levels != 0:
+		return errors.New("binning scheme must not have both binOffsets and levels")
+	case d.Shifts != nil && (d.ShiftFirst != 0 || d.ShiftNext != 0):
+		return errors.New("binning scheme must not have both shifts and shiftFirst or shiftNext")
+	case d.Shifts != nil && d.Levels != 0 && d.Levels != len(d.Shifts):
+		return errors.New(fmt.Sprintf("binning scheme has %d levels but %d shifts", d.Levels, len(d.Shifts)))
+	case d.Shifts != nil:
+		scheme, err = NewSchemeFromLevels(d.MaxPosition, d.BinOffsets, d.Shifts)
+	case d.BinOffsets != nil:
+		scheme, err = NewValidatedScheme(d.MaxPosition, d.BinOffsets, d.ShiftFirst, d.ShiftNext)
+	case d.Levels != 0:
+		scheme, err = NewSchemeFromShifts(d.Levels, d.ShiftFirst, d.ShiftNext, d.MaxPosition)
+	default:
+		return errors.New("binning scheme must have either binOffsets, levels or shifts")
+	}
+	if err != nil {
+		return err
+	}
+
+	*b = scheme
+	return nil
+}
+
+// ParseScheme parses a binning scheme from a JSON document such as:
+//
+//	{"maxPosition": 536870911, "levels": 5, "shiftFirst": 17, "shiftNext": 3}
+//
+// Instead of the number of levels, the first bin number per level can be
+// given as "binOffsets". Instead of "shiftFirst" and "shiftNext", how much to
+// shift to get to the bin per level can be given as "shifts". The parameters
+// are validated as with NewValidatedBinning. The result of marshaling a
+// Binning to JSON can be parsed by ParseScheme.
+func ParseScheme(data []byte) (Binning, error) {
+	var b Binning
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Binning{}, err
+	}
+	return b, nil
+}
+
+// genomeDescriptor is the JSON representation of a genome binning.
+type genomeDescriptor struct {
+	Chromosomes []chromosomeDescriptor `json:"chromosomes"`
+}
+
+// chromosomeDescriptor is the JSON representation of a chromosome in a
+// genome binning. Scheme is optional when unmarshaling.
+type chromosomeDescriptor struct {
+	Name    string   `json:"name"`
+	Length  int      `json:"length"`
+	Aliases []string `json:"aliases,omitempty"`
+	Scheme  *Binning `json:"scheme,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. Chromosomes are
+// written in order, with their length, aliases and binning scheme.
+func (g GenomeBinning) MarshalJSON() ([]byte, error) {
+	aliases := g.chromosomeAliases()
+	d := genomeDescriptor{Chromosomes: make([]chromosomeDescriptor, len(g.chromosomes))}
+	for i, c := range g.chromosomes {
+		d.Chromosomes[i] = chromosomeDescriptor{Name: c.Name, Length: c.Length, Aliases: aliases[i], Scheme: &g.schemes[i]}
+	}
+	return json.Marshal(d)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The genome
+// binning is created as with NewGenomeBinning and WithAliases. If a binning
+// scheme is given for a chromosome, it must be the scheme that
+// NewGenomeBinning chooses for that chromosome.
+func (g *GenomeBinning) UnmarshalJSON(data []byte) error {
+	var d genomeDescriptor
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&d); err != nil {
+		return err
+	}
+
+	chromosomes := make([]Chromosome, len(d.Chromosomes))
+	aliases := make(Aliases)
+	for i, c := range d.Chromosomes {
+		chromosomes[i] = Chromosome{Name: c.Name, Length: c.Length}
+		for _, alias := range c.Aliases {
+			if _, ok := aliases[alias]; ok {
+				return errors.New(fmt.Sprintf("duplicate alias: %q", alias))
+			}
+			aliases[alias] = c.Name
+		}
+	}
+
+	genome, err := NewGenomeBinning(chromosomes)
+	if err != nil {
+		return err
+	}
+	if genome, err = genome.WithAliases(aliases); err != nil {
+		return err
+	}
+	for i, c := range d.Chromosomes {
+		if c.Scheme != nil && !equalSchemes(*c.Scheme, genome.schemes[i]) {
+			return errors.New(fmt.Sprintf("binning scheme for chromosome %q does not match the scheme used for its length", c.Name))
+		}
+	}
+
+	*g = genome
+	return nil
+}
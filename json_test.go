@@ -0,0 +1,116 @@
+package binning
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseScheme(t *testing.T) {
+	for _, v := range []struct {
+		data   string
+		scheme Binning
+	}{
+		{`{"maxPosition": 536870911, "levels": 5, "shiftFirst": 17, "shiftNext": 3}`, StandardBinning()},
+		{`{"maxPosition": 536870911, "binOffsets": [4681, 585, 73, 9, 1, 0], "shiftFirst": 14, "shiftNext": 3}`, TabixBinning()},
+		{`{"maxPosition": 536870911, "shifts": [17, 20, 23, 26, 29]}`, StandardBinning()},
+	} {
+		scheme, error := ParseScheme([]byte(v.data))
+		if error != nil {
+			t.Errorf("ParseScheme(%s) returned error: %v", v.data, error)
+			continue
+		}
+		if scheme.MaxPosition != v.scheme.MaxPosition || scheme.MinBin != v.scheme.MinBin || scheme.MaxBin != v.scheme.MaxBin {
+			t.Errorf("ParseScheme(%s) = %v, expected %v", v.data, scheme, v.scheme)
+		}
+	}
+}
+
+func TestParseSchemeInvalid(t *testing.T) {
+	for _, data := range []string{
+		``,
+		`[]`,
+		`{"maxPosition": 536870911, "shiftFirst": 17, "shiftNext": 3}`,
+		`{"maxPosition": 536870911, "levels": 4, "shiftFirst": 17, "shiftNext": 3}`,
+		`{"maxPosition": 536870911, "levels": 5, "binOffsets": [585, 73, 9, 1, 0], "shiftFirst": 17, "shiftNext": 3}`,
+		`{"maxPosition": 536870911, "binOffsets": [584, 73, 9, 1, 0], "shiftFirst": 17, "shiftNext": 3}`,
+		`{"maxPosition": 536870911, "levels": 5, "shiftFirst": 17, "shiftNext": 3, "shiftLast": 3}`,
+		`{"maxPosition": 536870911, "shifts": [17, 20, 23, 26, 29], "shiftFirst": 17}`,
+		`{"maxPosition": 536870911, "shifts": [17, 20, 23, 26, 29], "levels": 4}`,
+	} {
+		if scheme, error := ParseScheme([]byte(data)); error == nil {
+			t.Errorf("ParseScheme(%s) = %v, expected error", data, scheme)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	irregular, _ := NewBinningFromLevels(1<<29-1, nil, []uint{12, 16, 23, 29})
+	for _, b := range []Binning{StandardBinning(), TabixBinning(), FineBinning(), irregular} {
+		data, error := json.Marshal(b)
+		if error != nil {
+			t.Errorf("json.Marshal(%v) returned error: %v", b, error)
+			continue
+		}
+		scheme, error := ParseScheme(data)
+		if error != nil {
+			t.Errorf("ParseScheme(%s) returned error: %v", data, error)
+			continue
+		}
+		if scheme.MaxPosition != b.MaxPosition || scheme.MinBin != b.MinBin || scheme.MaxBin != b.MaxBin {
+			t.Errorf("ParseScheme(%s) = %v, expected %v", data, scheme, b)
+		}
+		for i, offset := range scheme.binOffsets {
+			if offset != b.binOffsets[i] {
+				t.Errorf("ParseScheme(%s).binOffsets[%d] = %d, expected %d", data, i, offset, b.binOffsets[i])
+			}
+		}
+		for i, shift := range scheme.shifts {
+			if shift != b.shifts[i] {
+				t.Errorf("ParseScheme(%s).shifts[%d] = %d, expected %d", data, i, shift, b.shifts[i])
+			}
+		}
+	}
+}
+
+func TestGenomeMarshalJSON(t *testing.T) {
+	g := GRCh38()
+	data, error := json.Marshal(g)
+	if error != nil {
+		t.Fatalf("json.Marshal(GRCh38()) returned error: %v", error)
+	}
+	var parsed GenomeBinning
+	if error := json.Unmarshal(data, &parsed); error != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, error)
+	}
+	chromosomes := parsed.Chromosomes()
+	for i, c := range g.Chromosomes() {
+		if chromosomes[i] != c {
+			t.Errorf("json.Unmarshal(%s).Chromosomes()[%d] = %v, expected %v", data, i, chromosomes[i], c)
+		}
+	}
+	if aliases := parsed.Aliases(); len(aliases) != len(g.Aliases()) || aliases["NC_012920.1"] != "chrM" {
+		t.Errorf("json.Unmarshal(%s).Aliases() = %v, expected %v", data, aliases, g.Aliases())
+	}
+}
+
+func TestGenomeUnmarshalJSON(t *testing.T) {
+	data := `{"chromosomes": [{"name": "chr1", "length": 248956422, "aliases": ["1"]}, {"name": "chrM", "length": 16569}]}`
+	var g GenomeBinning
+	if error := json.Unmarshal([]byte(data), &g); error != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %v", data, error)
+	}
+	if name, error := g.Canonical("1"); error != nil || name != "chr1" {
+		t.Errorf("json.Unmarshal(%s).Canonical(%q) = (%q, %v), expected %q", data, "1", name, error, "chr1")
+	}
+	for _, data := range []string{
+		`{"chromosomes": [{"name": "chr1", "length": 0}]}`,
+		`{"chromosomes": [{"name": "chr1", "length": 100, "size": 100}]}`,
+		`{"chromosomes": [{"name": "chr1", "length": 100, "aliases": ["1", "1"]}]}`,
+		`{"chromosomes": [{"name": "chr1", "length": 100, "scheme": {"maxPosition": 536870911, "binOffsets": [4681, 585, 73, 9, 1, 0], "shiftFirst": 14, "shiftNext": 3}}]}`,
+	} {
+		var g GenomeBinning
+		if error := json.Unmarshal([]byte(data), &g); error == nil {
+			t.Errorf("json.Unmarshal(%s) returned no error, expected error", data)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package binning
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// KeySize is the size in bytes of a key encoded by EncodeKey.
+const KeySize = 12
+
+// EncodeKey returns a key for an interval starting at start in bin on the
+// chromosome with number chrom, for ordered key-value stores such as Pebble,
+// LevelDB or FoundationDB. The fields are encoded big-endian, so keys sort by
+// chromosome, bin and start. An identifier can be appended to the key to
+// make it unique.
+func EncodeKey(chrom, bin, start uint32) []byte {
+	return AppendKey(make([]byte, 0, KeySize), chrom, bin, start)
+}
+
+// AppendKey appends the key encoded by EncodeKey to dst.
+func AppendKey(dst []byte, chrom, bin, start uint32) []byte {
+	dst = binary.BigEndian.AppendUint32(dst, chrom)
+	dst = binary.BigEndian.AppendUint32(dst, bin)
+	return binary.BigEndian.AppendUint32(dst, start)
+}
+
+// DecodeKey returns the chromosome, bin and start encoded in key by
+// EncodeKey, and the remainder of the key.
+func DecodeKey(key []byte) (chrom, bin, start uint32, rest []byte, err error) {
+	if len(key) < KeySize {
+		return 0, 0, 0, nil, errors.New(fmt.Sprintf("key too short: %d bytes", len(key)))
+	}
+	return binary.BigEndian.Uint32(key), binary.BigEndian.Uint32(key[4:]), binary.BigEndian.Uint32(key[8:]), key[KeySize:], nil
+}
+
+// A KeyRange is a range of keys from Start up to but not including End.
+type KeyRange struct {
+	Start, End []byte
+}
+
+// OverlappingKeyRanges returns the ranges of keys encoded by EncodeKey to
+// scan for intervals overlapping the interval start:stop on the chromosome
+// with number chrom, one per bin in the order of Overlapping. Ranges cover
+// the intervals in the bin starting before stop. Intervals in the ranges
+// still need to be checked for ending after start.
+func (b Scheme[T]) OverlappingKeyRanges(chrom uint32, start, stop T) ([]KeyRange, error) {
+	bins, err := b.Overlapping(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	last := max(stop, start+1)
+	if uint64(last) > math.MaxUint32 || uint64(b.MaxBin) > math.MaxUint32 {
+		return nil, errors.New(fmt.Sprintf("interval %d-%d does not fit in keys", start, stop))
+	}
+
+	ranges := make([]KeyRange, len(bins))
+	for i, bin := range bins {
+		first, _, err := b.Covered(bin)
+		if err != nil {
+			return nil, err
+		}
+		ranges[i] = KeyRange{EncodeKey(chrom, uint32(bin), uint32(first)), EncodeKey(chrom, uint32(bin), uint32(last))}
+	}
+	return ranges, nil
+}
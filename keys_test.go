@@ -0,0 +1,58 @@
+package binning
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestEncodeKey(t *testing.T) {
+	key := EncodeKey(1, 585, 100)
+	if expected := []byte{0, 0, 0, 1, 0, 0, 2, 73, 0, 0, 0, 100}; !bytes.Equal(key, expected) {
+		t.Errorf("EncodeKey = %v, expected %v", key, expected)
+	}
+
+	chrom, bin, start, rest, error := DecodeKey(append(key, 'x'))
+	if error != nil || chrom != 1 || bin != 585 || start != 100 || string(rest) != "x" {
+		t.Errorf("DecodeKey = %d, %d, %d, %q, %v", chrom, bin, start, rest, error)
+	}
+	if _, _, _, _, error := DecodeKey(key[:11]); error == nil {
+		t.Errorf("DecodeKey with short key did not return error")
+	}
+
+	keys := [][]byte{EncodeKey(2, 0, 0), EncodeKey(1, 585, 256), EncodeKey(1, 585, 255), EncodeKey(1, 73, 1000)}
+	slices.SortFunc(keys, bytes.Compare)
+	if _, bin, start, _, _ := DecodeKey(keys[0]); bin != 73 || start != 1000 {
+		t.Errorf("smallest key has bin %d and start %d, expected 73 and 1000", bin, start)
+	}
+}
+
+func TestOverlappingKeyRanges(t *testing.T) {
+	b := StandardBinning()
+	ranges, error := b.OverlappingKeyRanges(3, 1<<17+10, 1<<17+20)
+	if error != nil {
+		t.Fatalf("OverlappingKeyRanges error: %v", error)
+	}
+	if len(ranges) != 5 {
+		t.Fatalf("OverlappingKeyRanges returned %d ranges, expected 5", len(ranges))
+	}
+	if r := ranges[0]; !bytes.Equal(r.Start, EncodeKey(3, 586, 1<<17)) || !bytes.Equal(r.End, EncodeKey(3, 586, 1<<17+20)) {
+		t.Errorf("OverlappingKeyRanges first range = %v, expected bin 586 from %d to %d", r, 1<<17, 1<<17+20)
+	}
+
+	// A key for an overlapping interval with an identifier is in a range,
+	// one for an interval starting at stop is not.
+	inside := append(EncodeKey(3, 73, 1<<17+19), "id"...)
+	outside := append(EncodeKey(3, 73, 1<<17+20), "id"...)
+	r := ranges[1]
+	if bytes.Compare(inside, r.Start) < 0 || bytes.Compare(inside, r.End) >= 0 {
+		t.Errorf("key %v not in range %v", inside, r)
+	}
+	if bytes.Compare(outside, r.End) < 0 {
+		t.Errorf("key %v in range %v", outside, r)
+	}
+
+	if _, error := b.OverlappingKeyRanges(3, 0, 1<<30); error == nil {
+		t.Errorf("OverlappingKeyRanges(0, 1<<30) did not return error")
+	}
+}
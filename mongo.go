@@ -0,0 +1,75 @@
+package binning
+
+// mongoRange returns a MongoDB condition matching values from first up to and
+// including last.
+func mongoRange[T Integer](first, last T) map[string]any {
+	if first == last {
+		return map[string]any{"$eq": int64(first)}
+	}
+	return map[string]any{"$gte": int64(first), "$lte": int64(last)}
+}
+
+// OverlappingMongoFilter returns a MongoDB query filter selecting documents
+// with an interval overlapping the interval start:stop, with the bin in field
+// and positions in the start and stop fields. The filter can be passed to
+// the MongoDB driver as is. See OverlappingMongoFilterFields for other
+// position fields.
+func (b Scheme[T]) OverlappingMongoFilter(field string, start, stop T) (map[string]any, error) {
+	return b.OverlappingMongoFilterFields(field, "start", "stop", start, stop)
+}
+
+// OverlappingMongoFilterFields returns a MongoDB query filter like
+// OverlappingMongoFilter with positions in the startField and stopField
+// fields.
+func (b Scheme[T]) OverlappingMongoFilterFields(field, startField, stopField string, start, stop T) (map[string]any, error) {
+	plan, err := b.Plan(Overlaps, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return plan.MongoFilter(field, startField, stopField), nil
+}
+
+// MongoFilter returns a MongoDB query filter selecting documents following
+// the plan, with the bin in field and positions in the startField and
+// stopField fields. Bins are matched with $in, or with $or over ranges of
+// bins if that is shorter, as for large intervals.
+func (p QueryPlan[T]) MongoFilter(field, startField, stopField string) map[string]any {
+	var filter map[string]any
+	switch p.Relation {
+	case Contains:
+		filter = map[string]any{
+			startField: map[string]any{"$lte": int64(p.Start)},
+			stopField:  map[string]any{"$gte": int64(p.Stop)},
+		}
+	case ContainedBy:
+		filter = map[string]any{
+			startField: map[string]any{"$gte": int64(p.Start)},
+			stopField:  map[string]any{"$lte": int64(p.Stop)},
+		}
+	default:
+		filter = map[string]any{
+			startField: map[string]any{"$lt": int64(p.Stop)},
+			stopField:  map[string]any{"$gt": int64(p.Start)},
+		}
+	}
+
+	switch {
+	case len(p.Bins) == 1:
+		filter[field] = mongoRange(p.Bins[0].First, p.Bins[0].Last)
+	case p.useRanges():
+		or := make([]map[string]any, len(p.Bins))
+		for i, r := range p.Bins {
+			or[i] = map[string]any{field: mongoRange(r.First, r.Last)}
+		}
+		filter["$or"] = or
+	default:
+		bins := make([]int64, 0, p.Count())
+		for _, r := range p.Bins {
+			for bin := r.First; bin <= r.Last; bin++ {
+				bins = append(bins, int64(bin))
+			}
+		}
+		filter[field] = map[string]any{"$in": bins}
+	}
+	return filter
+}
@@ -0,0 +1,53 @@
+package binning
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOverlappingMongoFilter(t *testing.T) {
+	b := StandardBinning()
+	tests := []struct {
+		start, stop int
+		expected    map[string]any
+	}{
+		{10, 20, map[string]any{
+			"bin":   map[string]any{"$in": []int64{0, 1, 9, 73, 585}},
+			"start": map[string]any{"$lt": int64(20)},
+			"stop":  map[string]any{"$gt": int64(10)},
+		}},
+		{0, 1 << 29, map[string]any{
+			"bin":   map[string]any{"$gte": int64(0), "$lte": int64(4680)},
+			"start": map[string]any{"$lt": int64(1 << 29)},
+			"stop":  map[string]any{"$gt": int64(0)},
+		}},
+		{1 << 28, 1 << 29, map[string]any{
+			"$or": []map[string]any{
+				{"bin": map[string]any{"$eq": int64(0)}},
+				{"bin": map[string]any{"$gte": int64(5), "$lte": int64(8)}},
+				{"bin": map[string]any{"$gte": int64(41), "$lte": int64(72)}},
+				{"bin": map[string]any{"$gte": int64(329), "$lte": int64(584)}},
+				{"bin": map[string]any{"$gte": int64(2633), "$lte": int64(4680)}},
+			},
+			"start": map[string]any{"$lt": int64(1 << 29)},
+			"stop":  map[string]any{"$gt": int64(1 << 28)},
+		}},
+	}
+	for _, test := range tests {
+		filter, error := b.OverlappingMongoFilter("bin", test.start, test.stop)
+		if error != nil {
+			t.Errorf("OverlappingMongoFilter(%d, %d) error: %v", test.start, test.stop, error)
+		} else if !reflect.DeepEqual(filter, test.expected) {
+			t.Errorf("OverlappingMongoFilter(%d, %d) = %v, expected %v", test.start, test.stop, filter, test.expected)
+		}
+	}
+
+	filter, _ := b.OverlappingMongoFilterFields("b", "s", "e", 10, 20)
+	if _, ok := filter["e"]; !ok || len(filter) != 3 {
+		t.Errorf("OverlappingMongoFilterFields = %v, expected fields b, s and e", filter)
+	}
+
+	if _, error := b.OverlappingMongoFilter("bin", 0, 1<<30); error == nil {
+		t.Errorf("OverlappingMongoFilter(0, 1<<30) did not return error")
+	}
+}
@@ -0,0 +1,49 @@
+package binning
+
+// An OffsetBinning implements an interval binning scheme on positions that
+// may be negative, such as coordinates relative to a transcription start
+// site. Positions >= MinPosition and <= MinPosition+Scheme.MaxPosition are
+// supported by binning them as positions relative to MinPosition.
+type OffsetBinning struct {
+	Scheme      Binning
+	MinPosition int
+}
+
+// NewOffsetBinning creates a new binning scheme on positions starting at
+// minPosition, using binning scheme b for the positions relative to
+// minPosition.
+func NewOffsetBinning(minPosition int, b Binning) OffsetBinning {
+	return OffsetBinning{Scheme: b, MinPosition: minPosition}
+}
+
+// Assign returns the smallest bin fitting the interval start:stop.
+func (o OffsetBinning) Assign(start, stop int) (int, error) {
+	return o.Scheme.Assign(start-o.MinPosition, stop-o.MinPosition)
+}
+
+// Overlapping returns bins for all intervals overlapping the interval
+// start:stop by at least one position.
+func (o OffsetBinning) Overlapping(start, stop int) ([]int, error) {
+	return o.Scheme.Overlapping(start-o.MinPosition, stop-o.MinPosition)
+}
+
+// Containing returns bins for all intervals completely containing the
+// interval start:stop.
+func (o OffsetBinning) Containing(start, stop int) ([]int, error) {
+	return o.Scheme.Containing(start-o.MinPosition, stop-o.MinPosition)
+}
+
+// Contained returns bins for all intervals completely contained by the
+// interval start:stop.
+func (o OffsetBinning) Contained(start, stop int) ([]int, error) {
+	return o.Scheme.Contained(start-o.MinPosition, stop-o.MinPosition)
+}
+
+// Covered returns the interval covered by bin.
+func (o OffsetBinning) Covered(bin int) (int, int, error) {
+	start, stop, err := o.Scheme.Covered(bin)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start + o.MinPosition, stop + o.MinPosition, nil
+}
@@ -0,0 +1,52 @@
+package binning
+
+import "testing"
+
+func TestOffsetAssign(t *testing.T) {
+	o := NewOffsetBinning(-1<<20, StandardBinning())
+	for _, v := range []struct{ start, stop, bin int }{
+		{-1 << 20, -1<<20 + 1, 585},
+		{-2000, -1000, 592},
+		{-1000, 1000, 9},
+		{0, 1, 593},
+		{1<<29 - 1<<20 - 1, 1<<29 - 1<<20, 4680},
+	} {
+		bin, error := o.Assign(v.start, v.stop)
+		if error != nil {
+			t.Errorf("Assign(%d, %d) returned error: %v", v.start, v.stop, error)
+			continue
+		}
+		if bin != v.bin {
+			t.Errorf("Assign(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+		if start, stop, error := o.Covered(bin); error != nil {
+			t.Errorf("Covered(%d) returned error: %v", bin, error)
+		} else if start > v.start || v.stop > stop {
+			t.Errorf("Covered(%d) = (%d, %d), expected (<=%d, >=%d)", bin, start, stop, v.start, v.stop)
+		}
+	}
+	for _, v := range []struct{ start, stop int }{{-1<<20 - 1, 0}, {0, 1<<29 - 1<<20 + 1}} {
+		if bin, error := o.Assign(v.start, v.stop); error == nil {
+			t.Errorf("Assign(%d, %d) = %d, expected error", v.start, v.stop, bin)
+		}
+	}
+}
+
+func TestOffsetOverlapping(t *testing.T) {
+	o := NewOffsetBinning(-1<<20, StandardBinning())
+	b := StandardBinning()
+	bins, error := o.Overlapping(-1000, 1000)
+	if error != nil {
+		t.Fatalf("Overlapping(%d, %d) returned error: %v", -1000, 1000, error)
+	}
+	expected, _ := b.Overlapping(1<<20-1000, 1<<20+1000)
+	if len(bins) != len(expected) {
+		t.Fatalf("len(Overlapping(%d, %d)) = %v, expected %v", -1000, 1000, len(bins), len(expected))
+	}
+	for i := 0; i < len(bins); i++ {
+		if bins[i] != expected[i] {
+			t.Errorf("Overlapping(%d, %d)[%d] = %v, expected %v", -1000, 1000, i, bins[i], expected[i])
+			break
+		}
+	}
+}
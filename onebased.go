@@ -0,0 +1,50 @@
+package binning
+
+// AssignOneBased returns the smallest bin fitting the one-based inclusive
+// interval start..end, as used in VCF and GFF files.
+func (b Scheme[T]) AssignOneBased(start, end T) (T, error) {
+	start, end, err := FromOneBasedClosed(start, end)
+	if err != nil {
+		return 0, err
+	}
+	return b.Assign(start, end)
+}
+
+// OverlappingOneBased returns bins for all intervals overlapping the
+// one-based inclusive interval start..end by at least one position.
+func (b Scheme[T]) OverlappingOneBased(start, end T) ([]T, error) {
+	start, end, err := FromOneBasedClosed(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return b.Overlapping(start, end)
+}
+
+// ContainingOneBased returns bins for all intervals completely containing
+// the one-based inclusive interval start..end.
+func (b Scheme[T]) ContainingOneBased(start, end T) ([]T, error) {
+	start, end, err := FromOneBasedClosed(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return b.Containing(start, end)
+}
+
+// ContainedOneBased returns bins for all intervals completely contained by
+// the one-based inclusive interval start..end.
+func (b Scheme[T]) ContainedOneBased(start, end T) ([]T, error) {
+	start, end, err := FromOneBasedClosed(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return b.Contained(start, end)
+}
+
+// CoveredOneBased returns the one-based inclusive interval covered by bin.
+func (b Scheme[T]) CoveredOneBased(bin T) (T, T, error) {
+	start, stop, err := b.Covered(bin)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start + 1, stop, nil
+}
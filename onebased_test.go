@@ -0,0 +1,58 @@
+package binning
+
+import "testing"
+
+func TestAssignOneBased(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range intervalBins {
+		if v.stop <= v.start {
+			continue
+		}
+		if bin, error := b.AssignOneBased(v.start+1, v.stop); error != nil {
+			t.Errorf("AssignOneBased(%d, %d) returned error: %v", v.start+1, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("AssignOneBased(%d, %d) = %d, expected %d", v.start+1, v.stop, bin, v.bin)
+		}
+	}
+	for _, v := range []struct{ start, end int }{{0, 1}, {-1, 5}, {10, 9}, {1, 1<<29 + 1}} {
+		if bin, error := b.AssignOneBased(v.start, v.end); error == nil {
+			t.Errorf("AssignOneBased(%d, %d) = %d, expected error", v.start, v.end, bin)
+		}
+	}
+}
+
+func TestOverlappingOneBased(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range intervalOverlappingBins {
+		bins, error := b.OverlappingOneBased(v.start+1, v.stop)
+		if error != nil {
+			t.Errorf("OverlappingOneBased(%d, %d) returned error: %v", v.start+1, v.stop, error)
+			continue
+		}
+		if len(bins) != len(v.bins) {
+			t.Errorf("len(OverlappingOneBased(%d, %d)) = %v, expected %v", v.start+1, v.stop, len(bins), len(v.bins))
+			continue
+		}
+		for i := 0; i < len(bins); i++ {
+			if bins[i] != v.bins[i] {
+				t.Errorf("OverlappingOneBased(%d, %d)[%d] = %v, expected %v", v.start+1, v.stop, i, bins[i], v.bins[i])
+				break
+			}
+		}
+	}
+}
+
+func TestCoveredOneBased(t *testing.T) {
+	b := StandardBinning()
+	for _, v := range []struct{ bin, start, end int }{
+		{585, 1, 1 << 17},
+		{586, 1<<17 + 1, 2 << 17},
+		{0, 1, 1 << 29},
+	} {
+		if start, end, error := b.CoveredOneBased(v.bin); error != nil {
+			t.Errorf("CoveredOneBased(%d) returned error: %v", v.bin, error)
+		} else if start != v.start || end != v.end {
+			t.Errorf("CoveredOneBased(%d) = (%d, %d), expected (%d, %d)", v.bin, start, end, v.start, v.end)
+		}
+	}
+}
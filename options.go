@@ -0,0 +1,33 @@
+package binning
+
+// A QueryOption modifies a query on a BinIndex. Query options are accepted by
+// Overlapping, CountOverlapping, Any, FirstN and At.
+type QueryOption[T any] func(*queryConfig[T])
+
+// queryConfig holds the query options for a query on a BinIndex.
+type queryConfig[T any] struct {
+	keep func(T) bool
+}
+
+// queryOptions returns the configuration after applying options.
+func queryOptions[T any](options []QueryOption[T]) queryConfig[T] {
+	var c queryConfig[T]
+	for _, option := range options {
+		option(&c)
+	}
+	return c
+}
+
+// WithFilter returns a query option restricting the query to entries with a
+// value for which keep returns true. The predicate is applied while scanning
+// bins, before results are collected. Several filters are combined, so an
+// entry must satisfy all of them.
+func WithFilter[T any](keep func(T) bool) QueryOption[T] {
+	return func(c *queryConfig[T]) {
+		if previous := c.keep; previous != nil {
+			c.keep = func(value T) bool { return previous(value) && keep(value) }
+		} else {
+			c.keep = keep
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package binning
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithFilter(t *testing.T) {
+	x := newTestIndex(t)
+	x.Insert(60, 70, "ab")
+	notA := WithFilter(func(name string) bool { return !strings.HasPrefix(name, "a") })
+	short := WithFilter(func(name string) bool { return len(name) == 1 })
+	for _, v := range []struct {
+		options []QueryOption[string]
+		names   []string
+	}{
+		{nil, []string{"a", "b", "ab"}},
+		{[]QueryOption[string]{notA}, []string{"b"}},
+		{[]QueryOption[string]{short}, []string{"a", "b"}},
+		{[]QueryOption[string]{notA, short}, []string{"b"}},
+	} {
+		if names, error := x.Overlapping(0, 100, v.options...); error != nil {
+			t.Errorf("Overlapping(%d, %d) returned error: %v", 0, 100, error)
+		} else if !sameElements(names, v.names) {
+			t.Errorf("Overlapping(%d, %d) = %v, expected %v", 0, 100, names, v.names)
+		}
+		if n, _ := x.CountOverlapping(0, 100, v.options...); n != len(v.names) {
+			t.Errorf("CountOverlapping(%d, %d) = %d, expected %d", 0, 100, n, len(v.names))
+		}
+		if names, _ := x.At(65, v.options...); !sameElements(names, v.names) {
+			t.Errorf("At(%d) = %v, expected %v", 65, names, v.names)
+		}
+		if names, _ := x.FirstN(0, 100, 10, v.options...); !sameElements(names, v.names) {
+			t.Errorf("FirstN(%d, %d, %d) = %v, expected %v", 0, 100, 10, names, v.names)
+		}
+	}
+	none := WithFilter(func(name string) bool { return name == "z" })
+	if any, _ := x.Any(0, 100, none); any {
+		t.Errorf("Any(%d, %d) = %v, expected %v", 0, 100, any, false)
+	}
+}
@@ -0,0 +1,93 @@
+package binning
+
+// A PairBinning implements a binning scheme for pairs of intervals, such as
+// Hi-C contacts or structural variant breakend pairs. The bin for a pair of
+// intervals combines the bins for both intervals in binning schemes X and Y,
+// such that it can be stored in a single column.
+type PairBinning struct {
+	X, Y   Binning
+	MaxBin int
+}
+
+// NewPairBinning creates a new binning scheme for pairs of intervals, using
+// binning scheme x for the first and binning scheme y for the second interval
+// in each pair.
+func NewPairBinning(x, y Binning) PairBinning {
+	return PairBinning{
+		X:      x,
+		Y:      y,
+		MaxBin: x.MaxBin*(y.MaxBin+1) + y.MaxBin,
+	}
+}
+
+// pair returns the bin for the pair of bins binX and binY.
+func (p PairBinning) pair(binX, binY int) int {
+	return binX*(p.Y.MaxBin+1) + binY
+}
+
+// Split returns the bins in binning schemes X and Y for the pair bin.
+func (p PairBinning) Split(bin int) (int, int, error) {
+	minBin := p.pair(p.X.MinBin, p.Y.MinBin)
+	if bin < minBin || bin > p.MaxBin {
+		return 0, 0, &InvalidBinError{Bin: int64(bin), MinBin: int64(minBin), MaxBin: int64(p.MaxBin)}
+	}
+	binX, binY := bin/(p.Y.MaxBin+1), bin%(p.Y.MaxBin+1)
+	if binY < p.Y.MinBin {
+		return 0, 0, &InvalidBinError{Bin: int64(bin), MinBin: int64(minBin), MaxBin: int64(p.MaxBin)}
+	}
+	return binX, binY, nil
+}
+
+// Assign returns the smallest bin fitting the pair of intervals startX:stopX
+// and startY:stopY.
+func (p PairBinning) Assign(startX, stopX, startY, stopY int) (int, error) {
+	binX, err := p.X.Assign(startX, stopX)
+	if err != nil {
+		return 0, err
+	}
+	binY, err := p.Y.Assign(startY, stopY)
+	if err != nil {
+		return 0, err
+	}
+	return p.pair(binX, binY), nil
+}
+
+// Overlapping returns bins for all pairs of intervals where the first
+// interval overlaps startX:stopX and the second interval overlaps
+// startY:stopY by at least one position.
+func (p PairBinning) Overlapping(startX, stopX, startY, stopY int) ([]int, error) {
+	binsX, err := p.X.Overlapping(startX, stopX)
+	if err != nil {
+		return nil, err
+	}
+	binsY, err := p.Y.Overlapping(startY, stopY)
+	if err != nil {
+		return nil, err
+	}
+
+	bins := make([]int, 0, len(binsX)*len(binsY))
+	for _, binX := range binsX {
+		for _, binY := range binsY {
+			bins = append(bins, p.pair(binX, binY))
+		}
+	}
+
+	return bins, nil
+}
+
+// Covered returns the pair of intervals covered by bin.
+func (p PairBinning) Covered(bin int) (int, int, int, int, error) {
+	binX, binY, err := p.Split(bin)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	startX, stopX, err := p.X.Covered(binX)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	startY, stopY, err := p.Y.Covered(binY)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return startX, stopX, startY, stopY, nil
+}
@@ -0,0 +1,61 @@
+package binning
+
+import "testing"
+
+func TestPairAssign(t *testing.T) {
+	p := NewPairBinning(StandardBinning(), StandardBinning())
+	for _, v := range []struct{ startX, stopX, startY, stopY, bin int }{
+		{0, 1, 0, 1, 585*4681 + 585},
+		{0, 1 << 29, 0, 1, 585},
+		{74012, 173034, 423427, 423428, 73*4681 + 588},
+		{0, 1 << 29, 0, 1 << 29, 0},
+	} {
+		bin, error := p.Assign(v.startX, v.stopX, v.startY, v.stopY)
+		if error != nil {
+			t.Errorf("Assign(%d, %d, %d, %d) returned error: %v", v.startX, v.stopX, v.startY, v.stopY, error)
+			continue
+		}
+		if bin != v.bin {
+			t.Errorf("Assign(%d, %d, %d, %d) = %d, expected %d", v.startX, v.stopX, v.startY, v.stopY, bin, v.bin)
+		}
+		if startX, stopX, startY, stopY, error := p.Covered(bin); error != nil {
+			t.Errorf("Covered(%d) returned error: %v", bin, error)
+		} else if startX > v.startX || v.stopX > stopX || startY > v.startY || v.stopY > stopY {
+			t.Errorf("Covered(%d) = (%d, %d, %d, %d), expected (<=%d, >=%d, <=%d, >=%d)",
+				bin, startX, stopX, startY, stopY, v.startX, v.stopX, v.startY, v.stopY)
+		}
+	}
+	if bin, error := p.Assign(0, 1, -1, 1); error == nil {
+		t.Errorf("Assign(%d, %d, %d, %d) = %d, expected error", 0, 1, -1, 1, bin)
+	}
+}
+
+func TestPairOverlapping(t *testing.T) {
+	p := NewPairBinning(StandardBinning(), ExtendedBinning())
+	bins, error := p.Overlapping(0, 1, 0, 1)
+	if error != nil {
+		t.Fatalf("Overlapping(%d, %d, %d, %d) returned error: %v", 0, 1, 0, 1, error)
+	}
+	if len(bins) != 30 {
+		t.Errorf("len(Overlapping(%d, %d, %d, %d)) = %d, expected %d", 0, 1, 0, 1, len(bins), 30)
+	}
+	bin, _ := p.Assign(0, 1, 0, 1)
+	found := false
+	for _, b := range bins {
+		if b == bin {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Overlapping(%d, %d, %d, %d) does not contain %d", 0, 1, 0, 1, bin)
+	}
+}
+
+func TestPairSplitInvalid(t *testing.T) {
+	p := NewPairBinning(StandardBinning(), ExtendedBinning())
+	for _, bin := range []int{-1, 100, p.MaxBin + 1} {
+		if binX, binY, error := p.Split(bin); error == nil {
+			t.Errorf("Split(%d) = (%d, %d), expected error", bin, binX, binY)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// PartitionGenome splits the genome into approximately n regions of
+// approximately equal size, to be used as units of work for parallel
+// processing. Regions do not span chromosomes and their boundaries are
+// aligned to the largest bins that are not larger than the target region
+// size, so each bin of at most that size is in exactly one region. Small
+// chromosomes are not merged, so more than n regions may be returned.
+func (g GenomeBinning) PartitionGenome(n int) ([]Region, error) {
+	if n < 1 {
+		return nil, errors.New(fmt.Sprintf("invalid number of regions: %d", n))
+	}
+	total := int64(0)
+	for _, c := range g.chromosomes {
+		total += int64(c.Length)
+	}
+	target := int(min(total/int64(n), math.MaxInt))
+	var regions []Region
+	for i, c := range g.chromosomes {
+		size := alignedSize(g.schemes[i], target)
+		for start := 0; start < c.Length; start += size {
+			stop := min(start+size, c.Length)
+			// Merge a small remainder into the previous region.
+			if c.Length-stop < size/2 {
+				stop = c.Length
+			}
+			regions = append(regions, Region{Chrom: c.Name, Start: start, Stop: stop})
+			if stop == c.Length {
+				break
+			}
+		}
+	}
+	return regions, nil
+}
+
+// alignedSize returns target rounded down to a multiple of the largest bin
+// size in scheme b that is not larger than target, or the smallest bin size
+// if all bins are larger.
+func alignedSize(b Binning, target int) int {
+	unit := 1 << b.shifts[0]
+	for _, shift := range b.shifts {
+		if shift < strconv.IntSize-1 && 1<<shift <= target {
+			unit = 1 << shift
+		}
+	}
+	return max(unit, target/unit*unit)
+}
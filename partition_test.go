@@ -0,0 +1,59 @@
+package binning
+
+import "testing"
+
+func TestPartitionGenome(t *testing.T) {
+	g := GRCh38()
+	for _, n := range []int{1, 10, 100, 1000} {
+		regions, error := g.PartitionGenome(n)
+		if error != nil {
+			t.Errorf("PartitionGenome(%d) returned error: %v", n, error)
+			continue
+		}
+		if len(regions) < n/2 || len(regions) > 2*n+len(g.Chromosomes()) {
+			t.Errorf("len(PartitionGenome(%d)) = %d, expected about %d", n, len(regions), n)
+		}
+		i := 0
+		for _, c := range g.Chromosomes() {
+			for start := 0; start < c.Length; i++ {
+				if i >= len(regions) {
+					t.Fatalf("PartitionGenome(%d) does not cover %q from %d", n, c.Name, start)
+				}
+				r := regions[i]
+				if r.Chrom != c.Name || r.Start != start || r.Stop <= r.Start {
+					t.Fatalf("PartitionGenome(%d)[%d] = %v, expected region on %q from %d", n, i, r, c.Name, start)
+				}
+				if r.Start%(1<<17) != 0 {
+					t.Errorf("PartitionGenome(%d)[%d] = %v, expected start aligned to %d", n, i, r, 1<<17)
+				}
+				start = r.Stop
+			}
+		}
+		if i != len(regions) {
+			t.Errorf("len(PartitionGenome(%d)) = %d, expected %d", n, len(regions), i)
+		}
+	}
+	if regions, error := g.PartitionGenome(0); error == nil {
+		t.Errorf("PartitionGenome(%d) = %v, expected error", 0, regions)
+	}
+}
+
+func TestPartitionGenomeAligned(t *testing.T) {
+	g, _ := NewGenomeBinning([]Chromosome{{"a", 1 << 26}, {"b", 1<<26 + 1<<23}})
+	regions, error := g.PartitionGenome(4)
+	if error != nil {
+		t.Fatalf("PartitionGenome(%d) returned error: %v", 4, error)
+	}
+	expected := []Region{
+		{"a", 0, 1 << 25}, {"a", 1 << 25, 1 << 26},
+		{"b", 0, 1 << 25}, {"b", 1 << 25, 1<<26 + 1<<23},
+	}
+	if len(regions) != len(expected) {
+		t.Fatalf("PartitionGenome(%d) = %v, expected %v", 4, regions, expected)
+	}
+	for i := range expected {
+		if regions[i] != expected[i] {
+			t.Errorf("PartitionGenome(%d)[%d] = %v, expected %v", 4, i, regions[i], expected[i])
+		}
+	}
+}
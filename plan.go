@@ -0,0 +1,258 @@
+package binning
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A Relation is a relation between stored intervals and a query interval.
+type Relation int
+
+const (
+	// Overlaps selects intervals overlapping the query interval by at least
+	// one position.
+	Overlaps Relation = iota
+
+	// Contains selects intervals completely containing the query interval.
+	Contains
+
+	// ContainedBy selects intervals completely contained by the query
+	// interval.
+	ContainedBy
+)
+
+// binRanges returns the ranges of bins for all intervals with relation r to
+// the interval start:stop, in ascending order with ranges of adjacent bins
+// merged.
+func (b Scheme[T]) binRanges(r Relation, start, stop T) ([]BinRange[T], error) {
+	var assigned T
+	if r != Overlaps {
+		bin, err := b.Assign(start, stop)
+		if err != nil {
+			return nil, err
+		}
+		assigned = bin
+	}
+	nextRange, err := b.ranges(start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []BinRange[T]
+	for {
+		first, last, ok := nextRange()
+		if !ok {
+			break
+		}
+		switch {
+		case r == Contains && first <= assigned:
+			ranges = append(ranges, BinRange[T]{first, min(last, assigned)})
+		case r == ContainedBy && last >= assigned:
+			ranges = append(ranges, BinRange[T]{max(first, assigned), last})
+		case r == Overlaps:
+			ranges = append(ranges, BinRange[T]{first, last})
+		}
+	}
+	slices.SortFunc(ranges, func(a, b BinRange[T]) int { return cmp.Compare(a.First, b.First) })
+
+	var merged []BinRange[T]
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.First <= merged[n-1].Last+1 {
+			merged[n-1].Last = max(merged[n-1].Last, r.Last)
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged, nil
+}
+
+// A QueryPlan holds the bins to read for a query for intervals with a
+// relation to a query interval, shared by the query generators for
+// different backends.
+type QueryPlan[T Integer] struct {
+	Relation    Relation
+	Start, Stop T
+
+	// Bins are the ranges of bins to read, in ascending order with ranges
+	// of adjacent bins merged.
+	Bins []BinRange[T]
+}
+
+// Plan returns the query plan for intervals with relation r to the interval
+// start:stop.
+func (b Scheme[T]) Plan(r Relation, start, stop T) (QueryPlan[T], error) {
+	if r < Overlaps || r > ContainedBy {
+		return QueryPlan[T]{}, errors.New(fmt.Sprintf("unknown relation: %d", r))
+	}
+	ranges, err := b.binRanges(r, start, stop)
+	if err != nil {
+		return QueryPlan[T]{}, err
+	}
+	return QueryPlan[T]{Relation: r, Start: start, Stop: stop, Bins: ranges}, nil
+}
+
+// Count returns the number of bins in the plan.
+func (p QueryPlan[T]) Count() int {
+	count := 0
+	for _, r := range p.Bins {
+		count += int(r.Last-r.First) + 1
+	}
+	return count
+}
+
+// binList returns the bins in the plan in ascending order.
+func (p QueryPlan[T]) binList() []int64 {
+	bins := make([]int64, 0, p.Count())
+	for _, r := range p.Bins {
+		for bin := r.First; bin <= r.Last; bin++ {
+			bins = append(bins, int64(bin))
+		}
+	}
+	return bins
+}
+
+// binCondition returns an SQL condition matching column to the bins in the
+// plan, with each bin written by format. If ranges is true, the condition
+// matches ranges of bins, such as "((bin BETWEEN 0 AND 1) OR (bin = 9))",
+// otherwise it matches a list of bins, such as "bin IN (0,1,9)".
+func (p QueryPlan[T]) binCondition(column string, ranges bool, format func(bin int64) string) string {
+	var s strings.Builder
+	if !ranges {
+		s.WriteString(column + " IN (")
+		for i, bin := range p.binList() {
+			if i > 0 {
+				s.WriteByte(',')
+			}
+			s.WriteString(format(bin))
+		}
+		s.WriteByte(')')
+		return s.String()
+	}
+	s.WriteByte('(')
+	for i, r := range p.Bins {
+		if i > 0 {
+			s.WriteString(" OR ")
+		}
+		if r.First == r.Last {
+			s.WriteString("(" + column + " = " + format(int64(r.First)) + ")")
+		} else {
+			s.WriteString("(" + column + " BETWEEN " + format(int64(r.First)) + " AND " + format(int64(r.Last)) + ")")
+		}
+	}
+	s.WriteByte(')')
+	return s.String()
+}
+
+// useRanges reports whether bins are better matched as ranges than as a list
+// of bins, which is the case if that is shorter.
+func (p QueryPlan[T]) useRanges() bool {
+	return p.Count() > 2*len(p.Bins)
+}
+
+// A QueryTarget is a table of regions with a bin column to generate queries
+// for.
+type QueryTarget interface {
+	// Table returns the name of the table.
+	Table() string
+
+	// Dialect returns the SQL dialect of the database with the table.
+	Dialect() Dialect
+
+	// Columns returns the names of the columns with the chromosome, start
+	// and stop positions, and bin of a region.
+	Columns() (chrom, start, stop, bin string)
+}
+
+// A RegionTable is a QueryTarget with the column names of
+// OverlappingRegionSQLArgs.
+type RegionTable struct {
+	Name       string
+	SQLDialect Dialect
+}
+
+// Table implements the QueryTarget interface.
+func (t RegionTable) Table() string { return t.Name }
+
+// Dialect implements the QueryTarget interface.
+func (t RegionTable) Dialect() Dialect { return t.SQLDialect }
+
+// Columns implements the QueryTarget interface.
+func (t RegionTable) Columns() (chrom, start, stop, bin string) {
+	return "chrom", "start", "stop", "bin"
+}
+
+// BuildQuery returns an SQL query selecting columns from the table of target
+// for regions on chromosome chrom following plan, and the arguments for its
+// placeholders. Bins are matched with a list of bins, or with ranges of bins
+// if that is shorter. The dialect and column names are those of target. Of
+// the options, only WithPrecedingArgs applies. It returns an error
+// for WithDialect and WithRegionColumns options that differ from target, and
+// for WithRangeTypes.
+func BuildQuery[T Integer](target QueryTarget, plan QueryPlan[T], chrom string, columns []string, options ...SQLOption) (string, []any, error) {
+	c := sqlOptions(options)
+	chromColumn, startColumn, stopColumn, binColumn := target.Columns()
+	targetColumns := [4]string{chromColumn, startColumn, stopColumn, binColumn}
+	if c.dialect != DefaultDialect && c.dialect != target.Dialect() {
+		return "", nil, errors.New("dialect option differs from the dialect of the target")
+	}
+	if c.columns != sqlOptions(nil).columns && c.columns != targetColumns {
+		return "", nil, errors.New("region columns option differs from the columns of the target")
+	}
+	if c.ranges {
+		return "", nil, errors.New("range types are not supported for query plans")
+	}
+	c.dialect = target.Dialect()
+	if len(columns) == 0 {
+		return "", nil, errors.New("no columns to select")
+	}
+	selected, err := quoteColumns(c.dialect, columns)
+	if err != nil {
+		return "", nil, err
+	}
+	table, err := c.dialect.Quote(target.Table())
+	if err != nil {
+		return "", nil, err
+	}
+	var quoted [4]string
+	for i, column := range targetColumns {
+		if quoted[i], err = c.dialect.Quote(column); err != nil {
+			return "", nil, err
+		}
+	}
+	condition, args, err := regionCondition(c, quoted, plan, chrom, plan.useRanges())
+	if err != nil {
+		return "", nil, err
+	}
+	return "SELECT " + selected + " FROM " + table + " WHERE " + condition, args, nil
+}
+
+// regionCondition returns an SQL condition selecting regions on chromosome
+// chrom following plan, with columns the quoted chromosome, start, stop and
+// bin columns, and the arguments for its placeholders. Bins are matched with
+// an array argument if c uses arrays, and otherwise with ranges of bins if
+// ranges is true or with a list of bins if not.
+func regionCondition[T Integer](c sqlConfig, columns [4]string, plan QueryPlan[T], chrom string, ranges bool) (string, []any, error) {
+	a := &binArgs{c: c}
+	condition := columns[0] + " = " + a.add(chrom) + " AND "
+	if c.usesArrays() {
+		condition += c.arrayCondition(columns[3], a.add(plan.binList()))
+	} else {
+		condition += plan.binCondition(columns[3], ranges, func(bin int64) string { return a.add(bin) })
+	}
+
+	start, stop := int64(plan.Start), int64(plan.Stop)
+	switch plan.Relation {
+	case Overlaps:
+		condition += " AND " + columns[1] + " < " + a.add(stop) + " AND " + columns[2] + " > " + a.add(start)
+	case Contains:
+		condition += " AND " + columns[1] + " <= " + a.add(start) + " AND " + columns[2] + " >= " + a.add(stop)
+	case ContainedBy:
+		condition += " AND " + columns[1] + " >= " + a.add(start) + " AND " + columns[2] + " <= " + a.add(stop)
+	default:
+		return "", nil, errors.New(fmt.Sprintf("unknown relation: %d", plan.Relation))
+	}
+	return condition, a.args, nil
+}
@@ -0,0 +1,120 @@
+package binning
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	b := StandardBinning()
+	tests := []struct {
+		relation    Relation
+		start, stop int
+		expected    []BinRange[int]
+	}{
+		{Overlaps, 10, 20, []BinRange[int]{{0, 1}, {9, 9}, {73, 73}, {585, 585}}},
+		{Contains, 10, 20, []BinRange[int]{{0, 1}, {9, 9}, {73, 73}, {585, 585}}},
+		{ContainedBy, 10, 20, []BinRange[int]{{585, 585}}},
+		{Contains, 1<<17 - 1, 1<<17 + 1, []BinRange[int]{{0, 1}, {9, 9}, {73, 73}}},
+		{ContainedBy, 1<<17 - 1, 1<<17 + 1, []BinRange[int]{{73, 73}, {585, 586}}},
+		{ContainedBy, 0, 1 << 29, []BinRange[int]{{0, 4680}}},
+	}
+	for _, test := range tests {
+		plan, error := b.Plan(test.relation, test.start, test.stop)
+		if error != nil {
+			t.Errorf("Plan(%d, %d, %d) error: %v", test.relation, test.start, test.stop, error)
+		} else if !slices.Equal(plan.Bins, test.expected) {
+			t.Errorf("Plan(%d, %d, %d) = %v, expected %v", test.relation, test.start, test.stop, plan.Bins, test.expected)
+		}
+	}
+
+	if _, error := b.Plan(Relation(3), 10, 20); error == nil {
+		t.Errorf("Plan with unknown relation did not return error")
+	}
+	if _, error := b.Plan(Contains, 0, 1<<30); error == nil {
+		t.Errorf("Plan(0, 1<<30) did not return error")
+	}
+}
+
+func TestPlanMatchesSchemeQueries(t *testing.T) {
+	b := StandardBinning()
+	queries := map[Relation]func(int, int) ([]int, error){
+		Overlaps:    b.Overlapping,
+		Contains:    b.Containing,
+		ContainedBy: b.Contained,
+	}
+	for relation, query := range queries {
+		for _, v := range [][2]int{{0, 1}, {99, 101}, {1<<17 - 1, 1<<17 + 1}, {5000000, 9000000}} {
+			expected, _ := query(v[0], v[1])
+			plan, _ := b.Plan(relation, v[0], v[1])
+			var bins []int
+			for _, r := range plan.Bins {
+				for bin := r.First; bin <= r.Last; bin++ {
+					bins = append(bins, bin)
+				}
+			}
+			slices.Sort(expected)
+			if !slices.Equal(bins, expected) || plan.Count() != len(expected) {
+				t.Errorf("Plan(%d, %d, %d) has bins %v, expected %v", relation, v[0], v[1], bins, expected)
+			}
+		}
+	}
+}
+
+func TestBuildQuery(t *testing.T) {
+	b := StandardBinning()
+	plan, _ := b.Plan(ContainedBy, 1<<17-1, 1<<17+1)
+	query, args, error := BuildQuery(RegionTable{"genes", PostgreSQL}, plan, "chr1", []string{"name"})
+	if error != nil {
+		t.Fatalf("BuildQuery error: %v", error)
+	}
+	expected := `SELECT "name" FROM "genes" WHERE "chrom" = $1 AND "bin" IN ($2,$3,$4) AND "start" >= $5 AND "stop" <= $6`
+	if query != expected {
+		t.Errorf("BuildQuery = %q, expected %q", query, expected)
+	}
+	if expected := []any{"chr1", int64(73), int64(585), int64(586), int64(1<<17 - 1), int64(1<<17 + 1)}; !slices.Equal(args, expected) {
+		t.Errorf("BuildQuery args = %v, expected %v", args, expected)
+	}
+
+	plan, _ = b.Plan(Overlaps, 0, 1<<29)
+	query, args, _ = BuildQuery(RegionTable{"genes", MySQL}, plan, "chr1", []string{"name"}, WithPrecedingArgs(2))
+	expected = "SELECT `name` FROM `genes` WHERE `chrom` = ? AND ((`bin` BETWEEN ? AND ?)) AND `start` < ? AND `stop` > ?"
+	if query != expected || len(args) != 5 {
+		t.Errorf("BuildQuery = %q with %d args, expected %q with 5 args", query, len(args), expected)
+	}
+
+	plan, _ = b.Plan(Contains, 10, 20)
+	query, args, _ = BuildQuery(RegionTable{"genes", ClickHouse}, plan, "chr1", []string{"name"})
+	expected = "SELECT `name` FROM `genes` WHERE `chrom` = ? AND has(?, `bin`) AND `start` <= ? AND `stop` >= ?"
+	if query != expected || !slices.Equal(args[1].([]int64), []int64{0, 1, 9, 73, 585}) {
+		t.Errorf("BuildQuery = %q with args %v, expected %q", query, args, expected)
+	}
+
+	if _, _, error := BuildQuery(RegionTable{"genes", PostgreSQL}, plan, "chr1", nil); error == nil {
+		t.Errorf("BuildQuery without columns did not return error")
+	}
+	if _, _, error := BuildQuery(RegionTable{"", PostgreSQL}, plan, "chr1", []string{"name"}); error == nil {
+		t.Errorf("BuildQuery without table did not return error")
+	}
+	if _, _, error := BuildQuery(RegionTable{"genes", PostgreSQL}, plan, "chr1", []string{"name"}, WithDialect(PostgreSQL), WithRegionColumns("chrom", "start", "stop", "bin")); error != nil {
+		t.Errorf("BuildQuery with options matching the target returned error: %v", error)
+	}
+	for _, option := range []SQLOption{WithDialect(MySQL), WithRegionColumns("chrom", "chrom_start", "chrom_end", "bin"), WithRangeTypes()} {
+		if _, _, error := BuildQuery(RegionTable{"genes", PostgreSQL}, plan, "chr1", []string{"name"}, option); error == nil {
+			t.Errorf("BuildQuery with option not applied to the target did not return error")
+		}
+	}
+}
+
+func TestQueryPlanMongoFilter(t *testing.T) {
+	plan, _ := StandardBinning().Plan(ContainedBy, 10, 20)
+	expected := map[string]any{
+		"bin":   map[string]any{"$eq": int64(585)},
+		"start": map[string]any{"$gte": int64(10)},
+		"stop":  map[string]any{"$lte": int64(20)},
+	}
+	if filter := plan.MongoFilter("bin", "start", "stop"); !reflect.DeepEqual(filter, expected) {
+		t.Errorf("MongoFilter = %v, expected %v", filter, expected)
+	}
+}
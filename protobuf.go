@@ -0,0 +1,226 @@
+package binning
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Protocol buffer wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func appendVarintField(data []byte, field int, v uint64) []byte {
+	data = binary.AppendUvarint(data, uint64(field)<<3|wireVarint)
+	return binary.AppendUvarint(data, v)
+}
+
+func appendBytesField(data []byte, field int, v []byte) []byte {
+	data = binary.AppendUvarint(data, uint64(field)<<3|wireBytes)
+	data = binary.AppendUvarint(data, uint64(len(v)))
+	return append(data, v...)
+}
+
+// protoField is a decoded protocol buffer field. For the varint wire type,
+// value holds the value, for the bytes wire type, bytes holds the value.
+type protoField struct {
+	number   int
+	wireType int
+	value    uint64
+	bytes    []byte
+}
+
+// parseProto calls fn for each field in the protocol buffer message in data.
+func parseProto(data []byte, fn func(f protoField) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 || key>>3 == 0 {
+			return errors.New("invalid protocol buffer field")
+		}
+		data = data[n:]
+		f := protoField{number: int(key >> 3), wireType: int(key & 7)}
+		switch f.wireType {
+		case wireVarint:
+			if f.value, n = binary.Uvarint(data); n <= 0 {
+				return errors.New("invalid protocol buffer varint")
+			}
+			data = data[n:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if f.wireType == wireFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return errors.New("truncated protocol buffer field")
+			}
+			data = data[size:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return errors.New("truncated protocol buffer field")
+			}
+			f.bytes, data = data[n:n+int(length)], data[n+int(length):]
+		default:
+			return errors.New(fmt.Sprintf("unsupported protocol buffer wire type: %d", f.wireType))
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// varints returns the values of a repeated varint field, which may be
+// packed (bytes wire type) or not (varint wire type).
+func (f protoField) varints() ([]uint64, error) {
+	if f.wireType == wireVarint {
+		return []uint64{f.value}, nil
+	}
+	if f.wireType != wireBytes {
+		return nil, errors.New(fmt.Sprintf("invalid wire type for field %d: %d", f.number, f.wireType))
+	}
+	var values []uint64
+	for data := f.bytes; len(data) > 0; {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.New("invalid protocol buffer varint")
+		}
+		values, data = append(values, v), data[n:]
+	}
+	return values, nil
+}
+
+// MarshalProto returns the region encoded as an Interval protocol buffer
+// message as defined in proto/binning.proto.
+func (r Region) MarshalProto() []byte {
+	var data []byte
+	if r.Chrom != "" {
+		data = appendBytesField(data, 1, []byte(r.Chrom))
+	}
+	if r.Start != 0 {
+		data = appendVarintField(data, 2, uint64(int64(r.Start)))
+	}
+	if r.Stop != 0 {
+		data = appendVarintField(data, 3, uint64(int64(r.Stop)))
+	}
+	return data
+}
+
+// UnmarshalProto sets the region from an Interval protocol buffer message.
+// Unknown fields are ignored.
+func (r *Region) UnmarshalProto(data []byte) error {
+	var region Region
+	err := parseProto(data, func(f protoField) error {
+		switch {
+		case f.number == 1 && f.wireType == wireBytes:
+			region.Chrom = string(f.bytes)
+		case f.number == 2 && f.wireType == wireVarint:
+			region.Start = int(int64(f.value))
+		case f.number == 3 && f.wireType == wireVarint:
+			region.Stop = int(int64(f.value))
+		case f.number <= 3:
+			return errors.New(fmt.Sprintf("invalid wire type for field %d: %d", f.number, f.wireType))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*r = region
+	return nil
+}
+
+// A BinnedRegion is a region with the bin assigned to it.
+type BinnedRegion struct {
+	Region
+	Bin int
+}
+
+// MarshalProto returns the binned region encoded as a BinnedInterval
+// protocol buffer message as defined in proto/binning.proto.
+func (r BinnedRegion) MarshalProto() []byte {
+	data := appendBytesField(nil, 1, r.Region.MarshalProto())
+	if r.Bin != 0 {
+		data = appendVarintField(data, 2, uint64(int64(r.Bin)))
+	}
+	return data
+}
+
+// UnmarshalProto sets the binned region from a BinnedInterval protocol
+// buffer message. Unknown fields are ignored.
+func (r *BinnedRegion) UnmarshalProto(data []byte) error {
+	var region BinnedRegion
+	err := parseProto(data, func(f protoField) error {
+		switch {
+		case f.number == 1 && f.wireType == wireBytes:
+			return region.Region.UnmarshalProto(f.bytes)
+		case f.number == 2 && f.wireType == wireVarint:
+			region.Bin = int(int64(f.value))
+		case f.number <= 2:
+			return errors.New(fmt.Sprintf("invalid wire type for field %d: %d", f.number, f.wireType))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	*r = region
+	return nil
+}
+
+// MarshalProto returns the binning scheme encoded as a SchemeDescriptor
+// protocol buffer message as defined in proto/binning.proto.
+func (b Scheme[T]) MarshalProto() []byte {
+	data := appendVarintField(nil, 1, uint64(int64(b.MaxPosition)))
+	var offsets, shifts []byte
+	for level := range b.shifts {
+		offsets = binary.AppendUvarint(offsets, uint64(int64(b.binOffsets[level])))
+		shifts = binary.AppendUvarint(shifts, uint64(b.shifts[level]))
+	}
+	data = appendBytesField(data, 2, offsets)
+	return appendBytesField(data, 3, shifts)
+}
+
+// UnmarshalProto sets the binning scheme from a SchemeDescriptor protocol
+// buffer message. The parameters are validated as with NewSchemeFromLevels.
+// Unknown fields are ignored.
+func (b *Scheme[T]) UnmarshalProto(data []byte) error {
+	var maxPosition T
+	var offsets []T
+	var shifts []uint
+	err := parseProto(data, func(f protoField) error {
+		switch f.number {
+		case 1:
+			if f.wireType != wireVarint {
+				return errors.New(fmt.Sprintf("invalid wire type for field 1: %d", f.wireType))
+			}
+			maxPosition = T(int64(f.value))
+		case 2:
+			values, err := f.varints()
+			for _, v := range values {
+				offsets = append(offsets, T(int64(v)))
+			}
+			return err
+		case 3:
+			values, err := f.varints()
+			for _, v := range values {
+				shifts = append(shifts, uint(v))
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	scheme, err := NewSchemeFromLevels(maxPosition, offsets, shifts)
+	if err != nil {
+		return err
+	}
+	*b = scheme
+	return nil
+}
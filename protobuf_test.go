@@ -0,0 +1,86 @@
+package binning
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRegionProto(t *testing.T) {
+	r := Region{Chrom: "chr1", Start: 100, Stop: 200}
+	data := r.MarshalProto()
+	expected := []byte{0x0a, 4, 'c', 'h', 'r', '1', 0x10, 100, 0x18, 0xc8, 0x01}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("MarshalProto() = %x, expected %x", data, expected)
+	}
+
+	var s Region
+	if error := s.UnmarshalProto(data); error != nil || s != r {
+		t.Errorf("UnmarshalProto(%x) = %v, %v, expected %v", data, s, error, r)
+	}
+
+	// Negative positions, unknown fields of all wire types and an empty
+	// message.
+	for _, r := range []Region{{Chrom: "X", Start: -5, Stop: -1}, {}} {
+		data := append(r.MarshalProto(), 0x20, 1, 0x29, 0, 0, 0, 0, 0, 0, 0, 0, 0x32, 1, 'x', 0x3d, 0, 0, 0, 0)
+		var s Region
+		if error := s.UnmarshalProto(data); error != nil || s != r {
+			t.Errorf("UnmarshalProto(%x) = %v, %v, expected %v", data, s, error, r)
+		}
+	}
+}
+
+func TestRegionProtoInvalid(t *testing.T) {
+	tests := [][]byte{
+		{0x0a, 5, 'c'},
+		{0x10},
+		{0x08, 1},
+		{0x12, 0},
+		{0x00, 1},
+		{0x0b},
+	}
+	for _, data := range tests {
+		r := Region{Chrom: "unchanged"}
+		if error := r.UnmarshalProto(data); error == nil || r.Chrom != "unchanged" {
+			t.Errorf("UnmarshalProto(%x) = %v, %v, expected error", data, r, error)
+		}
+	}
+}
+
+func TestBinnedRegionProto(t *testing.T) {
+	r := BinnedRegion{Region: Region{Chrom: "chr1", Start: 74012, Stop: 173034}, Bin: 73}
+	var s BinnedRegion
+	if error := s.UnmarshalProto(r.MarshalProto()); error != nil || s != r {
+		t.Errorf("UnmarshalProto(MarshalProto(%v)) = %v, %v", r, s, error)
+	}
+	if error := s.UnmarshalProto([]byte{0x0a, 2, 0x10, 0x80}); error == nil {
+		t.Errorf("UnmarshalProto did not return error for invalid nested interval")
+	}
+}
+
+func TestSchemeProto(t *testing.T) {
+	for _, b := range []Binning{StandardBinning(), TabixBinning(), FineBinning()} {
+		var c Binning
+		if error := c.UnmarshalProto(b.MarshalProto()); error != nil || !equalSchemes(b, c) {
+			t.Errorf("UnmarshalProto(MarshalProto(%v)) = %v, %v", b, c, error)
+		}
+	}
+
+	b := ExtendedScheme[int64]()
+	var c Binning64
+	if error := c.UnmarshalProto(b.MarshalProto()); error != nil || !equalSchemes(b, c) {
+		t.Errorf("UnmarshalProto(MarshalProto(%v)) = %v, %v", b, c, error)
+	}
+
+	// Unpacked repeated fields: max position 15, offsets 1 and 0, shifts 2
+	// and 4.
+	unpacked := []byte{0x08, 15, 0x10, 1, 0x10, 0, 0x18, 2, 0x18, 4}
+	if error := c.UnmarshalProto(unpacked); error != nil || c.MaxPosition != 15 || c.MaxBin != 4 {
+		t.Errorf("UnmarshalProto(%x) = %v, %v", unpacked, c, error)
+	}
+
+	for _, data := range [][]byte{{}, {0x08, 15, 0x10, 1, 0x18, 2}, {0x09, 0, 0, 0, 0, 0, 0, 0, 0}, {0x15, 0, 0, 0, 0}} {
+		if error := c.UnmarshalProto(data); error == nil {
+			t.Errorf("UnmarshalProto(%x) did not return error", data)
+		}
+	}
+}
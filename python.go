@@ -0,0 +1,53 @@
+package binning
+
+// This file provides functions named after those in the Python implementation
+// of the interval binning scheme (the interval-binning package), for use in
+// pipelines mixing both implementations.
+//
+// The Python implementation defines its maximum position as 2^29, the first
+// position that cannot be binned, whereas MaxPosition in this package is the
+// last position that can be binned (2^29-1). Both accept exactly the same
+// intervals and assign them the same bin numbers.
+
+// PythonMaxPosition is the maximum position as defined by the Python
+// implementation. Intervals may not extend beyond this position.
+const PythonMaxPosition = 1 << 29
+
+// PythonBinning returns the binning scheme used by the Python implementation,
+// which is the standard binning scheme used by the UCSC Genome Browser.
+func PythonBinning() Binning {
+	return StandardBinning()
+}
+
+// AssignBin returns the smallest bin fitting the interval start:stop in the
+// scheme used by the Python implementation. See Binning.Assign.
+func AssignBin(start, stop int) (int, error) {
+	return PythonBinning().Assign(start, stop)
+}
+
+// OverlappingBins returns bins for all intervals overlapping the interval
+// start:stop in the scheme used by the Python implementation. See
+// Binning.Overlapping.
+func OverlappingBins(start, stop int) ([]int, error) {
+	return PythonBinning().Overlapping(start, stop)
+}
+
+// ContainingBins returns bins for all intervals completely containing the
+// interval start:stop in the scheme used by the Python implementation. See
+// Binning.Containing.
+func ContainingBins(start, stop int) ([]int, error) {
+	return PythonBinning().Containing(start, stop)
+}
+
+// ContainedBins returns bins for all intervals completely contained by the
+// interval start:stop in the scheme used by the Python implementation. See
+// Binning.Contained.
+func ContainedBins(start, stop int) ([]int, error) {
+	return PythonBinning().Contained(start, stop)
+}
+
+// CoveredInterval returns the interval covered by bin in the scheme used by
+// the Python implementation. See Binning.Covered.
+func CoveredInterval(bin int) (int, int, error) {
+	return PythonBinning().Covered(bin)
+}
@@ -0,0 +1,31 @@
+package binning
+
+import "testing"
+
+func TestAssignBin(t *testing.T) {
+	for _, v := range intervalBins {
+		if bin, error := AssignBin(v.start, v.stop); error != nil {
+			t.Errorf("AssignBin(%d, %d) returned error: %v", v.start, v.stop, error)
+		} else if bin != v.bin {
+			t.Errorf("AssignBin(%d, %d) = %d, expected %d", v.start, v.stop, bin, v.bin)
+		}
+	}
+	if bin, error := AssignBin(PythonMaxPosition-1, PythonMaxPosition); error != nil {
+		t.Errorf("AssignBin(%d, %d) returned error: %v", PythonMaxPosition-1, PythonMaxPosition, error)
+	} else if bin != 4680 {
+		t.Errorf("AssignBin(%d, %d) = %d, expected %d", PythonMaxPosition-1, PythonMaxPosition, bin, 4680)
+	}
+	if bin, error := AssignBin(PythonMaxPosition, PythonMaxPosition+1); error == nil {
+		t.Errorf("AssignBin(%d, %d) = %d, expected error", PythonMaxPosition, PythonMaxPosition+1, bin)
+	}
+}
+
+func TestCoveredInterval(t *testing.T) {
+	for _, v := range intervalBins {
+		if start, stop, error := CoveredInterval(v.bin); error != nil {
+			t.Errorf("CoveredInterval(%d) returned error: %v", v.bin, error)
+		} else if start > v.start || v.stop > stop {
+			t.Errorf("CoveredInterval(%d) = (%d, %d), expected (<=%d, >=%d)", v.bin, start, stop, v.start, v.stop)
+		}
+	}
+}
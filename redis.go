@@ -0,0 +1,84 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RedisKey returns the Redis key for the intervals in bin on chromosome
+// chrom, of the form prefix:chrom:bin. The bin is last, so chromosome names
+// containing colons give unique keys.
+func RedisKey[T Integer](prefix, chrom string, bin T) string {
+	return prefix + ":" + chrom + ":" + strconv.FormatInt(int64(bin), 10)
+}
+
+// RedisMember returns the member of a Redis sorted set for the interval
+// start:stop with identifier id, of the form start:stop:id. The score of the
+// member should be start, as used by OverlappingRedisScans.
+func RedisMember[T Integer](start, stop T, id string) string {
+	return strconv.FormatInt(int64(start), 10) + ":" + strconv.FormatInt(int64(stop), 10) + ":" + id
+}
+
+// ParseRedisMember returns the interval start:stop and identifier of a member
+// created by RedisMember.
+func ParseRedisMember(member string) (start, stop int64, id string, err error) {
+	parts := strings.SplitN(member, ":", 3)
+	if len(parts) != 3 {
+		return 0, 0, "", errors.New(fmt.Sprintf("invalid member: %q", member))
+	}
+	start, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, "", errors.New(fmt.Sprintf("invalid start in member: %q", member))
+	}
+	stop, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, "", errors.New(fmt.Sprintf("invalid stop in member: %q", member))
+	}
+	return start, stop, parts[2], nil
+}
+
+// OverlappingRedisKeys returns the Redis keys for all bins with intervals
+// overlapping the interval start:stop on chromosome chrom, for example to
+// fetch them with MGET.
+func (b Scheme[T]) OverlappingRedisKeys(prefix, chrom string, start, stop T) ([]string, error) {
+	bins, err := b.Overlapping(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(bins))
+	for i, bin := range bins {
+		keys[i] = RedisKey(prefix, chrom, bin)
+	}
+	return keys, nil
+}
+
+// A RedisScan is a range of scores from Min up to and including Max in a
+// Redis sorted set with key Key, as queried with ZRANGEBYSCORE.
+type RedisScan struct {
+	Key      string
+	Min, Max int64
+}
+
+// OverlappingRedisScans returns the ranges to scan in Redis sorted sets with
+// members created by RedisMember for intervals overlapping the interval
+// start:stop on chromosome chrom. Scans are limited to members starting in
+// the bin and before stop. Members still need to be checked for ending
+// after start.
+func (b Scheme[T]) OverlappingRedisScans(prefix, chrom string, start, stop T) ([]RedisScan, error) {
+	bins, err := b.Overlapping(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	last := int64(max(stop-1, start))
+	scans := make([]RedisScan, len(bins))
+	for i, bin := range bins {
+		first, _, err := b.Covered(bin)
+		if err != nil {
+			return nil, err
+		}
+		scans[i] = RedisScan{RedisKey(prefix, chrom, bin), int64(first), last}
+	}
+	return scans, nil
+}
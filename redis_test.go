@@ -0,0 +1,61 @@
+package binning
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRedisKey(t *testing.T) {
+	if key := RedisKey("genes", "HLA-A*01:01", 585); key != "genes:HLA-A*01:01:585" {
+		t.Errorf("RedisKey = %q, expected %q", key, "genes:HLA-A*01:01:585")
+	}
+}
+
+func TestRedisMember(t *testing.T) {
+	member := RedisMember(10, 20, "gene:1")
+	if member != "10:20:gene:1" {
+		t.Errorf("RedisMember = %q, expected %q", member, "10:20:gene:1")
+	}
+	start, stop, id, error := ParseRedisMember(member)
+	if error != nil || start != 10 || stop != 20 || id != "gene:1" {
+		t.Errorf("ParseRedisMember(%q) = %d, %d, %q, %v", member, start, stop, id, error)
+	}
+
+	for _, member := range []string{"", "10:20", "a:20:x", "10:b:x"} {
+		if _, _, _, error := ParseRedisMember(member); error == nil {
+			t.Errorf("ParseRedisMember(%q) did not return error", member)
+		}
+	}
+}
+
+func TestOverlappingRedisKeys(t *testing.T) {
+	keys, error := StandardBinning().OverlappingRedisKeys("genes", "chr1", 10, 20)
+	if error != nil {
+		t.Fatalf("OverlappingRedisKeys error: %v", error)
+	}
+	expected := []string{"genes:chr1:585", "genes:chr1:73", "genes:chr1:9", "genes:chr1:1", "genes:chr1:0"}
+	if !slices.Equal(keys, expected) {
+		t.Errorf("OverlappingRedisKeys = %v, expected %v", keys, expected)
+	}
+
+	if _, error := StandardBinning().OverlappingRedisKeys("genes", "chr1", 0, 1<<30); error == nil {
+		t.Errorf("OverlappingRedisKeys(0, 1<<30) did not return error")
+	}
+}
+
+func TestOverlappingRedisScans(t *testing.T) {
+	scans, error := StandardBinning().OverlappingRedisScans("genes", "chr1", 1<<17+10, 1<<17+20)
+	if error != nil {
+		t.Fatalf("OverlappingRedisScans error: %v", error)
+	}
+	expected := []RedisScan{
+		{"genes:chr1:586", 1 << 17, 1<<17 + 19},
+		{"genes:chr1:73", 0, 1<<17 + 19},
+		{"genes:chr1:9", 0, 1<<17 + 19},
+		{"genes:chr1:1", 0, 1<<17 + 19},
+		{"genes:chr1:0", 0, 1<<17 + 19},
+	}
+	if !slices.Equal(scans, expected) {
+		t.Errorf("OverlappingRedisScans = %v, expected %v", scans, expected)
+	}
+}
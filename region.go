@@ -0,0 +1,63 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseRegion parses a region in samtools/UCSC-style syntax (chr1,
+// chr1:5000, chr1:5000- or chr1:1,000,000-2,000,000) with one-based closed
+// positions and returns the chromosome and the zero-based open-ended
+// interval. If the region has no end position, the returned stop is -1. If it
+// has no start position, the returned start is 0.
+func ParseRegion(region string) (chrom string, start, stop int, err error) {
+	i := strings.LastIndex(region, ":")
+	if i < 0 {
+		if region == "" {
+			return "", 0, 0, errors.New("invalid region: empty chromosome")
+		}
+		return region, 0, -1, nil
+	}
+	chrom = region[:i]
+	if chrom == "" {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid region %q: empty chromosome", region))
+	}
+	positions := strings.ReplaceAll(region[i+1:], ",", "")
+	first, last, ranged := strings.Cut(positions, "-")
+	begin, err := strconv.Atoi(first)
+	if err != nil || begin < 1 {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid region %q: invalid start %q", region, first))
+	}
+	if !ranged || last == "" {
+		return chrom, begin - 1, -1, nil
+	}
+	end, err := strconv.Atoi(last)
+	if err != nil || end < begin {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid region %q: invalid end %q", region, last))
+	}
+	return chrom, begin - 1, end, nil
+}
+
+// ParseRegion parses a region like the ParseRegion function and checks it
+// against the genome. Chromosome aliases are resolved and a missing end
+// position is taken to be the end of the chromosome.
+func (g GenomeBinning) ParseRegion(region string) (Region, error) {
+	chrom, start, stop, err := ParseRegion(region)
+	if err != nil {
+		return Region{}, err
+	}
+	i, err := g.lookup(chrom)
+	if err != nil {
+		return Region{}, err
+	}
+	c := g.chromosomes[i]
+	if stop < 0 {
+		stop = c.Length
+	}
+	if _, err := g.checkRange(c.Name, start, stop); err != nil {
+		return Region{}, err
+	}
+	return Region{Chrom: c.Name, Start: start, Stop: stop}, nil
+}
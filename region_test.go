@@ -0,0 +1,53 @@
+package binning
+
+import "testing"
+
+func TestParseRegion(t *testing.T) {
+	for _, v := range []struct {
+		region      string
+		chrom       string
+		start, stop int
+	}{
+		{"chr1", "chr1", 0, -1},
+		{"chr1:5000", "chr1", 4999, -1},
+		{"chr1:5000-", "chr1", 4999, -1},
+		{"chr1:1,000,000-2,000,000", "chr1", 999999, 2000000},
+		{"chr1:1-1", "chr1", 0, 1},
+		{"HLA-A*01:01:1-10", "HLA-A*01:01", 0, 10},
+	} {
+		if chrom, start, stop, error := ParseRegion(v.region); error != nil {
+			t.Errorf("ParseRegion(%q) returned error: %v", v.region, error)
+		} else if chrom != v.chrom || start != v.start || stop != v.stop {
+			t.Errorf("ParseRegion(%q) = (%q, %d, %d), expected (%q, %d, %d)", v.region, chrom, start, stop, v.chrom, v.start, v.stop)
+		}
+	}
+	for _, region := range []string{"", ":1-10", "chr1:", "chr1:0-10", "chr1:10-9", "chr1:a-10", "chr1:1-b", "chr1:-10"} {
+		if chrom, start, stop, error := ParseRegion(region); error == nil {
+			t.Errorf("ParseRegion(%q) = (%q, %d, %d), expected error", region, chrom, start, stop)
+		}
+	}
+}
+
+func TestGenomeParseRegion(t *testing.T) {
+	g, _ := NewGenomeBinning(testChromosomes)
+	g, _ = g.WithAliases(Aliases{"MT": "chrM"})
+	for _, v := range []struct {
+		region string
+		r      Region
+	}{
+		{"chr21", Region{"chr21", 0, 46709983}},
+		{"MT:16000-", Region{"chrM", 15999, 16569}},
+		{"chr1:1,000-2,000", Region{"chr1", 999, 2000}},
+	} {
+		if r, error := g.ParseRegion(v.region); error != nil {
+			t.Errorf("ParseRegion(%q) returned error: %v", v.region, error)
+		} else if r != v.r {
+			t.Errorf("ParseRegion(%q) = %v, expected %v", v.region, r, v.r)
+		}
+	}
+	for _, region := range []string{"chr2:1-10", "chrM:16000-16570", "chrM:16570", "chr1:0-10"} {
+		if r, error := g.ParseRegion(region); error == nil {
+			t.Errorf("ParseRegion(%q) = %v, expected error", region, r)
+		}
+	}
+}
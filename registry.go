@@ -0,0 +1,59 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Binning{
+		"ucsc-standard": StandardBinning(),
+		"ucsc-extended": ExtendedBinning(),
+		"tabix":         TabixBinning(),
+		"fine":          FineBinning(),
+		"coarse":        CoarseBinning(),
+	}
+)
+
+// Register makes a binning scheme available by the provided name. It returns
+// an error if a scheme is already registered by that name.
+func Register(name string, scheme Binning) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[name]; ok {
+		return errors.New(fmt.Sprintf("binning scheme already registered: %q", name))
+	}
+	registry[name] = scheme
+	return nil
+}
+
+// Lookup returns the binning scheme registered by the provided name. The
+// built-in schemes are registered as "ucsc-standard", "ucsc-extended",
+// "tabix", "fine" and "coarse".
+func Lookup(name string) (Binning, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	scheme, ok := registry[name]
+	if !ok {
+		return Binning{}, errors.New(fmt.Sprintf("unknown binning scheme: %q", name))
+	}
+	return scheme, nil
+}
+
+// Names returns the names of all registered binning schemes in sorted order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,41 @@
+package binning
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	for _, v := range []struct {
+		name   string
+		scheme Binning
+	}{
+		{"ucsc-standard", StandardBinning()},
+		{"ucsc-extended", ExtendedBinning()},
+		{"tabix", TabixBinning()},
+	} {
+		if scheme, error := Lookup(v.name); error != nil {
+			t.Errorf("Lookup(%q) returned error: %v", v.name, error)
+		} else if scheme.MaxPosition != v.scheme.MaxPosition || scheme.MinBin != v.scheme.MinBin || scheme.MaxBin != v.scheme.MaxBin {
+			t.Errorf("Lookup(%q) = %v, expected %v", v.name, scheme, v.scheme)
+		}
+	}
+	if scheme, error := Lookup("unknown"); error == nil {
+		t.Errorf("Lookup(%q) = %v, expected error", "unknown", scheme)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	scheme := NewCSIBinning(16, 4)
+	if error := Register("test-register", scheme); error != nil {
+		t.Fatalf("Register(%q) returned error: %v", "test-register", error)
+	}
+	if error := Register("test-register", scheme); error == nil {
+		t.Errorf("Register(%q) returned no error on second call, expected error", "test-register")
+	}
+	if found, error := Lookup("test-register"); error != nil {
+		t.Errorf("Lookup(%q) returned error: %v", "test-register", error)
+	} else if found.MaxBin != scheme.MaxBin {
+		t.Errorf("Lookup(%q).MaxBin = %d, expected %d", "test-register", found.MaxBin, scheme.MaxBin)
+	}
+	if error := Register("tabix", scheme); error == nil {
+		t.Errorf("Register(%q) returned no error, expected error", "tabix")
+	}
+}
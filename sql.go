@@ -0,0 +1,334 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Dialect is an SQL dialect used for generating SQL. Dialects differ in
+// how identifiers are quoted and in the placeholders for query arguments.
+type Dialect int
+
+const (
+	// DefaultDialect leaves identifiers as they are and uses ? placeholders.
+	DefaultDialect Dialect = iota
+
+	// MySQL quotes identifiers with backticks and uses ? placeholders.
+	MySQL
+
+	// PostgreSQL quotes identifiers with double quotes and uses numbered
+	// placeholders $1, $2, and so on.
+	PostgreSQL
+
+	// SQLite quotes identifiers with double quotes and uses ? placeholders.
+	SQLite
+
+	// BigQuery quotes identifiers with backticks and uses ? placeholders.
+	// Lists of bins are passed as a single array argument with UNNEST.
+	BigQuery
+
+	// ClickHouse quotes identifiers with backticks and uses ? placeholders.
+	// Lists of bins are passed as a single array argument with has.
+	ClickHouse
+
+	// DuckDB quotes identifiers with double quotes and uses ? placeholders.
+	DuckDB
+)
+
+// Quote returns name as an identifier in the dialect. Qualified names such
+// as "genes.bin" are quoted per part.
+func (d Dialect) Quote(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("empty identifier")
+	}
+	var quote string
+	var escape *strings.Replacer
+	switch d {
+	case DefaultDialect:
+		return name, nil
+	case MySQL:
+		quote, escape = "`", strings.NewReplacer("`", "``")
+	case PostgreSQL, SQLite, DuckDB:
+		quote, escape = `"`, strings.NewReplacer(`"`, `""`)
+	case BigQuery, ClickHouse:
+		quote, escape = "`", strings.NewReplacer(`\`, `\\`, "`", "\\`")
+	default:
+		return "", errors.New(fmt.Sprintf("unknown SQL dialect: %d", d))
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "" {
+			return "", errors.New(fmt.Sprintf("invalid identifier: %q", name))
+		}
+		parts[i] = quote + escape.Replace(part) + quote
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// Placeholder returns the placeholder for the query argument with 1-based
+// position n in the dialect.
+func (d Dialect) Placeholder(n int) string {
+	if d == PostgreSQL {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// An SQLOption modifies the SQL generated by OverlappingSQL,
+// OverlappingRangesSQL and their variants with arguments.
+type SQLOption func(*sqlConfig)
+
+// sqlConfig holds the options for generating SQL.
+type sqlConfig struct {
+	dialect Dialect
+	args    int
+	columns [4]string
+	ranges  bool
+}
+
+// usesArrays reports whether the configuration passes bins as a single array
+// argument.
+func (c sqlConfig) usesArrays() bool {
+	return c.dialect == BigQuery || c.dialect == ClickHouse
+}
+
+// arrayCondition returns the condition matching column to the bins in an
+// array argument with placeholder. It is only valid if the configuration
+// uses arrays.
+func (c sqlConfig) arrayCondition(column, placeholder string) string {
+	if c.dialect == BigQuery {
+		return column + " IN UNNEST(" + placeholder + ")"
+	}
+	return "has(" + placeholder + ", " + column + ")"
+}
+
+// binArgs collects the arguments of a generated fragment.
+type binArgs struct {
+	c    sqlConfig
+	args []any
+}
+
+// add adds value as an argument and returns its placeholder.
+func (a *binArgs) add(value any) string {
+	a.args = append(a.args, value)
+	return a.c.placeholder(len(a.args))
+}
+
+// formatBin returns bin as an SQL literal.
+func formatBin(bin int64) string {
+	return strconv.FormatInt(bin, 10)
+}
+
+// placeholder returns the placeholder for the nth argument of a generated
+// fragment.
+func (c sqlConfig) placeholder(n int) string {
+	return c.dialect.Placeholder(c.args + n)
+}
+
+// sqlOptions returns the configuration after applying options.
+func sqlOptions(options []SQLOption) sqlConfig {
+	c := sqlConfig{columns: [4]string{"chrom", "start", "stop", "bin"}}
+	for _, option := range options {
+		option(&c)
+	}
+	return c
+}
+
+// WithDialect returns an SQL option generating SQL for dialect d.
+func WithDialect(d Dialect) SQLOption {
+	return func(c *sqlConfig) {
+		c.dialect = d
+	}
+}
+
+// WithPrecedingArgs returns an SQL option for fragments embedded in a query
+// with n arguments before them, so numbered placeholders start at n+1.
+func WithPrecedingArgs(n int) SQLOption {
+	return func(c *sqlConfig) {
+		c.args = n
+	}
+}
+
+// WithRegionColumns returns an SQL option setting the names of the columns
+// with the chromosome, start and stop positions, and bin of a region used by
+// OverlappingRegionSQLArgs. The default names are chrom, start, stop and bin.
+func WithRegionColumns(chrom, start, stop, bin string) SQLOption {
+	return func(c *sqlConfig) {
+		c.columns = [4]string{chrom, start, stop, bin}
+	}
+}
+
+// WithRangeTypes returns an SQL option for the PostgreSQL dialect using range
+// types instead of the bin column. OverlappingRegionSQLArgs then selects
+// overlapping regions with the && operator on int8range values of the start
+// and stop columns, and CreateRegionTableSQL adds a GiST index on the
+// chromosome and range, so both approaches can be compared on one table.
+func WithRangeTypes() SQLOption {
+	return func(c *sqlConfig) {
+		c.ranges = true
+	}
+}
+
+// OverlappingSQL returns an SQL condition selecting rows with a bin in column
+// for all intervals overlapping the interval start:stop, such as
+// "bin IN (0,1,9,73,585)". Rows in these bins still need to be checked for
+// actual overlap with the interval.
+func (b Scheme[T]) OverlappingSQL(column string, start, stop T, options ...SQLOption) (string, error) {
+	c := sqlOptions(options)
+	column, err := c.dialect.Quote(column)
+	if err != nil {
+		return "", err
+	}
+	plan, err := b.Plan(Overlaps, start, stop)
+	if err != nil {
+		return "", err
+	}
+	return plan.binCondition(column, false, formatBin), nil
+}
+
+// OverlappingRangesSQL returns an SQL condition selecting the same rows as
+// OverlappingSQL using one range of bins per level, such as
+// "((bin BETWEEN 0 AND 1) OR (bin BETWEEN 9 AND 10) OR ...)". Ranges of
+// adjacent bins are merged. The condition stays short for large intervals,
+// where the list of bins for OverlappingSQL gets very long.
+func (b Scheme[T]) OverlappingRangesSQL(column string, start, stop T, options ...SQLOption) (string, error) {
+	c := sqlOptions(options)
+	column, err := c.dialect.Quote(column)
+	if err != nil {
+		return "", err
+	}
+	plan, err := b.Plan(Overlaps, start, stop)
+	if err != nil {
+		return "", err
+	}
+	return plan.binCondition(column, true, formatBin), nil
+}
+
+// OverlappingSQLArgs returns an SQL condition like OverlappingSQL with
+// placeholders instead of bins, such as "bin IN (?,?,?,?,?)", and the bins as
+// arguments for the placeholders. For BigQuery, the condition is
+// "bin IN UNNEST(?)" with the bins as one []int64 argument, and for
+// ClickHouse it is "has(?, bin)" with the same argument.
+func (b Scheme[T]) OverlappingSQLArgs(column string, start, stop T, options ...SQLOption) (string, []any, error) {
+	c := sqlOptions(options)
+	column, err := c.dialect.Quote(column)
+	if err != nil {
+		return "", nil, err
+	}
+	plan, err := b.Plan(Overlaps, start, stop)
+	if err != nil {
+		return "", nil, err
+	}
+	a := &binArgs{c: c}
+	if c.usesArrays() {
+		return c.arrayCondition(column, a.add(plan.binList())), a.args, nil
+	}
+	return plan.binCondition(column, false, func(bin int64) string { return a.add(bin) }), a.args, nil
+}
+
+// OverlappingRangesSQLArgs returns an SQL condition like
+// OverlappingRangesSQL with placeholders instead of bins, such as
+// "((bin BETWEEN ? AND ?) OR (bin = ?) OR ...)", and the bins as arguments
+// for the placeholders.
+func (b Scheme[T]) OverlappingRangesSQLArgs(column string, start, stop T, options ...SQLOption) (string, []any, error) {
+	c := sqlOptions(options)
+	column, err := c.dialect.Quote(column)
+	if err != nil {
+		return "", nil, err
+	}
+	plan, err := b.Plan(Overlaps, start, stop)
+	if err != nil {
+		return "", nil, err
+	}
+	a := &binArgs{c: c}
+	return plan.binCondition(column, true, func(bin int64) string { return a.add(bin) }), a.args, nil
+}
+
+// int8range returns a PostgreSQL expression for the half-open range start:stop.
+func int8range(start, stop string) string {
+	return "int8range(" + start + ", " + stop + ")"
+}
+
+// OverlappingRegionSQLArgs returns an SQL condition selecting rows with a
+// region overlapping the region start:stop on chromosome chrom, and the
+// arguments for its placeholders. The condition combines the bin filter of
+// OverlappingSQLArgs with the exact overlap check, such as
+// "chrom = ? AND bin IN (?,?,?,?,?) AND start < ? AND stop > ?". With the
+// WithRangeTypes option, the condition is like
+// "chrom = $1 AND int8range(start, stop) && int8range($2, $3)" instead.
+func (b Scheme[T]) OverlappingRegionSQLArgs(chrom string, start, stop T, options ...SQLOption) (string, []any, error) {
+	c := sqlOptions(options)
+	var columns [4]string
+	for i, column := range c.columns {
+		quoted, err := c.dialect.Quote(column)
+		if err != nil {
+			return "", nil, err
+		}
+		columns[i] = quoted
+	}
+	if c.ranges {
+		if c.dialect != PostgreSQL {
+			return "", nil, errors.New("range types require the PostgreSQL dialect")
+		}
+		if _, _, err := b.checkRange(start, stop); err != nil {
+			return "", nil, err
+		}
+		clause := columns[0] + " = " + c.placeholder(1) +
+			" AND " + int8range(columns[1], columns[2]) +
+			" && " + int8range(c.placeholder(2), c.placeholder(3))
+		return clause, []any{chrom, int64(start), int64(stop)}, nil
+	}
+	plan, err := b.Plan(Overlaps, start, stop)
+	if err != nil {
+		return "", nil, err
+	}
+	return regionCondition(c, columns, plan, chrom, false)
+}
+
+// AssignSQL returns an SQL expression computing the bin assigned to an
+// interval with its start and stop positions in the start and stop columns,
+// as Assign does. This makes it possible to add a bin column to existing
+// data, such as Parquet files queried with DuckDB, without a user-defined
+// function. Positions are not checked against the range of the binning
+// scheme.
+func (b Scheme[T]) AssignSQL(start, stop string, options ...SQLOption) (string, error) {
+	c := sqlOptions(options)
+	if err := b.check(); err != nil {
+		return "", err
+	}
+	start, err := c.dialect.Quote(start)
+	if err != nil {
+		return "", err
+	}
+	stop, err = c.dialect.Quote(stop)
+	if err != nil {
+		return "", err
+	}
+	greatest := "greatest"
+	if c.dialect == SQLite {
+		greatest = "max"
+	}
+	last := greatest + "(" + stop + " - 1, " + start + ")"
+
+	// ClickHouse has no shift operators.
+	shift := func(position string, level int) string {
+		if c.dialect == ClickHouse {
+			return fmt.Sprintf("bitShiftRight(%s, %d)", position, b.shifts[level])
+		}
+		return fmt.Sprintf("(%s >> %d)", position, b.shifts[level])
+	}
+
+	top := len(b.binOffsets) - 1
+	if top == 0 {
+		return fmt.Sprintf("%d + %s", b.binOffsets[top], shift(start, top)), nil
+	}
+	var s strings.Builder
+	s.WriteString("CASE")
+	for level, offset := range b.binOffsets[:top] {
+		fmt.Fprintf(&s, " WHEN %s = %s THEN %d + %s", shift(start, level), shift(last, level), offset, shift(start, level))
+	}
+	fmt.Fprintf(&s, " ELSE %d + %s END", b.binOffsets[top], shift(start, top))
+	return s.String(), nil
+}
@@ -0,0 +1,282 @@
+package binning
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOverlappingSQL(t *testing.T) {
+	b := StandardBinning()
+	tests := []struct {
+		start, stop int
+		expected    string
+	}{
+		{0, 1, "bin IN (0,1,9,73,585)"},
+		{1 << 17, 1<<17 + 1, "bin IN (0,1,9,73,586)"},
+		{1<<17 - 1, 1<<17 + 1, "bin IN (0,1,9,73,585,586)"},
+	}
+	for _, test := range tests {
+		clause, error := b.OverlappingSQL("bin", test.start, test.stop)
+		if error != nil {
+			t.Errorf("OverlappingSQL(%d, %d) error: %v", test.start, test.stop, error)
+		} else if clause != test.expected {
+			t.Errorf("OverlappingSQL(%d, %d) = %q, expected %q", test.start, test.stop, clause, test.expected)
+		}
+	}
+
+	if _, error := b.OverlappingSQL("bin", 0, 1<<30); error == nil {
+		t.Errorf("OverlappingSQL(0, 1<<30) did not return error")
+	}
+	if _, error := b.OverlappingSQL("", 0, 1); error == nil {
+		t.Errorf("OverlappingSQL with empty column did not return error")
+	}
+	if clause, error := NewCSIScheme[int32](14, -1).OverlappingSQL("bin", 0, 1); !errors.Is(error, ErrInvalidScheme) {
+		t.Errorf("OverlappingSQL on invalid CSI scheme = %q, %v, expected ErrInvalidScheme", clause, error)
+	}
+}
+
+func TestOverlappingRangesSQL(t *testing.T) {
+	b := StandardBinning()
+	tests := []struct {
+		start, stop int
+		expected    string
+	}{
+		{0, 1, "((bin BETWEEN 0 AND 1) OR (bin = 9) OR (bin = 73) OR (bin = 585))"},
+		{1<<17 - 1, 1<<17 + 1, "((bin BETWEEN 0 AND 1) OR (bin = 9) OR (bin = 73) OR (bin BETWEEN 585 AND 586))"},
+		{0, 1 << 29, "((bin BETWEEN 0 AND 4680))"},
+		{1 << 28, 1 << 29, "((bin = 0) OR (bin BETWEEN 5 AND 8) OR (bin BETWEEN 41 AND 72) OR (bin BETWEEN 329 AND 584) OR (bin BETWEEN 2633 AND 4680))"},
+	}
+	for _, test := range tests {
+		clause, error := b.OverlappingRangesSQL("bin", test.start, test.stop)
+		if error != nil {
+			t.Errorf("OverlappingRangesSQL(%d, %d) error: %v", test.start, test.stop, error)
+		} else if clause != test.expected {
+			t.Errorf("OverlappingRangesSQL(%d, %d) = %q, expected %q", test.start, test.stop, clause, test.expected)
+		}
+	}
+
+	if _, error := b.OverlappingRangesSQL("bin", 0, 1<<30); error == nil {
+		t.Errorf("OverlappingRangesSQL(0, 1<<30) did not return error")
+	}
+	if _, error := b.OverlappingRangesSQL("", 0, 1); error == nil {
+		t.Errorf("OverlappingRangesSQL with empty column did not return error")
+	}
+}
+
+func TestDialectQuote(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		name     string
+		expected string
+	}{
+		{DefaultDialect, "bin", "bin"},
+		{MySQL, "bin", "`bin`"},
+		{MySQL, "genes.bin", "`genes`.`bin`"},
+		{MySQL, "a`b", "`a``b`"},
+		{PostgreSQL, "genes.bin", `"genes"."bin"`},
+		{SQLite, `a"b`, `"a""b"`},
+	}
+	for _, test := range tests {
+		quoted, error := test.dialect.Quote(test.name)
+		if error != nil {
+			t.Errorf("Quote(%q) error: %v", test.name, error)
+		} else if quoted != test.expected {
+			t.Errorf("Quote(%q) = %q, expected %q", test.name, quoted, test.expected)
+		}
+	}
+
+	for _, name := range []string{"", "genes.", ".bin"} {
+		if _, error := PostgreSQL.Quote(name); error == nil {
+			t.Errorf("Quote(%q) did not return error", name)
+		}
+	}
+	if _, error := Dialect(10).Quote("bin"); error == nil {
+		t.Errorf("Quote with unknown dialect did not return error")
+	}
+}
+
+func TestDialectPlaceholder(t *testing.T) {
+	if p := MySQL.Placeholder(2); p != "?" {
+		t.Errorf("MySQL.Placeholder(2) = %q, expected %q", p, "?")
+	}
+	if p := PostgreSQL.Placeholder(2); p != "$2" {
+		t.Errorf("PostgreSQL.Placeholder(2) = %q, expected %q", p, "$2")
+	}
+}
+
+func TestSQLWithDialect(t *testing.T) {
+	b := StandardBinning()
+	clause, _ := b.OverlappingSQL("genes.bin", 0, 1, WithDialect(MySQL))
+	if expected := "`genes`.`bin` IN (0,1,9,73,585)"; clause != expected {
+		t.Errorf("OverlappingSQL with MySQL = %q, expected %q", clause, expected)
+	}
+	clause, _ = b.OverlappingRangesSQL("bin", 0, 1<<29, WithDialect(PostgreSQL))
+	if expected := `(("bin" BETWEEN 0 AND 4680))`; clause != expected {
+		t.Errorf("OverlappingRangesSQL with PostgreSQL = %q, expected %q", clause, expected)
+	}
+}
+
+func TestOverlappingSQLArgs(t *testing.T) {
+	b := StandardBinning()
+	clause, args, error := b.OverlappingSQLArgs("bin", 0, 1)
+	if error != nil {
+		t.Fatalf("OverlappingSQLArgs error: %v", error)
+	}
+	if expected := "bin IN (?,?,?,?,?)"; clause != expected {
+		t.Errorf("OverlappingSQLArgs = %q, expected %q", clause, expected)
+	}
+	if expected := []any{int64(0), int64(1), int64(9), int64(73), int64(585)}; !slices.Equal(args, expected) {
+		t.Errorf("OverlappingSQLArgs args = %v, expected %v", args, expected)
+	}
+
+	clause, _, _ = b.OverlappingSQLArgs("bin", 0, 1, WithDialect(PostgreSQL), WithPrecedingArgs(2))
+	if expected := `"bin" IN ($3,$4,$5,$6,$7)`; clause != expected {
+		t.Errorf("OverlappingSQLArgs with PostgreSQL = %q, expected %q", clause, expected)
+	}
+
+	if _, _, error := b.OverlappingSQLArgs("bin", 0, 1<<30); error == nil {
+		t.Errorf("OverlappingSQLArgs(0, 1<<30) did not return error")
+	}
+}
+
+func TestOverlappingRangesSQLArgs(t *testing.T) {
+	b := StandardBinning()
+	clause, args, error := b.OverlappingRangesSQLArgs("bin", 0, 1, WithDialect(PostgreSQL))
+	if error != nil {
+		t.Fatalf("OverlappingRangesSQLArgs error: %v", error)
+	}
+	if expected := `(("bin" BETWEEN $1 AND $2) OR ("bin" = $3) OR ("bin" = $4) OR ("bin" = $5))`; clause != expected {
+		t.Errorf("OverlappingRangesSQLArgs = %q, expected %q", clause, expected)
+	}
+	if expected := []any{int64(0), int64(1), int64(9), int64(73), int64(585)}; !slices.Equal(args, expected) {
+		t.Errorf("OverlappingRangesSQLArgs args = %v, expected %v", args, expected)
+	}
+
+	if _, _, error := b.OverlappingRangesSQLArgs("", 0, 1); error == nil {
+		t.Errorf("OverlappingRangesSQLArgs with empty column did not return error")
+	}
+}
+
+func TestOverlappingRegionSQLArgs(t *testing.T) {
+	b := StandardBinning()
+	clause, args, error := b.OverlappingRegionSQLArgs("chr1", 10, 20)
+	if error != nil {
+		t.Fatalf("OverlappingRegionSQLArgs error: %v", error)
+	}
+	if expected := "chrom = ? AND bin IN (?,?,?,?,?) AND start < ? AND stop > ?"; clause != expected {
+		t.Errorf("OverlappingRegionSQLArgs = %q, expected %q", clause, expected)
+	}
+	if expected := []any{"chr1", int64(0), int64(1), int64(9), int64(73), int64(585), int64(20), int64(10)}; !slices.Equal(args, expected) {
+		t.Errorf("OverlappingRegionSQLArgs args = %v, expected %v", args, expected)
+	}
+
+	options := []SQLOption{WithDialect(PostgreSQL), WithPrecedingArgs(1), WithRegionColumns("c", "s", "e", "b")}
+	clause, _, _ = b.OverlappingRegionSQLArgs("chr1", 10, 20, options...)
+	if expected := `"c" = $2 AND "b" IN ($3,$4,$5,$6,$7) AND "s" < $8 AND "e" > $9`; clause != expected {
+		t.Errorf("OverlappingRegionSQLArgs with PostgreSQL = %q, expected %q", clause, expected)
+	}
+
+	if _, _, error := b.OverlappingRegionSQLArgs("chr1", 10, 20, WithRegionColumns("", "s", "e", "b")); error == nil {
+		t.Errorf("OverlappingRegionSQLArgs with empty column did not return error")
+	}
+}
+
+func TestOverlappingRegionSQLArgsRangeTypes(t *testing.T) {
+	b := StandardBinning()
+	clause, args, error := b.OverlappingRegionSQLArgs("chr1", 10, 20, WithDialect(PostgreSQL), WithRangeTypes(), WithPrecedingArgs(1))
+	if error != nil {
+		t.Fatalf("OverlappingRegionSQLArgs error: %v", error)
+	}
+	if expected := `"chrom" = $2 AND int8range("start", "stop") && int8range($3, $4)`; clause != expected {
+		t.Errorf("OverlappingRegionSQLArgs = %q, expected %q", clause, expected)
+	}
+	if expected := []any{"chr1", int64(10), int64(20)}; !slices.Equal(args, expected) {
+		t.Errorf("OverlappingRegionSQLArgs args = %v, expected %v", args, expected)
+	}
+
+	if _, _, error := b.OverlappingRegionSQLArgs("chr1", 10, 20, WithDialect(MySQL), WithRangeTypes()); error == nil {
+		t.Errorf("OverlappingRegionSQLArgs with range types for MySQL did not return error")
+	}
+	if _, _, error := b.OverlappingRegionSQLArgs("chr1", 0, 1<<30, WithDialect(PostgreSQL), WithRangeTypes()); error == nil {
+		t.Errorf("OverlappingRegionSQLArgs(0, 1<<30) with range types did not return error")
+	}
+}
+
+func TestSQLBigQuery(t *testing.T) {
+	b := StandardBinning()
+	quoted, _ := BigQuery.Quote("my-project.genomics.a`b")
+	if expected := "`my-project`.`genomics`.`a\\`b`"; quoted != expected {
+		t.Errorf("BigQuery.Quote = %q, expected %q", quoted, expected)
+	}
+
+	clause, args, error := b.OverlappingSQLArgs("bin", 0, 1, WithDialect(BigQuery))
+	if error != nil {
+		t.Fatalf("OverlappingSQLArgs error: %v", error)
+	}
+	if expected := "`bin` IN UNNEST(?)"; clause != expected {
+		t.Errorf("OverlappingSQLArgs with BigQuery = %q, expected %q", clause, expected)
+	}
+	if len(args) != 1 || !slices.Equal(args[0].([]int64), []int64{0, 1, 9, 73, 585}) {
+		t.Errorf("OverlappingSQLArgs with BigQuery args = %v, expected [[0 1 9 73 585]]", args)
+	}
+
+	clause, args, _ = b.OverlappingRegionSQLArgs("chr1", 10, 20, WithDialect(BigQuery))
+	if expected := "`chrom` = ? AND `bin` IN UNNEST(?) AND `start` < ? AND `stop` > ?"; clause != expected || len(args) != 4 {
+		t.Errorf("OverlappingRegionSQLArgs with BigQuery = %q with %d args, expected %q with 4 args", clause, len(args), expected)
+	}
+}
+
+func TestSQLClickHouse(t *testing.T) {
+	clause, args, error := StandardBinning().OverlappingRegionSQLArgs("chr1", 10, 20, WithDialect(ClickHouse))
+	if error != nil {
+		t.Fatalf("OverlappingRegionSQLArgs error: %v", error)
+	}
+	if expected := "`chrom` = ? AND has(?, `bin`) AND `start` < ? AND `stop` > ?"; clause != expected {
+		t.Errorf("OverlappingRegionSQLArgs with ClickHouse = %q, expected %q", clause, expected)
+	}
+	if len(args) != 4 || !slices.Equal(args[1].([]int64), []int64{0, 1, 9, 73, 585}) {
+		t.Errorf("OverlappingRegionSQLArgs with ClickHouse args = %v", args)
+	}
+}
+
+func TestAssignSQL(t *testing.T) {
+	b, _ := NewBinningFromLevels(1<<20-1, []int{9, 1, 0}, []uint{17, 20, 23})
+	expression, error := b.AssignSQL("start", "stop", WithDialect(DuckDB))
+	if error != nil {
+		t.Fatalf("AssignSQL error: %v", error)
+	}
+	expected := `CASE WHEN ("start" >> 17) = (greatest("stop" - 1, "start") >> 17) THEN 9 + ("start" >> 17)` +
+		` WHEN ("start" >> 20) = (greatest("stop" - 1, "start") >> 20) THEN 1 + ("start" >> 20)` +
+		` ELSE 0 + ("start" >> 23) END`
+	if expression != expected {
+		t.Errorf("AssignSQL = %q, expected %q", expression, expected)
+	}
+
+	expression, _ = b.AssignSQL("start", "stop", WithDialect(SQLite))
+	if !strings.Contains(expression, `max("stop" - 1, "start")`) {
+		t.Errorf("AssignSQL with SQLite = %q, expected max function", expression)
+	}
+	if _, error := b.AssignSQL("", "stop"); error == nil {
+		t.Errorf("AssignSQL with empty column did not return error")
+	}
+
+	expression, _ = b.AssignSQL("start", "stop", WithDialect(ClickHouse))
+	expected = "CASE WHEN bitShiftRight(`start`, 17) = bitShiftRight(greatest(`stop` - 1, `start`), 17) THEN 9 + bitShiftRight(`start`, 17)" +
+		" WHEN bitShiftRight(`start`, 20) = bitShiftRight(greatest(`stop` - 1, `start`), 20) THEN 1 + bitShiftRight(`start`, 20)" +
+		" ELSE 0 + bitShiftRight(`start`, 23) END"
+	if expression != expected {
+		t.Errorf("AssignSQL with ClickHouse = %q, expected %q", expression, expected)
+	}
+
+	b, _ = NewBinningFromLevels(1<<20-1, []int{0}, []uint{20})
+	if expression, error := b.AssignSQL("start", "stop"); error != nil || expression != "0 + (start >> 20)" {
+		t.Errorf("AssignSQL with a single level = %q, %v, expected %q", expression, error, "0 + (start >> 20)")
+	}
+	for _, b := range []Binning{{}, NewBinning(100, []int{}, 1, 1)} {
+		if expression, error := b.AssignSQL("start", "stop"); !errors.Is(error, ErrInvalidScheme) {
+			t.Errorf("AssignSQL on %v = %q, %v, expected ErrInvalidScheme", b, expression, error)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package binning
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Queryer runs SQL queries, such as *sql.DB, *sql.Tx or *sqlx.DB.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// A Rows iterates over the result of a query, such as *sql.Rows or
+// *sqlx.Rows.
+type Rows interface {
+	Next() bool
+	Err() error
+	Close() error
+}
+
+// quoteColumns returns the names in columns quoted for dialect d, separated
+// by commas.
+func quoteColumns(d Dialect, columns []string) (string, error) {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		q, err := d.Quote(column)
+		if err != nil {
+			return "", err
+		}
+		quoted[i] = q
+	}
+	return strings.Join(quoted, ", "), nil
+}
+
+// OverlappingSelectSQLArgs returns an SQL query selecting columns from table
+// for rows with a region overlapping the region start:stop on chromosome
+// chrom, using the condition of OverlappingRegionSQLArgs, and the arguments
+// for its placeholders.
+func (b Scheme[T]) OverlappingSelectSQLArgs(table string, columns []string, chrom string, start, stop T, options ...SQLOption) (string, []any, error) {
+	c := sqlOptions(options)
+	if len(columns) == 0 {
+		return "", nil, errors.New("no columns to select")
+	}
+	selected, err := quoteColumns(c.dialect, columns)
+	if err != nil {
+		return "", nil, err
+	}
+	table, err = c.dialect.Quote(table)
+	if err != nil {
+		return "", nil, err
+	}
+	where, args, err := b.OverlappingRegionSQLArgs(chrom, start, stop, options...)
+	if err != nil {
+		return "", nil, err
+	}
+	return "SELECT " + selected + " FROM " + table + " WHERE " + where, args, nil
+}
+
+// QueryOverlapping runs the query of OverlappingSelectSQLArgs with binning
+// scheme b using query and returns the rows. Each row is scanned by scan into
+// a new value of type R, typically a struct with a field per selected column.
+// For database/sql, query is the QueryContext method of *sql.DB or *sql.Tx.
+// For sqlx, query is the QueryxContext method of *sqlx.DB and scan can call
+// StructScan on the rows.
+func QueryOverlapping[R any, S Rows](ctx context.Context, query func(ctx context.Context, query string, args ...any) (S, error), b Binning, table string, columns []string, chrom string, start, stop int, scan func(S, *R) error, options ...SQLOption) ([]R, error) {
+	statement, args, err := b.OverlappingSelectSQLArgs(table, columns, chrom, start, stop, options...)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := query(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []R
+	for rows.Next() {
+		var r R
+		if err := scan(rows, &r); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, rows.Close()
+}
+
+// InsertRegionsSQLArgs returns an SQL statement inserting regions into table
+// with the bins assigned by binning scheme b, and the arguments for its
+// placeholders. Besides the region columns, each row has a value for each of
+// columns, taken from the corresponding element of values.
+func InsertRegionsSQLArgs(b Binning, table string, columns []string, regions []Region, values [][]any, options ...SQLOption) (string, []any, error) {
+	c := sqlOptions(options)
+	if len(regions) == 0 {
+		return "", nil, errors.New("no regions to insert")
+	}
+	if len(values) != len(regions) && !(len(values) == 0 && len(columns) == 0) {
+		return "", nil, errors.New(fmt.Sprintf("%d regions but %d rows of values", len(regions), len(values)))
+	}
+	all, err := quoteColumns(c.dialect, append(c.columns[:], columns...))
+	if err != nil {
+		return "", nil, err
+	}
+	table, err = c.dialect.Quote(table)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var s strings.Builder
+	args := make([]any, 0, len(regions)*(4+len(columns)))
+	s.WriteString("INSERT INTO " + table + " (" + all + ") VALUES ")
+	for i, r := range regions {
+		bin, err := b.Assign(r.Start, r.Stop)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, r.Chrom, int64(r.Start), int64(r.Stop), int64(bin))
+		if len(columns) > 0 {
+			if len(values[i]) != len(columns) {
+				return "", nil, errors.New(fmt.Sprintf("%d columns but %d values for region %d", len(columns), len(values[i]), i))
+			}
+			args = append(args, values[i]...)
+		}
+
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteByte('(')
+		for j := len(args) - 4 - len(columns); j < len(args); j++ {
+			if j > len(args)-4-len(columns) {
+				s.WriteString(", ")
+			}
+			s.WriteString(c.placeholder(j + 1))
+		}
+		s.WriteByte(')')
+	}
+	return s.String(), args, nil
+}
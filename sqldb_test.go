@@ -0,0 +1,206 @@
+package binning
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+// fakeDriver is a database/sql driver answering the queries in fakeQueries
+// in order, recording the arguments of the last query, and recording the
+// statements executed with their arguments.
+type fakeDriver struct{}
+
+// fakeQuery is a query expected by fakeDriver with the columns and rows it
+// returns.
+type fakeQuery struct {
+	query   string
+	columns []string
+	rows    [][]driver.Value
+}
+
+var (
+	fakeQueries []fakeQuery
+	fakeArgs    []driver.Value
+	fakeExecs   []fakeExec
+)
+
+type fakeExec struct {
+	statement string
+	args      []driver.Value
+}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	fakeExecs = append(fakeExecs, fakeExec{s.query, args})
+	return driver.RowsAffected(1), nil
+}
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if len(fakeQueries) == 0 {
+		return nil, errors.New(fmt.Sprintf("unexpected query %q", s.query))
+	}
+	q := fakeQueries[0]
+	if s.query != q.query {
+		return nil, errors.New(fmt.Sprintf("unexpected query %q, expected %q", s.query, q.query))
+	}
+	fakeQueries, fakeArgs = fakeQueries[1:], args
+	return &fakeResult{columns: q.columns, rows: q.rows}, nil
+}
+
+type fakeResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeResult) Columns() []string { return r.columns }
+func (*fakeResult) Close() error        { return nil }
+func (r *fakeResult) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("binning-fake", fakeDriver{})
+}
+
+func TestOverlappingSelectSQLArgs(t *testing.T) {
+	b := StandardBinning()
+	query, args, error := b.OverlappingSelectSQLArgs("genes", []string{"name", "chrom_start"}, "chr1", 10, 20, WithDialect(MySQL))
+	if error != nil {
+		t.Fatalf("OverlappingSelectSQLArgs error: %v", error)
+	}
+	expected := "SELECT `name`, `chrom_start` FROM `genes` WHERE `chrom` = ? AND `bin` IN (?,?,?,?,?) AND `start` < ? AND `stop` > ?"
+	if query != expected {
+		t.Errorf("OverlappingSelectSQLArgs = %q, expected %q", query, expected)
+	}
+	if len(args) != 8 {
+		t.Errorf("OverlappingSelectSQLArgs returned %d args, expected %d", len(args), 8)
+	}
+
+	if _, _, error := b.OverlappingSelectSQLArgs("genes", nil, "chr1", 10, 20); error == nil {
+		t.Errorf("OverlappingSelectSQLArgs without columns did not return error")
+	}
+	if _, _, error := b.OverlappingSelectSQLArgs("", []string{"name"}, "chr1", 10, 20); error == nil {
+		t.Errorf("OverlappingSelectSQLArgs without table did not return error")
+	}
+}
+
+type fakeGene struct {
+	Name  string
+	Start int64
+}
+
+func scanFakeGene(rows *sql.Rows, g *fakeGene) error {
+	return rows.Scan(&g.Name, &g.Start)
+}
+
+// structRows wraps *sql.Rows the way sqlx.Rows does, adding StructScan.
+type structRows struct{ *sql.Rows }
+
+func (r structRows) StructScan(g *fakeGene) error {
+	return r.Scan(&g.Name, &g.Start)
+}
+
+func queryStructRows(db *sql.DB) func(context.Context, string, ...any) (structRows, error) {
+	return func(ctx context.Context, query string, args ...any) (structRows, error) {
+		rows, err := db.QueryContext(ctx, query, args...)
+		return structRows{rows}, err
+	}
+}
+
+func structScanFakeGene(rows structRows, g *fakeGene) error {
+	return rows.StructScan(g)
+}
+
+func TestQueryOverlapping(t *testing.T) {
+	db, error := sql.Open("binning-fake", "")
+	if error != nil {
+		t.Fatalf("sql.Open error: %v", error)
+	}
+	defer db.Close()
+
+	query := "SELECT name, chrom_start FROM genes WHERE chrom = ? AND bin IN (?,?,?,?,?) AND start < ? AND stop > ?"
+	columns := []string{"name", "chrom_start"}
+	fakeQueries = []fakeQuery{{query, columns, [][]driver.Value{{"DDX11L1", int64(11873)}, {"WASH7P", int64(14403)}}}}
+	genes, error := QueryOverlapping(context.Background(), db.QueryContext, StandardBinning(), "genes", columns, "chr1", 14000, 15000, scanFakeGene)
+	if error != nil {
+		t.Fatalf("QueryOverlapping error: %v", error)
+	}
+	if expected := []fakeGene{{"DDX11L1", 11873}, {"WASH7P", 14403}}; !slices.Equal(genes, expected) {
+		t.Errorf("QueryOverlapping = %v, expected %v", genes, expected)
+	}
+	if len(fakeArgs) != 8 || fakeArgs[0] != "chr1" {
+		t.Errorf("QueryOverlapping passed args %v", fakeArgs)
+	}
+
+	fakeQueries = []fakeQuery{{query, columns, [][]driver.Value{{"DDX11L1", int64(11873)}}}}
+	genes, error = QueryOverlapping(context.Background(), queryStructRows(db), StandardBinning(), "genes", columns, "chr1", 14000, 15000, structScanFakeGene)
+	if expected := []fakeGene{{"DDX11L1", 11873}}; error != nil || !slices.Equal(genes, expected) {
+		t.Errorf("QueryOverlapping with struct scan = %v, %v, expected %v", genes, error, expected)
+	}
+
+	fakeQueries = []fakeQuery{{query, columns, [][]driver.Value{{"DDX11L1", "unknown"}}}}
+	_, error = QueryOverlapping(context.Background(), db.QueryContext, StandardBinning(), "genes", columns, "chr1", 14000, 15000, scanFakeGene)
+	if error == nil {
+		t.Errorf("QueryOverlapping did not return scan error")
+	}
+	if len(fakeQueries) != 0 {
+		t.Errorf("QueryOverlapping did not run %d expected queries", len(fakeQueries))
+	}
+}
+
+func TestInsertRegionsSQLArgs(t *testing.T) {
+	b := StandardBinning()
+	regions := []Region{{"chr1", 10, 20}, {"chr2", 1 << 17, 1<<17 + 5}}
+	statement, args, error := InsertRegionsSQLArgs(b, "genes", []string{"name"}, regions, [][]any{{"a"}, {"b"}}, WithDialect(PostgreSQL))
+	if error != nil {
+		t.Fatalf("InsertRegionsSQLArgs error: %v", error)
+	}
+	expected := `INSERT INTO "genes" ("chrom", "start", "stop", "bin", "name") VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)`
+	if statement != expected {
+		t.Errorf("InsertRegionsSQLArgs = %q, expected %q", statement, expected)
+	}
+	if expected := []any{"chr1", int64(10), int64(20), int64(585), "a", "chr2", int64(1 << 17), int64(1<<17 + 5), int64(586), "b"}; !slices.Equal(args, expected) {
+		t.Errorf("InsertRegionsSQLArgs args = %v, expected %v", args, expected)
+	}
+
+	statement, _, error = InsertRegionsSQLArgs(b, "genes", nil, regions[:1], nil)
+	if expected := "INSERT INTO genes (chrom, start, stop, bin) VALUES (?, ?, ?, ?)"; error != nil || statement != expected {
+		t.Errorf("InsertRegionsSQLArgs = %q, %v, expected %q", statement, error, expected)
+	}
+
+	tests := []struct {
+		regions []Region
+		values  [][]any
+	}{
+		{nil, nil},
+		{regions, [][]any{{"a"}}},
+		{regions, [][]any{{"a"}, {"b", "c"}}},
+		{[]Region{{"chr1", 0, 1 << 30}}, [][]any{{"a"}}},
+	}
+	for _, test := range tests {
+		if _, _, error := InsertRegionsSQLArgs(b, "genes", []string{"name"}, test.regions, test.values); error == nil {
+			t.Errorf("InsertRegionsSQLArgs(%v, %v) did not return error", test.regions, test.values)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/martijnvermaat/binning"
+)
+
+// memoryKV is an OrderedKV in memory. Transactions are applied on commit.
+type memoryKV struct {
+	data map[string][]byte
+}
+
+type memoryTx struct {
+	kv       *memoryKV
+	writes   map[string][]byte
+	writable bool
+}
+
+func (tx *memoryTx) Get(key []byte) ([]byte, error) {
+	if value, ok := tx.writes[string(key)]; ok {
+		return value, nil
+	}
+	return tx.kv.data[string(key)], nil
+}
+
+func (tx *memoryTx) Set(key, value []byte) error {
+	if !tx.writable {
+		return errors.New("read-only transaction")
+	}
+	tx.writes[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (tx *memoryTx) Iterate(prefix []byte, fn func(key, value []byte) error) error {
+	var keys []string
+	for key := range tx.kv.data {
+		keys = append(keys, key)
+	}
+	for key := range tx.writes {
+		if _, ok := tx.kv.data[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if strings.HasPrefix(key, string(prefix)) {
+			value, _ := tx.Get([]byte(key))
+			if err := fn([]byte(key), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (kv *memoryKV) Update(fn func(OrderedTx) error) error {
+	tx := &memoryTx{kv: kv, writes: make(map[string][]byte), writable: true}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for key, value := range tx.writes {
+		kv.data[key] = value
+	}
+	return nil
+}
+
+func (kv *memoryKV) View(fn func(OrderedTx) error) error {
+	return fn(&memoryTx{kv: kv})
+}
+
+func (kv *memoryKV) Close() error {
+	return nil
+}
+
+func TestOrderedBackend(t *testing.T) {
+	kv := &memoryKV{data: make(map[string][]byte)}
+	s, error := Open(NewOrderedBackend(kv), binning.StandardBinning())
+	if error != nil {
+		t.Fatalf("Open error: %v", error)
+	}
+	if error := s.Insert(storeEntries[:3]...); error != nil {
+		t.Fatalf("Insert error: %v", error)
+	}
+	if error := s.Insert(storeEntries[3:]...); error != nil {
+		t.Fatalf("Insert error: %v", error)
+	}
+
+	entries, error := s.Overlapping("chr1", 99, 1<<17+1)
+	if expected := []string{"a", "b", "c", "d"}; error != nil || !slices.Equal(payloads(entries), expected) {
+		t.Errorf("Overlapping = %v, %v, expected %v", payloads(entries), error, expected)
+	}
+	if _, error := Open(NewOrderedBackend(kv), binning.TabixBinning()); error == nil {
+		t.Errorf("Open with different binning scheme did not return error")
+	}
+}
+
+func TestOrderedBackendOrder(t *testing.T) {
+	backend := NewOrderedBackend(&memoryKV{data: make(map[string][]byte)})
+	for _, value := range []string{"a", "b", "c"} {
+		if error := backend.Update(appendTo("x", value)); error != nil {
+			t.Fatalf("Update error: %v", error)
+		}
+	}
+	backend.Update(appendTo("x0", "d"))
+
+	var values []string
+	backend.View(func(tx Tx) error {
+		return tx.Each("x", func(value []byte) error {
+			values = append(values, string(value))
+			return nil
+		})
+	})
+	if expected := []string{"a", "b", "c"}; !slices.Equal(values, expected) {
+		t.Errorf("Each = %v, expected %v", values, expected)
+	}
+
+	if error := backend.Update(appendTo("x\x00y", "e")); error == nil {
+		t.Errorf("Append with invalid bucket name did not return error")
+	}
+}
+
+func TestOrderedBackendKeys(t *testing.T) {
+	kv := &memoryKV{data: make(map[string][]byte)}
+	backend := NewOrderedBackend(kv)
+	backend.Update(appendTo("x", "a", "b"))
+	backend.Update(appendTo("y", "c"))
+	backend.Update(appendTo("x", "d"))
+	for key := range kv.data {
+		if !strings.HasPrefix(key, "x\x00") && !strings.HasPrefix(key, "y\x00") {
+			t.Errorf("Append wrote key %q outside its bucket", key)
+		}
+	}
+	if len(kv.data) != 4 {
+		t.Errorf("Append wrote %d keys, expected %d", len(kv.data), 4)
+	}
+
+	var values []string
+	backend.View(func(tx Tx) error {
+		return tx.Each("x", func(value []byte) error {
+			values = append(values, string(value))
+			return nil
+		})
+	})
+	if expected := []string{"a", "b", "d"}; !slices.Equal(values, expected) {
+		t.Errorf("Each = %v, expected %v", values, expected)
+	}
+}
+
+func appendTo(bucket string, values ...string) func(Tx) error {
+	return func(tx Tx) error {
+		for _, value := range values {
+			if err := tx.Append(bucket, []byte(value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,211 @@
+// Package store implements a persistent interval index on top of a
+// transactional key-value backend. Intervals are kept in one bucket per
+// chromosome and bin of a binning scheme, so queries only read the buckets
+// for the bins overlapping the query interval.
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/martijnvermaat/binning"
+)
+
+// A Backend is a transactional key-value store holding values in named
+// buckets. A bucket in bbolt or a key prefix in Badger can implement a
+// bucket.
+type Backend interface {
+	// Update calls fn with a read-write transaction, which is committed if
+	// fn returns nil and discarded otherwise.
+	Update(fn func(Tx) error) error
+
+	// View calls fn with a read-only transaction.
+	View(fn func(Tx) error) error
+
+	// Close closes the backend.
+	Close() error
+}
+
+// A Tx is a transaction on a Backend.
+type Tx interface {
+	// Append adds value to bucket, creating the bucket if needed. Append
+	// fails in a read-only transaction.
+	Append(bucket string, value []byte) error
+
+	// Each calls fn with the values in bucket in the order they were
+	// added, until fn returns an error. The value is only valid during the
+	// call. A bucket that does not exist is empty.
+	Each(bucket string, fn func(value []byte) error) error
+}
+
+// schemeBucket holds the binning scheme of a store.
+const schemeBucket = "scheme"
+
+// An Entry is an interval on a chromosome with a payload.
+type Entry struct {
+	Chrom       string
+	Start, Stop int
+	Payload     []byte
+}
+
+// A Store is a persistent index of intervals with payloads.
+type Store struct {
+	backend Backend
+	scheme  binning.Binning
+}
+
+// Open returns a store on backend with binning scheme b. The binning scheme
+// is saved in a new store and must be the same for an existing store.
+func Open(backend Backend, b binning.Binning) (*Store, error) {
+	scheme, err := b.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	err = backend.Update(func(tx Tx) error {
+		var saved []byte
+		err := tx.Each(schemeBucket, func(value []byte) error {
+			saved = append([]byte{}, value...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if saved == nil {
+			return tx.Append(schemeBucket, scheme)
+		}
+		if string(saved) != string(scheme) {
+			return errors.New(fmt.Sprintf("store has a different binning scheme: %s", saved))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Store{backend: backend, scheme: b}, nil
+}
+
+// Scheme returns the binning scheme of the store.
+func (s *Store) Scheme() binning.Binning {
+	return s.scheme
+}
+
+// Close closes the backend of the store.
+func (s *Store) Close() error {
+	return s.backend.Close()
+}
+
+// bucket returns the name of the bucket for bin on chromosome chrom.
+func bucket(chrom string, bin int) string {
+	return "bin:" + chrom + ":" + strconv.Itoa(bin)
+}
+
+// encode returns the value stored for an interval start:stop with payload.
+func encode(start, stop int, payload []byte) []byte {
+	value := binary.AppendUvarint(nil, uint64(start))
+	value = binary.AppendUvarint(value, uint64(stop-start))
+	return append(value, payload...)
+}
+
+// decode returns the interval and payload of a stored value.
+func decode(value []byte) (int, int, []byte, error) {
+	start, n := binary.Uvarint(value)
+	if n <= 0 {
+		return 0, 0, nil, errors.New("invalid stored interval")
+	}
+	length, m := binary.Uvarint(value[n:])
+	if m <= 0 {
+		return 0, 0, nil, errors.New("invalid stored interval")
+	}
+	return int(start), int(start + length), value[n+m:], nil
+}
+
+// Insert adds entries to the store in a single transaction, so either all
+// or none of the entries are added.
+func (s *Store) Insert(entries ...Entry) error {
+	buckets := make([]string, len(entries))
+	for i, e := range entries {
+		if e.Start < 0 || e.Stop < e.Start {
+			return errors.New(fmt.Sprintf("invalid interval: %d-%d", e.Start, e.Stop))
+		}
+		bin, err := s.scheme.Assign(e.Start, e.Stop)
+		if err != nil {
+			return err
+		}
+		buckets[i] = bucket(e.Chrom, bin)
+	}
+	return s.backend.Update(func(tx Tx) error {
+		for i, e := range entries {
+			if err := tx.Append(buckets[i], encode(e.Start, e.Stop, e.Payload)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// query returns the entries on chromosome chrom in bins for which keep
+// returns true.
+func (s *Store) query(chrom string, bins []int, keep func(start, stop int) bool) ([]Entry, error) {
+	entries := []Entry{}
+	err := s.backend.View(func(tx Tx) error {
+		for _, bin := range bins {
+			err := tx.Each(bucket(chrom, bin), func(value []byte) error {
+				start, stop, payload, err := decode(value)
+				if err != nil {
+					return err
+				}
+				if keep(start, stop) {
+					entries = append(entries, Entry{chrom, start, stop, append([]byte{}, payload...)})
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Overlapping returns the entries on chromosome chrom overlapping the
+// interval start:stop by at least one position. Entries are ordered by bin,
+// starting with the smallest bins, and by insertion order within a bin.
+func (s *Store) Overlapping(chrom string, start, stop int) ([]Entry, error) {
+	bins, err := s.scheme.Overlapping(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return s.query(chrom, bins, func(entryStart, entryStop int) bool {
+		return max(entryStart, start) < min(entryStop, stop)
+	})
+}
+
+// Containing returns the entries on chromosome chrom completely containing
+// the interval start:stop, ordered as for Overlapping.
+func (s *Store) Containing(chrom string, start, stop int) ([]Entry, error) {
+	bins, err := s.scheme.Containing(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return s.query(chrom, bins, func(entryStart, entryStop int) bool {
+		return entryStart <= start && stop <= entryStop
+	})
+}
+
+// Contained returns the entries on chromosome chrom completely contained by
+// the interval start:stop, ordered as for Overlapping.
+func (s *Store) Contained(chrom string, start, stop int) ([]Entry, error) {
+	bins, err := s.scheme.Contained(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return s.query(chrom, bins, func(entryStart, entryStop int) bool {
+		return start <= entryStart && entryStop <= stop
+	})
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/martijnvermaat/binning"
+)
+
+var storeEntries = []Entry{
+	{"chr1", 100, 200, []byte("a")},
+	{"chr1", 150, 1 << 17, []byte("b")},
+	{"chr1", 1<<17 - 1, 1<<17 + 1, []byte("c")},
+	{"chr1", 0, 1 << 29, []byte("d")},
+	{"chr2", 100, 200, []byte("e")},
+	{"chr1", 4000000, 6000000, []byte("f")},
+}
+
+// memoryBackend is a Backend in memory. Appended values are applied on
+// commit.
+type memoryBackend struct {
+	buckets map[string][][]byte
+}
+
+type memoryBackendTx struct {
+	backend  *memoryBackend
+	appended map[string][][]byte
+	writable bool
+}
+
+func (tx *memoryBackendTx) Append(bucket string, value []byte) error {
+	if !tx.writable {
+		return errors.New("read-only transaction")
+	}
+	tx.appended[bucket] = append(tx.appended[bucket], append([]byte{}, value...))
+	return nil
+}
+
+func (tx *memoryBackendTx) Each(bucket string, fn func(value []byte) error) error {
+	for _, values := range [][][]byte{tx.backend.buckets[bucket], tx.appended[bucket]} {
+		for _, value := range values {
+			if err := fn(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *memoryBackend) Update(fn func(Tx) error) error {
+	tx := &memoryBackendTx{backend: b, appended: make(map[string][][]byte), writable: true}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for bucket, values := range tx.appended {
+		b.buckets[bucket] = append(b.buckets[bucket], values...)
+	}
+	return nil
+}
+
+func (b *memoryBackend) View(fn func(Tx) error) error {
+	return fn(&memoryBackendTx{backend: b})
+}
+
+func (b *memoryBackend) Close() error {
+	return nil
+}
+
+func openTestStore(t *testing.T, backend Backend) *Store {
+	s, error := Open(backend, binning.StandardBinning())
+	if error != nil {
+		t.Fatalf("Open error: %v", error)
+	}
+	return s
+}
+
+// payloads returns the payloads of entries as a sorted string slice.
+func payloads(entries []Entry) []string {
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, string(e.Payload))
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestStore(t *testing.T) {
+	backend := &memoryBackend{buckets: make(map[string][][]byte)}
+	s := openTestStore(t, backend)
+	if error := s.Insert(storeEntries...); error != nil {
+		t.Fatalf("Insert error: %v", error)
+	}
+	s.Close()
+	s = openTestStore(t, backend)
+	defer s.Close()
+
+	tests := []struct {
+		query      func(string, int, int) ([]Entry, error)
+		name       string
+		start, end int
+		expected   []string
+	}{
+		{s.Overlapping, "Overlapping", 0, 1, []string{"d"}},
+		{s.Overlapping, "Overlapping", 99, 101, []string{"a", "d"}},
+		{s.Overlapping, "Overlapping", 1<<17 - 1, 1<<17 + 1, []string{"b", "c", "d"}},
+		{s.Overlapping, "Overlapping", 4000000, 6000000, []string{"d", "f"}},
+		{s.Containing, "Containing", 160, 170, []string{"a", "b", "d"}},
+		{s.Containing, "Containing", 1<<17 - 1, 1 << 17, []string{"b", "c", "d"}},
+		{s.Contained, "Contained", 0, 1 << 17, []string{"a", "b"}},
+		{s.Contained, "Contained", 0, 1 << 29, []string{"a", "b", "c", "d", "f"}},
+	}
+	for _, test := range tests {
+		entries, error := test.query("chr1", test.start, test.end)
+		if error != nil {
+			t.Errorf("%s(%d, %d) error: %v", test.name, test.start, test.end, error)
+		} else if names := payloads(entries); !slices.Equal(names, test.expected) {
+			t.Errorf("%s(%d, %d) = %v, expected %v", test.name, test.start, test.end, names, test.expected)
+		}
+	}
+
+	entries, _ := s.Overlapping("chr2", 0, 1000)
+	if len(entries) != 1 || entries[0].Start != 100 || entries[0].Stop != 200 {
+		t.Errorf("Overlapping(chr2) = %v, expected entry e", entries)
+	}
+	if _, error := s.Overlapping("chr1", 0, 1<<30); error == nil {
+		t.Errorf("Overlapping(0, 1<<30) did not return error")
+	}
+}
+
+func TestStoreInsertAtomic(t *testing.T) {
+	s := openTestStore(t, &memoryBackend{buckets: make(map[string][][]byte)})
+	defer s.Close()
+	error := s.Insert(Entry{"chr1", 10, 20, []byte("a")}, Entry{"chr1", 0, 1 << 30, []byte("b")})
+	if error == nil {
+		t.Errorf("Insert with invalid interval did not return error")
+	}
+	if entries, _ := s.Overlapping("chr1", 0, 100); len(entries) != 0 {
+		t.Errorf("Insert with invalid interval added %d entries", len(entries))
+	}
+}
+
+func TestOpenSchemeMismatch(t *testing.T) {
+	backend := &memoryBackend{buckets: make(map[string][][]byte)}
+	openTestStore(t, backend).Close()
+	if _, error := Open(backend, binning.TabixBinning()); error == nil {
+		t.Errorf("Open with different binning scheme did not return error")
+	}
+}
@@ -0,0 +1,33 @@
+package tabix
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ReadBAI reads a BAI index for a BAM file from r. BAI indexes are not
+// compressed and do not include sequence names or a tabix configuration, so
+// the index must be queried with ChunksByID. The resulting chunks are
+// virtual offsets in the BAM file.
+// http://samtools.github.io/hts-specs/SAMv1.pdf
+func ReadBAI(r io.Reader) (*Index, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	d := &decoder{data: data}
+	if string(d.bytes(4)) != "BAI\x01" {
+		return nil, errors.New("invalid BAI index: unknown magic")
+	}
+	n := d.int32()
+	if d.err != nil || n < 0 {
+		return nil, errors.New(fmt.Sprintf("invalid BAI index: %d references", n))
+	}
+
+	x := &Index{MinShift: 14, Depth: 5}
+	if err := d.references(x, n, false); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
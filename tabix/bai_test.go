@@ -0,0 +1,89 @@
+package tabix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// encodeBAI returns the index in the BAI format.
+func encodeBAI(x *Index) []byte {
+	data := []byte("BAI\x01")
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(x.References)))
+	return x.appendReferences(data)
+}
+
+func TestReadBAI(t *testing.T) {
+	x, error := Build(bytes.NewReader(compress(bedLines, true)), BED)
+	if error != nil {
+		t.Fatalf("Build error: %v", error)
+	}
+	x.References[0].Unmapped = 4
+	x.NoCoordinate = 7
+
+	y, error := ReadBAI(bytes.NewReader(encodeBAI(x)))
+	if error != nil {
+		t.Fatalf("ReadBAI error: %v", error)
+	}
+	if y.Names != nil || y.NoCoordinate != 7 || !reflect.DeepEqual(y.References, x.References) {
+		t.Errorf("ReadBAI = %v, expected %v", y, x)
+	}
+
+	chr1 := x.References[0]
+	tests := []struct {
+		id          int
+		start, stop int64
+		expected    []Chunk
+	}{
+		{0, 16384, 16385, []Chunk{chr1.Bins[585][0]}},
+		{0, 100000, 200000, nil},
+		{1, 0, 1, []Chunk{x.References[1].Bins[4681][0]}},
+	}
+	for _, test := range tests {
+		chunks, error := y.ChunksByID(test.id, test.start, test.stop)
+		if error != nil || !reflect.DeepEqual(chunks, test.expected) {
+			t.Errorf("ChunksByID(%d, %d, %d) = %v, %v, expected %v", test.id, test.start, test.stop, chunks, error, test.expected)
+		}
+	}
+	for _, id := range []int{-1, 2} {
+		if _, error := y.ChunksByID(id, 0, 100); error == nil {
+			t.Errorf("ChunksByID(%d, 0, 100) did not return error", id)
+		}
+	}
+}
+
+func TestReadBAIInvalid(t *testing.T) {
+	x, _ := Build(bytes.NewReader(compress(bedLines, false)), BED)
+	valid := encodeBAI(x)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"magic", append([]byte("TBI\x01"), valid[4:]...)},
+		{"negative", []byte("BAI\x01\xff\xff\xff\xff")},
+		{"truncated", valid[:len(valid)-9]},
+	}
+	for _, test := range tests {
+		if _, error := ReadBAI(bytes.NewReader(test.data)); error == nil {
+			t.Errorf("ReadBAI(%s) did not return error", test.name)
+		}
+	}
+}
+
+func TestNoCoordinate(t *testing.T) {
+	x, _ := Build(bytes.NewReader(compress(bedLines, false)), BED)
+	x.NoCoordinate = 3
+
+	var tbi, csi bytes.Buffer
+	x.WriteTo(&tbi)
+	x.WriteCSI(&csi)
+	for name, buffer := range map[string]*bytes.Buffer{"tabix": &tbi, "CSI": &csi} {
+		y, error := Read(buffer)
+		if error != nil || y.NoCoordinate != 3 {
+			t.Errorf("Read(%s) NoCoordinate = %v, %v, expected 3", name, y.NoCoordinate, error)
+		}
+	}
+}
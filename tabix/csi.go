@@ -0,0 +1,59 @@
+package tabix
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/martijnvermaat/binning/bgzf"
+)
+
+// validCSI returns an error if bin numbers in the binning scheme with
+// minShift and depth do not fit in 32 bits or positions do not fit in 64
+// bits.
+func validCSI(minShift, depth int) error {
+	if minShift < 1 || depth < 1 || depth > 10 || minShift+3*depth > 62 {
+		return errors.New(fmt.Sprintf("invalid CSI parameters: min_shift %d, depth %d", minShift, depth))
+	}
+	return nil
+}
+
+// binOffsets returns the virtual offset of the first record overlapping
+// each bin, derived from the linear index.
+func (x *Index) binOffsets(ref Reference) map[int]bgzf.VirtualOffset {
+	scheme := x.scheme()
+	offsets := make(map[int]bgzf.VirtualOffset, len(ref.Bins))
+	for bin, chunks := range ref.Bins {
+		offsets[bin] = chunks[0].Begin
+		start, _, err := scheme.Covered(int64(bin))
+		if err == nil && len(ref.Linear.Offsets) > 0 {
+			offsets[bin] = min(offsets[bin], ref.Linear.Offset(start))
+		}
+	}
+	return offsets
+}
+
+// WriteCSI writes the index to w in the bgzip-compressed CSI format, with
+// the tabix header as auxiliary data.
+func (x *Index) WriteCSI(w io.Writer) (int64, error) {
+	header := x.header()
+	data := []byte("CSI\x01")
+	for _, n := range []int{x.MinShift, x.Depth, len(header)} {
+		data = binary.LittleEndian.AppendUint32(data, uint32(n))
+	}
+	data = append(data, header...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(x.References)))
+
+	for _, ref := range x.References {
+		offsets := ref.Offsets
+		if offsets == nil {
+			offsets = x.binOffsets(ref)
+		}
+		data = x.appendBins(data, ref, offsets)
+	}
+	if x.NoCoordinate > 0 {
+		data = binary.LittleEndian.AppendUint64(data, x.NoCoordinate)
+	}
+	return writeCompressed(w, data)
+}
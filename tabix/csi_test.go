@@ -0,0 +1,89 @@
+package tabix
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/martijnvermaat/binning/bgzf"
+)
+
+var longLines = []string{
+	"scaffold1\t100\t200\ta",
+	"scaffold1\t1000000000\t1000000100\tb",
+	"scaffold1\t4294967296\t4294967300\tc",
+}
+
+func TestBuildCSI(t *testing.T) {
+	data := compress(longLines, true)
+	if _, error := Build(bytes.NewReader(data), BED); error == nil {
+		t.Errorf("Build did not return error for positions beyond 2^29")
+	}
+
+	x, error := BuildCSI(bytes.NewReader(data), BED, 14, 7)
+	if error != nil {
+		t.Fatalf("BuildCSI error: %v", error)
+	}
+	if _, error := x.WriteTo(&bytes.Buffer{}); error == nil {
+		t.Errorf("WriteTo did not return error for CSI parameters")
+	}
+
+	var buffer bytes.Buffer
+	if _, error := x.WriteCSI(&buffer); error != nil {
+		t.Fatalf("WriteCSI error: %v", error)
+	}
+	y, error := Read(&buffer)
+	if error != nil {
+		t.Fatalf("Read error: %v", error)
+	}
+	if y.MinShift != 14 || y.Depth != 7 || y.Config != BED || !reflect.DeepEqual(y.Names, x.Names) {
+		t.Errorf("Read(WriteCSI(x)) = %v, expected %v", y, x)
+	}
+	if !reflect.DeepEqual(y.References[0].Bins, x.References[0].Bins) || len(y.References[0].Offsets) != 3 {
+		t.Errorf("Read(WriteCSI(x)) references = %v, expected %v", y.References, x.References)
+	}
+
+	r := bgzf.NewReader(bytes.NewReader(data))
+	for _, index := range []*Index{x, y} {
+		lines, error := index.Query(r, "scaffold1", 4294967290, 4294967297)
+		if error != nil || len(lines) != 1 || lines[0] != longLines[2] {
+			t.Errorf("Query(\"scaffold1\", 4294967290, 4294967297) = %q, %v, expected %q", lines, error, longLines[2:])
+		}
+		// Offsets exclude the chunks for records a and b.
+		chunks, _ := index.Chunks("scaffold1", 4294967290, 4294967297)
+		if len(chunks) != 1 || chunks[0] != x.References[0].Bins[299593+(4294967296>>14)][0] {
+			t.Errorf("Chunks(\"scaffold1\", 4294967290, 4294967297) = %v", chunks)
+		}
+	}
+}
+
+func TestBuildCSIInvalid(t *testing.T) {
+	tests := []struct {
+		minShift, depth int
+	}{
+		{0, 5},
+		{14, 0},
+		{14, 11},
+		{40, 8},
+	}
+	for _, test := range tests {
+		if _, error := BuildCSI(bytes.NewReader(compress(longLines, false)), BED, test.minShift, test.depth); error == nil {
+			t.Errorf("BuildCSI(%d, %d) did not return error", test.minShift, test.depth)
+		}
+	}
+}
+
+func TestReadCSIWithoutNames(t *testing.T) {
+	x, _ := BuildCSI(bytes.NewReader(compress(longLines, false)), BED, 14, 7)
+	x.Names = nil
+	var buffer bytes.Buffer
+	x.WriteCSI(&buffer)
+
+	y, error := Read(&buffer)
+	if error != nil {
+		t.Fatalf("Read error: %v", error)
+	}
+	if y.Names != nil || len(y.References) != 1 {
+		t.Errorf("Read names = %v, references = %d, expected none and 1", y.Names, len(y.References))
+	}
+}
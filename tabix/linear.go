@@ -0,0 +1,73 @@
+package tabix
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/martijnvermaat/binning/bgzf"
+)
+
+// A LinearIndex holds, for each window of 2^Shift positions on a sequence,
+// the virtual offset of the first record overlapping the window, or of the
+// first record after the window if there is none. This offset is a lower
+// bound for reading records overlapping any position in the window, which
+// the bins alone do not give for records in large bins.
+type LinearIndex struct {
+	Shift   int
+	Offsets []bgzf.VirtualOffset
+}
+
+// Add adds the record at virtual offset offset spanning the interval
+// start:stop. Records must be added in order of start position.
+func (l *LinearIndex) Add(start, stop int64, offset bgzf.VirtualOffset) {
+	last := start >> l.Shift
+	if stop > start {
+		last = (stop - 1) >> l.Shift
+	}
+	// Windows before start without records get the offset of this record,
+	// windows overlapping earlier records already have an offset.
+	for int64(len(l.Offsets)) <= last {
+		l.Offsets = append(l.Offsets, offset)
+	}
+}
+
+// Offset returns the virtual offset from which to read records overlapping
+// position pos.
+func (l LinearIndex) Offset(pos int64) bgzf.VirtualOffset {
+	if len(l.Offsets) == 0 || pos < 0 {
+		return 0
+	}
+	return l.Offsets[min(pos>>l.Shift, int64(len(l.Offsets)-1))]
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The
+// offsets are encoded as in tabix and BAI indexes, the shift is not encoded.
+func (l LinearIndex) MarshalBinary() ([]byte, error) {
+	return l.appendBinary(nil), nil
+}
+
+func (l LinearIndex) appendBinary(data []byte) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(l.Offsets)))
+	for _, offset := range l.Offsets {
+		data = binary.LittleEndian.AppendUint64(data, uint64(offset))
+	}
+	return data
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. The
+// shift is left unchanged.
+func (l *LinearIndex) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("truncated linear index")
+	}
+	n := int32(binary.LittleEndian.Uint32(data))
+	if n < 0 || int64(len(data)) != 4+8*int64(n) {
+		return errors.New(fmt.Sprintf("invalid linear index: %d offsets in %d bytes", n, len(data)))
+	}
+	l.Offsets = make([]bgzf.VirtualOffset, n)
+	for i := range l.Offsets {
+		l.Offsets[i] = bgzf.VirtualOffset(binary.LittleEndian.Uint64(data[4+8*i:]))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package tabix
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/martijnvermaat/binning/bgzf"
+)
+
+func TestLinearIndex(t *testing.T) {
+	l := LinearIndex{Shift: 4}
+	l.Add(2, 40, 10)  // Windows 0, 1, 2.
+	l.Add(20, 21, 20) // Window 1.
+	l.Add(70, 70, 30) // Window 4, window 3 has no records.
+	l.Add(75, 90, 40) // Windows 4, 5.
+
+	expected := []bgzf.VirtualOffset{10, 10, 10, 30, 30, 40}
+	if !reflect.DeepEqual(l.Offsets, expected) {
+		t.Errorf("LinearIndex.Add offsets = %v, expected %v", l.Offsets, expected)
+	}
+
+	tests := []struct {
+		pos      int64
+		expected bgzf.VirtualOffset
+	}{
+		{-1, 0},
+		{0, 10},
+		{47, 10},
+		{48, 30},
+		{79, 30},
+		{80, 40},
+		{95, 40},
+		{1000, 40},
+	}
+	for _, test := range tests {
+		if offset := l.Offset(test.pos); offset != test.expected {
+			t.Errorf("LinearIndex.Offset(%d) = %v, expected %v", test.pos, offset, test.expected)
+		}
+	}
+
+	if offset := (LinearIndex{Shift: 4}).Offset(10); offset != 0 {
+		t.Errorf("LinearIndex.Offset(10) = %v for empty index, expected 0", offset)
+	}
+}
+
+func TestLinearIndexBinary(t *testing.T) {
+	l := LinearIndex{Shift: 14, Offsets: []bgzf.VirtualOffset{0, 1 << 20, 1<<40 | 5}}
+	data, error := l.MarshalBinary()
+	if error != nil {
+		t.Fatalf("MarshalBinary error: %v", error)
+	}
+	if len(data) != 28 {
+		t.Errorf("MarshalBinary = %d bytes, expected 28", len(data))
+	}
+
+	m := LinearIndex{Shift: 14}
+	if error := m.UnmarshalBinary(data); error != nil {
+		t.Fatalf("UnmarshalBinary error: %v", error)
+	}
+	if !reflect.DeepEqual(l, m) {
+		t.Errorf("UnmarshalBinary(MarshalBinary(l)) = %v, expected %v", m, l)
+	}
+
+	for _, data := range [][]byte{nil, data[:27], append(data, 0), {0xff, 0xff, 0xff, 0xff}} {
+		if error := m.UnmarshalBinary(data); error == nil {
+			t.Errorf("UnmarshalBinary(%v) did not return error", data)
+		}
+	}
+}
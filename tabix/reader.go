@@ -0,0 +1,269 @@
+package tabix
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/martijnvermaat/binning/bgzf"
+)
+
+// decoder reads little-endian values from an uncompressed index.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.data) {
+		d.err = errors.New("truncated index")
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.bytes(4); b != nil {
+		return int32(binary.LittleEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if b := d.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (d *decoder) chunks() []Chunk {
+	n := d.int32()
+	if n < 0 || int(n) > len(d.data)/16 {
+		d.err = errors.New("truncated index")
+		return nil
+	}
+	chunks := make([]Chunk, n)
+	for i := range chunks {
+		chunks[i] = Chunk{bgzf.VirtualOffset(d.uint64()), bgzf.VirtualOffset(d.uint64())}
+	}
+	return chunks
+}
+
+// Read reads a bgzip-compressed tabix or CSI index from r.
+func Read(r io.Reader) (*Index, error) {
+	data, err := io.ReadAll(bgzf.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	d := &decoder{data: data}
+
+	x := &Index{MinShift: 14, Depth: 5}
+	var n int32
+	magic := string(d.bytes(4))
+	switch magic {
+	case "TBI\x01":
+		n = d.int32()
+		d.header(x)
+	case "CSI\x01":
+		x.MinShift, x.Depth = int(d.int32()), int(d.int32())
+		if err := validCSI(x.MinShift, x.Depth); err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid CSI index: %v", err))
+		}
+		if aux := d.bytes(int(d.int32())); len(aux) > 0 {
+			a := &decoder{data: aux}
+			a.header(x)
+			if a.err != nil {
+				return nil, errors.New(fmt.Sprintf("invalid CSI index: auxiliary data: %v", a.err))
+			}
+		}
+		n = d.int32()
+	default:
+		return nil, errors.New("invalid index: unknown magic")
+	}
+	if d.err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid index: %v", d.err))
+	}
+	if n < 0 || (x.Names != nil && int(n) != len(x.Names)) {
+		return nil, errors.New(fmt.Sprintf("invalid index: %d references but %d names", n, len(x.Names)))
+	}
+	if err := d.references(x, n, magic == "CSI\x01"); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// references reads the indexes for n sequences into x, followed by the
+// optional number of records without coordinates.
+func (d *decoder) references(x *Index, n int32, csi bool) error {
+	x.References = make([]Reference, n)
+	for i := range x.References {
+		x.References[i] = d.reference(x.metaBin(), csi)
+		x.References[i].Linear.Shift = x.MinShift
+	}
+	if len(d.data) >= 8 {
+		x.NoCoordinate = d.uint64()
+	}
+	if d.err != nil {
+		return errors.New(fmt.Sprintf("invalid index: %v", d.err))
+	}
+	return nil
+}
+
+// header reads the tabix header into x.
+func (d *decoder) header(x *Index) {
+	x.Format, x.SeqColumn, x.StartColumn, x.EndColumn = d.int32(), d.int32(), d.int32(), d.int32()
+	x.Meta, x.Skip = byte(d.int32()), d.int32()
+	if names := d.bytes(int(d.int32())); len(names) > 0 {
+		x.Names = strings.Split(strings.TrimSuffix(string(names), "\x00"), "\x00")
+	}
+}
+
+// reference reads the index for one sequence. Bins are preceded by their
+// offset in the CSI format, which has no linear index.
+func (d *decoder) reference(metaBin int, csi bool) Reference {
+	ref := Reference{Bins: make(map[int][]Chunk)}
+	if csi {
+		ref.Offsets = make(map[int]bgzf.VirtualOffset)
+	}
+	for bins := d.int32(); bins > 0 && d.err == nil; bins-- {
+		bin := int(uint32(d.int32()))
+		var offset uint64
+		if csi {
+			offset = d.uint64()
+		}
+		chunks := d.chunks()
+		if bin == metaBin && len(chunks) == 2 {
+			ref.Begin, ref.End = chunks[0].Begin, chunks[0].End
+			ref.Records, ref.Unmapped = uint64(chunks[1].Begin), uint64(chunks[1].End)
+			continue
+		}
+		ref.Bins[bin] = append(ref.Bins[bin], chunks...)
+		if csi {
+			ref.Offsets[bin] = bgzf.VirtualOffset(offset)
+		}
+	}
+	if csi {
+		return ref
+	}
+	if d.err == nil && len(d.data) >= 4 {
+		n := int64(binary.LittleEndian.Uint32(d.data))
+		if data := d.bytes(int(min(4+8*n, int64(len(d.data)+1)))); data != nil {
+			d.err = ref.Linear.UnmarshalBinary(data)
+		}
+	} else if d.err == nil {
+		d.err = errors.New("truncated index")
+	}
+	return ref
+}
+
+// Chunks returns the chunks of the indexed file to read for records
+// overlapping the region start:stop on sequence chrom, with 0-based start and
+// exclusive stop positions. Chunks are sorted and do not overlap. Chunks
+// ending before the first record overlapping the region according to the
+// linear index are left out.
+func (x *Index) Chunks(chrom string, start, stop int64) ([]Chunk, error) {
+	i := slices.Index(x.Names, chrom)
+	if i < 0 {
+		return nil, errors.New(fmt.Sprintf("unknown sequence: %q", chrom))
+	}
+	return x.ChunksByID(i, start, stop)
+}
+
+// ChunksByID returns the chunks of the indexed file to read for records
+// overlapping the region start:stop on the sequence with index id, as with
+// Chunks. BAI indexes do not include sequence names, so they can only be
+// queried by the position of the sequence in the BAM header.
+func (x *Index) ChunksByID(id int, start, stop int64) ([]Chunk, error) {
+	if id < 0 || id >= len(x.References) {
+		return nil, errors.New(fmt.Sprintf("invalid sequence id: %d", id))
+	}
+	ref := x.References[id]
+
+	scheme := x.scheme().Clamped()
+	bins, err := scheme.Overlapping(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	start = max(start, 0)
+
+	var lowest bgzf.VirtualOffset
+	if len(ref.Linear.Offsets) > 0 {
+		lowest = ref.Linear.Offset(start)
+	} else if ref.Offsets != nil {
+		// The offset of the smallest bin in the index containing start.
+		containing, _ := scheme.Overlapping(start, start+1)
+		for _, bin := range containing {
+			if offset, ok := ref.Offsets[int(bin)]; ok {
+				lowest = offset
+				break
+			}
+		}
+	}
+
+	var chunks []Chunk
+	for _, bin := range bins {
+		for _, c := range ref.Bins[int(bin)] {
+			if c.End > lowest {
+				chunks = append(chunks, c)
+			}
+		}
+	}
+	slices.SortFunc(chunks, func(a, b Chunk) int { return cmp.Compare(a.Begin, b.Begin) })
+
+	var merged []Chunk
+	for _, c := range chunks {
+		if n := len(merged); n > 0 && c.Begin <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, c.End)
+		} else {
+			merged = append(merged, c)
+		}
+	}
+	return merged, nil
+}
+
+// Query returns the lines from the indexed file read by r with records
+// overlapping the region start:stop on sequence chrom, with 0-based start and
+// exclusive stop positions.
+func (x *Index) Query(r *bgzf.Reader, chrom string, start, stop int64) ([]string, error) {
+	chunks, err := x.Chunks(chrom, start, stop)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, c := range chunks {
+		if err := r.Seek(c.Begin); err != nil {
+			return nil, err
+		}
+		for r.Offset() < c.End {
+			data, err := r.ReadBytes('\n')
+			if err != nil && (err != io.EOF || len(data) == 0) {
+				return nil, err
+			}
+			text := strings.TrimRight(string(data), "\r\n")
+			if text == "" || (x.Meta != 0 && text[0] == x.Meta) {
+				continue
+			}
+			name, s, e, err := x.parse(text)
+			if err != nil {
+				return nil, err
+			}
+			if name != chrom || s >= max(stop, start+1) {
+				break
+			}
+			if max(s, start) < min(max(e, s+1), max(stop, start+1)) {
+				lines = append(lines, text)
+			}
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,129 @@
+package tabix
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/martijnvermaat/binning/bgzf"
+)
+
+func TestRead(t *testing.T) {
+	x, error := Build(bytes.NewReader(compress(bedLines, true)), BED)
+	if error != nil {
+		t.Fatalf("Build error: %v", error)
+	}
+	var buffer bytes.Buffer
+	x.WriteTo(&buffer)
+
+	y, error := Read(&buffer)
+	if error != nil {
+		t.Fatalf("Read error: %v", error)
+	}
+	if !reflect.DeepEqual(x, y) {
+		t.Errorf("Read(WriteTo(x)) = %v, expected %v", y, x)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	x, _ := Build(bytes.NewReader(compress(bedLines, false)), BED)
+	var buffer bytes.Buffer
+	x.WriteTo(&buffer)
+	valid := buffer.Bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not bgzf", []byte("TBI\x01")},
+		{"magic", compress([]string{"CSI\x01"}, false)},
+		{"truncated", compress([]string{"TBI\x01\x02\x00\x00\x00"}, false)},
+		{"truncated compressed", valid[:len(valid)/2]},
+	}
+	for _, test := range tests {
+		if _, error := Read(bytes.NewReader(test.data)); error == nil {
+			t.Errorf("Read(%s) did not return error", test.name)
+		}
+	}
+}
+
+func TestChunks(t *testing.T) {
+	x, error := Build(bytes.NewReader(compress(bedLines, true)), BED)
+	if error != nil {
+		t.Fatalf("Build error: %v", error)
+	}
+	chr1 := x.References[0]
+	a, b, c := chr1.Bins[4681][0], chr1.Bins[585][0], chr1.Bins[4683][0]
+
+	tests := []struct {
+		chrom       string
+		start, stop int64
+		expected    []Chunk
+	}{
+		// Records are in consecutive blocks, so their chunks merge.
+		{"chr1", 0, 100000, []Chunk{{a.Begin, c.End}}},
+		{"chr1", 16384, 16385, []Chunk{b}},
+		// The linear index shows no record in window 2 starts before c.
+		{"chr1", 40000, 1 << 40, []Chunk{c}},
+		{"chr1", 100000, 200000, nil},
+		{"chr2", 0, 1, []Chunk{x.References[1].Bins[4681][0]}},
+	}
+	for _, test := range tests {
+		chunks, error := x.Chunks(test.chrom, test.start, test.stop)
+		if error != nil {
+			t.Errorf("Chunks(%q, %d, %d) error: %v", test.chrom, test.start, test.stop, error)
+			continue
+		}
+		if !reflect.DeepEqual(chunks, test.expected) {
+			t.Errorf("Chunks(%q, %d, %d) = %v, expected %v", test.chrom, test.start, test.stop, chunks, test.expected)
+		}
+	}
+
+	if _, error := x.Chunks("chr3", 0, 100); error == nil {
+		t.Errorf("Chunks(\"chr3\", 0, 100) did not return error")
+	}
+	if _, error := x.Chunks("chr1", 1<<29, 1<<30); error == nil {
+		t.Errorf("Chunks(\"chr1\", 1<<29, 1<<30) did not return error")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	for _, separate := range []bool{false, true} {
+		data := compress(bedLines, separate)
+		x, error := Build(bytes.NewReader(data), BED)
+		if error != nil {
+			t.Fatalf("Build error: %v", error)
+		}
+		r := bgzf.NewReader(bytes.NewReader(data))
+
+		tests := []struct {
+			chrom       string
+			start, stop int64
+			expected    []string
+		}{
+			{"chr1", 0, 100000, []string{"a", "b", "c"}},
+			{"chr1", 199, 201, []string{"a", "b"}},
+			{"chr1", 200, 201, []string{"b"}},
+			{"chr1", 20000, 40000, nil},
+			{"chr1", 40000, 40000, []string{"c"}},
+			{"chr2", 5, 6, []string{"d"}},
+		}
+		for _, test := range tests {
+			lines, error := x.Query(r, test.chrom, test.start, test.stop)
+			if error != nil {
+				t.Errorf("Query(%q, %d, %d) error: %v", test.chrom, test.start, test.stop, error)
+				continue
+			}
+			var names []string
+			for _, line := range lines {
+				fields := strings.Split(line, "\t")
+				names = append(names, fields[len(fields)-1])
+			}
+			if !reflect.DeepEqual(names, test.expected) {
+				t.Errorf("Query(%q, %d, %d) = %v, expected %v", test.chrom, test.start, test.stop, names, test.expected)
+			}
+		}
+	}
+}
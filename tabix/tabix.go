@@ -0,0 +1,382 @@
+// Package tabix reads and writes tabix (.tbi) and CSI (.csi) indexes for
+// bgzip-compressed, coordinate-sorted tab-delimited files such as BED, GFF
+// and VCF files, and uses them for random access to such files by region.
+// It also reads BAI (.bai) indexes for region queries on BAM files. Bins are
+// computed with the CSI binning schemes from the binning package.
+// http://samtools.github.io/hts-specs/tabix.pdf
+// http://samtools.github.io/hts-specs/CSIv1.pdf
+package tabix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/martijnvermaat/binning"
+	"github.com/martijnvermaat/binning/bgzf"
+)
+
+// Formats of indexed files.
+const (
+	FormatGeneric int32 = 0
+	FormatSAM     int32 = 1
+	FormatVCF     int32 = 2
+
+	// ZeroBased can be combined with a format to indicate that positions
+	// are 0-based with exclusive end positions, as in BED files, instead of
+	// 1-based with inclusive end positions.
+	ZeroBased int32 = 0x10000
+)
+
+// A Config describes the layout of an indexed file. Columns are numbered
+// from 1. If EndColumn is 0, the end position is derived from the record for
+// the SAM and VCF formats, and records span one position otherwise. Lines
+// starting with Meta and the first Skip lines are not indexed.
+type Config struct {
+	Format      int32
+	SeqColumn   int32
+	StartColumn int32
+	EndColumn   int32
+	Meta        byte
+	Skip        int32
+}
+
+// Configs for common file formats.
+var (
+	BED = Config{Format: FormatGeneric | ZeroBased, SeqColumn: 1, StartColumn: 2, EndColumn: 3, Meta: '#'}
+	GFF = Config{Format: FormatGeneric, SeqColumn: 1, StartColumn: 4, EndColumn: 5, Meta: '#'}
+	VCF = Config{Format: FormatVCF, SeqColumn: 1, StartColumn: 2, Meta: '#'}
+	SAM = Config{Format: FormatSAM, SeqColumn: 3, StartColumn: 4, Meta: '@'}
+)
+
+// A Chunk is a range of virtual file offsets in the indexed file.
+type Chunk struct {
+	Begin, End bgzf.VirtualOffset
+}
+
+// A Reference holds the index for one sequence. Bins maps bin numbers to
+// chunks of records assigned to that bin. Linear is the linear index with
+// windows of 2^MinShift positions. CSI indexes store no linear index, but
+// instead the virtual offset of the first record overlapping each bin in
+// Offsets. Begin and End span all records for the sequence, of which there
+// are Records, and Unmapped counts unmapped reads placed on the sequence in
+// BAM files.
+type Reference struct {
+	Bins     map[int][]Chunk
+	Linear   LinearIndex
+	Offsets  map[int]bgzf.VirtualOffset
+	Begin    bgzf.VirtualOffset
+	End      bgzf.VirtualOffset
+	Records  uint64
+	Unmapped uint64
+}
+
+// An Index is a tabix, CSI or BAI index. Bins are those of the binning
+// scheme created by binning.NewCSIScheme with MinShift and Depth, which are 14
+// and 5 for tabix and BAI indexes. NoCoordinate counts records without a
+// position, such as unplaced unmapped reads in BAM files.
+type Index struct {
+	Config
+	MinShift     int
+	Depth        int
+	Names        []string
+	References   []Reference
+	NoCoordinate uint64
+}
+
+// scheme returns the binning scheme used by the index.
+func (x *Index) scheme() binning.Scheme[int64] {
+	return binning.NewCSIScheme[int64](x.MinShift, x.Depth)
+}
+
+// metaBin returns the pseudo-bin holding the span and number of records for
+// a reference, which is the bin after the last bin in the binning scheme.
+func (x *Index) metaBin() int {
+	return (1<<(3*(x.Depth+1))-1)/7 + 1
+}
+
+// Build reads the bgzip-compressed file from r and returns a tabix index for
+// it. Records must be grouped by sequence and sorted by start position within
+// each sequence.
+func Build(r io.Reader, c Config) (*Index, error) {
+	return BuildCSI(r, c, 14, 5)
+}
+
+// BuildCSI reads the bgzip-compressed file from r and returns an index for
+// it with the binning scheme created by binning.NewCSIScheme with minShift
+// and depth. Records must be grouped by sequence and sorted by start position
+// within each sequence.
+func BuildCSI(r io.Reader, c Config, minShift, depth int) (*Index, error) {
+	if c.SeqColumn < 1 || c.StartColumn < 1 || c.EndColumn < 0 {
+		return nil, errors.New(fmt.Sprintf("invalid tabix columns: %d, %d, %d", c.SeqColumn, c.StartColumn, c.EndColumn))
+	}
+	if err := validCSI(minShift, depth); err != nil {
+		return nil, err
+	}
+
+	x := &Index{Config: c, MinShift: minShift, Depth: depth}
+	scheme := x.scheme()
+	seen := make(map[string]bool)
+	reader := bgzf.NewReader(r)
+
+	var ref *Reference
+	var chunk Chunk
+	var last int64
+	bin := -1
+
+	flush := func() {
+		if bin >= 0 {
+			ref.Bins[bin] = append(ref.Bins[bin], chunk)
+		}
+	}
+
+	for line := 1; ; line++ {
+		begin := reader.Offset()
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(data) == 0 {
+			break
+		}
+		end := reader.Offset()
+
+		text := strings.TrimRight(string(data), "\r\n")
+		if int32(line) <= c.Skip || text == "" || (c.Meta != 0 && text[0] == c.Meta) {
+			continue
+		}
+		name, start, stop, err := c.parse(text)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid line %d: %v", line, err))
+		}
+		recordBin, err := scheme.Assign(start, stop)
+		if err == nil && recordBin >= int64(x.metaBin()) {
+			err = errors.New(fmt.Sprintf("invalid bin: %d", recordBin))
+		}
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid line %d: %v", line, err))
+		}
+
+		if len(x.Names) == 0 || name != x.Names[len(x.Names)-1] {
+			if seen[name] {
+				return nil, errors.New(fmt.Sprintf("invalid line %d: records for sequence %q are not grouped", line, name))
+			}
+			flush()
+			seen[name] = true
+			x.Names = append(x.Names, name)
+			x.References = append(x.References, Reference{Bins: make(map[int][]Chunk), Linear: LinearIndex{Shift: minShift}, Begin: begin})
+			ref, bin, last = &x.References[len(x.References)-1], -1, 0
+		}
+		if start < last {
+			return nil, errors.New(fmt.Sprintf("invalid line %d: records for sequence %q are not sorted", line, name))
+		}
+		last = start
+
+		if int(recordBin) != bin {
+			flush()
+			bin, chunk.Begin = int(recordBin), begin
+		}
+		chunk.End = end
+		ref.End = end
+		ref.Records++
+		ref.Linear.Add(start, stop, begin)
+	}
+	flush()
+
+	for i := range x.References {
+		x.References[i].finish()
+	}
+	return x, nil
+}
+
+// finish merges chunks in the same bin that start in the block where the
+// previous chunk ends.
+func (ref *Reference) finish() {
+	for bin, chunks := range ref.Bins {
+		merged := chunks[:1]
+		for _, c := range chunks[1:] {
+			if previous := &merged[len(merged)-1]; c.Begin.Block() <= previous.End.Block() {
+				previous.End = max(previous.End, c.End)
+			} else {
+				merged = append(merged, c)
+			}
+		}
+		ref.Bins[bin] = merged
+	}
+}
+
+// parse returns the sequence name and the 0-based start and exclusive stop
+// position of the record on line text.
+func (c Config) parse(text string) (string, int64, int64, error) {
+	fields := strings.Split(text, "\t")
+	column := func(n int32) (string, error) {
+		if int(n) > len(fields) {
+			return "", errors.New(fmt.Sprintf("expected at least %d columns, found %d", n, len(fields)))
+		}
+		return fields[n-1], nil
+	}
+	position := func(n int32) (int64, error) {
+		field, err := column(n)
+		if err != nil {
+			return 0, err
+		}
+		p, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return 0, errors.New(fmt.Sprintf("invalid position %q in column %d", field, n))
+		}
+		return p, nil
+	}
+
+	name, err := column(c.SeqColumn)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	start, err := position(c.StartColumn)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if c.Format&ZeroBased == 0 {
+		start--
+	}
+	if start < 0 {
+		return "", 0, 0, errors.New(fmt.Sprintf("invalid start position: %d", start))
+	}
+
+	stop := start + 1
+	switch {
+	case c.EndColumn > 0:
+		if stop, err = position(c.EndColumn); err != nil {
+			return "", 0, 0, err
+		}
+	case c.Format&0xffff == FormatVCF:
+		ref, err := column(4)
+		if err != nil {
+			return "", 0, 0, err
+		}
+		stop = start + int64(max(len(ref), 1))
+		if info, err := column(8); err == nil {
+			for _, field := range strings.Split(info, ";") {
+				if value, ok := strings.CutPrefix(field, "END="); ok {
+					if end, err := strconv.ParseInt(value, 10, 64); err == nil && end > start {
+						stop = end
+					}
+				}
+			}
+		}
+	case c.Format&0xffff == FormatSAM:
+		cigar, err := column(6)
+		if err != nil {
+			return "", 0, 0, err
+		}
+		stop = start + int64(max(referenceLength(cigar), 1))
+	}
+	return name, start, stop, nil
+}
+
+// referenceLength returns the number of reference positions covered by the
+// alignment described by cigar.
+func referenceLength(cigar string) int {
+	length, n := 0, 0
+	for _, r := range cigar {
+		switch {
+		case r >= '0' && r <= '9':
+			n = 10*n + int(r-'0')
+		case strings.ContainsRune("MDN=X", r):
+			length, n = length+n, 0
+		default:
+			n = 0
+		}
+	}
+	return length
+}
+
+// header returns the tabix header with the configuration and sequence
+// names.
+func (x *Index) header() []byte {
+	var names []byte
+	for _, name := range x.Names {
+		names = append(append(names, name...), 0)
+	}
+	var data []byte
+	for _, n := range []int32{x.Format, x.SeqColumn, x.StartColumn, x.EndColumn, int32(x.Meta), x.Skip, int32(len(names))} {
+		data = binary.LittleEndian.AppendUint32(data, uint32(n))
+	}
+	return append(data, names...)
+}
+
+// WriteTo writes the index to w in the bgzip-compressed tabix format. It
+// implements the io.WriterTo interface. The tabix format requires MinShift 14
+// and Depth 5, use WriteCSI for other binning schemes.
+func (x *Index) WriteTo(w io.Writer) (int64, error) {
+	if x.MinShift != 14 || x.Depth != 5 {
+		return 0, errors.New(fmt.Sprintf("tabix format does not support min_shift %d and depth %d", x.MinShift, x.Depth))
+	}
+
+	data := []byte("TBI\x01")
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(x.References)))
+	data = append(data, x.header()...)
+	return writeCompressed(w, x.appendReferences(data))
+}
+
+// appendReferences appends the bins and linear index for each sequence and
+// the number of records without coordinates, as in the tabix and BAI
+// formats.
+func (x *Index) appendReferences(data []byte) []byte {
+	for _, ref := range x.References {
+		data = x.appendBins(data, ref, nil)
+		data = ref.Linear.appendBinary(data)
+	}
+	if x.NoCoordinate > 0 {
+		data = binary.LittleEndian.AppendUint64(data, x.NoCoordinate)
+	}
+	return data
+}
+
+// appendBins appends the bins of ref including the pseudo-bin, each preceded
+// by its offset in the CSI format if offsets is not nil.
+func (x *Index) appendBins(data []byte, ref Reference, offsets map[int]bgzf.VirtualOffset) []byte {
+	bins := make([]int, 0, len(ref.Bins))
+	for bin := range ref.Bins {
+		bins = append(bins, bin)
+	}
+	slices.Sort(bins)
+
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(bins)+1))
+	for _, bin := range bins {
+		data = binary.LittleEndian.AppendUint32(data, uint32(bin))
+		if offsets != nil {
+			data = binary.LittleEndian.AppendUint64(data, uint64(offsets[bin]))
+		}
+		data = appendChunks(data, ref.Bins[bin])
+	}
+	data = binary.LittleEndian.AppendUint32(data, uint32(x.metaBin()))
+	if offsets != nil {
+		data = binary.LittleEndian.AppendUint64(data, 0)
+	}
+	return appendChunks(data, []Chunk{{ref.Begin, ref.End}, {bgzf.VirtualOffset(ref.Records), bgzf.VirtualOffset(ref.Unmapped)}})
+}
+
+// writeCompressed writes data to w bgzip-compressed.
+func writeCompressed(w io.Writer, data []byte) (int64, error) {
+	var compressed bytes.Buffer
+	writer := bgzf.NewWriter(&compressed)
+	writer.Write(data)
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+	return compressed.WriteTo(w)
+}
+
+func appendChunks(data []byte, chunks []Chunk) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(chunks)))
+	for _, c := range chunks {
+		data = binary.LittleEndian.AppendUint64(data, uint64(c.Begin))
+		data = binary.LittleEndian.AppendUint64(data, uint64(c.End))
+	}
+	return data
+}
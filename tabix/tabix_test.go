@@ -0,0 +1,171 @@
+package tabix
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/martijnvermaat/binning/bgzf"
+)
+
+// compress returns the lines bgzip-compressed, with each line in its own
+// block if separate is true.
+func compress(lines []string, separate bool) []byte {
+	var compressed bytes.Buffer
+	w := bgzf.NewWriter(&compressed)
+	for _, line := range lines {
+		w.Write([]byte(line + "\n"))
+		if separate {
+			w.Flush()
+		}
+	}
+	w.Close()
+	return compressed.Bytes()
+}
+
+var bedLines = []string{
+	"#header",
+	"chr1\t100\t200\ta",
+	"chr1\t150\t20000\tb",
+	"chr1\t40000\t40001\tc",
+	"chr2\t0\t10\td",
+}
+
+func TestBuild(t *testing.T) {
+	x, error := Build(bytes.NewReader(compress(bedLines, false)), BED)
+	if error != nil {
+		t.Fatalf("Build error: %v", error)
+	}
+
+	if strings.Join(x.Names, ",") != "chr1,chr2" {
+		t.Errorf("Build names = %v, expected [chr1 chr2]", x.Names)
+	}
+	chr1 := x.References[0]
+	if chr1.Records != 3 {
+		t.Errorf("Build records for chr1 = %d, expected 3", chr1.Records)
+	}
+	// Records a and c are in bins 4681 and 4683 and b is in bin 585.
+	for _, bin := range []int{4681, 585, 4683} {
+		if len(chr1.Bins[bin]) != 1 {
+			t.Errorf("Build chunks for chr1 bin %d = %v, expected 1 chunk", bin, chr1.Bins[bin])
+		}
+	}
+	if len(chr1.Bins) != 3 {
+		t.Errorf("Build bins for chr1 = %v, expected 3 bins", chr1.Bins)
+	}
+	if o := chr1.Linear.Offsets; len(o) != 3 || o[1] != chr1.Bins[585][0].Begin || o[2] != chr1.Bins[4683][0].Begin {
+		t.Errorf("Build linear index for chr1 = %v", chr1.Linear)
+	}
+}
+
+func TestBuildSeparateBlocks(t *testing.T) {
+	x, error := Build(bytes.NewReader(compress(bedLines, true)), BED)
+	if error != nil {
+		t.Fatalf("Build error: %v", error)
+	}
+	chr1 := x.References[0]
+	a, c := chr1.Bins[4681][0], chr1.Bins[4683][0]
+	if a.Begin.Within() != 0 || a.End.Within() != 0 || a.End.Block() <= a.Begin.Block() {
+		t.Errorf("Build chunk for a = %v", a)
+	}
+	if c.Begin.Block() <= a.End.Block() {
+		t.Errorf("Build chunk for c = %v, expected after %v", c, a)
+	}
+}
+
+func TestBuildFormats(t *testing.T) {
+	tests := []struct {
+		config Config
+		line   string
+		bin    int
+	}{
+		{GFF, "chr1\tsrc\tgene\t16385\t32768\t.\t+\t.\tID=x", 4682},
+		{VCF, "chr1\t16385\t.\tA\tG\t.\t.\t.", 4682},
+		{VCF, "chr1\t16380\t.\tACGTACGTAC\tA\t.\t.\t.", 585},
+		{VCF, "chr1\t16385\t.\tA\t<DEL>\t.\t.\tSVTYPE=DEL;END=40000", 585},
+		{SAM, "r1\t0\tchr1\t16380\t60\t5M2I10M\t*\t0\t0\tACGT\t*", 585},
+		{SAM, "r1\t0\tchr1\t16380\t60\t5M\t*\t0\t0\tACGT\t*", 4681},
+	}
+	for _, test := range tests {
+		x, error := Build(bytes.NewReader(compress([]string{test.line}, false)), test.config)
+		if error != nil {
+			t.Errorf("Build(%q) error: %v", test.line, error)
+			continue
+		}
+		if _, ok := x.References[0].Bins[test.bin]; !ok || len(x.References[0].Bins) != 1 {
+			t.Errorf("Build(%q) bins = %v, expected bin %d", test.line, x.References[0].Bins, test.bin)
+		}
+	}
+}
+
+func TestBuildInvalid(t *testing.T) {
+	tests := [][]string{
+		{"chr1\t100\t200", "chr2\t100\t200", "chr1\t300\t400"},
+		{"chr1\t100\t200", "chr1\t50\t200"},
+		{"chr1\tx\t200"},
+		{"chr1\t100"},
+		{"chr1\t-1\t200"},
+		{"chr1\t100\t1000000000"},
+	}
+	for _, lines := range tests {
+		if _, error := Build(bytes.NewReader(compress(lines, false)), BED); error == nil {
+			t.Errorf("Build(%q) did not return error", lines)
+		}
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	x, error := Build(bytes.NewReader(compress(bedLines, false)), BED)
+	if error != nil {
+		t.Fatalf("Build error: %v", error)
+	}
+	var buffer bytes.Buffer
+	n, error := x.WriteTo(&buffer)
+	if error != nil || n != int64(buffer.Len()) {
+		t.Fatalf("WriteTo = %d, %v, expected %d, nil", n, error, buffer.Len())
+	}
+
+	// BGZF files can be read as multi-member gzip files.
+	r, error := gzip.NewReader(&buffer)
+	if error != nil {
+		t.Fatalf("gzip.NewReader error: %v", error)
+	}
+	data, error := io.ReadAll(r)
+	if error != nil {
+		t.Fatalf("ReadAll error: %v", error)
+	}
+	if string(data[:4]) != "TBI\x01" {
+		t.Errorf("WriteTo magic = %q, expected \"TBI\\x01\"", data[:4])
+	}
+	header := make([]int32, 8)
+	binary.Read(bytes.NewReader(data[4:]), binary.LittleEndian, header)
+	expected := []int32{2, ZeroBased, 1, 2, 3, '#', 0, 10}
+	for i := range expected {
+		if header[i] != expected[i] {
+			t.Errorf("WriteTo header = %v, expected %v", header, expected)
+			break
+		}
+	}
+	if names := string(data[36:46]); names != "chr1\x00chr2\x00" {
+		t.Errorf("WriteTo names = %q, expected \"chr1\\x00chr2\\x00\"", names)
+	}
+}
+
+func TestReferenceLength(t *testing.T) {
+	tests := []struct {
+		cigar    string
+		expected int
+	}{
+		{"100M", 100},
+		{"5S10M2I3D4N6=7X3H", 30},
+		{"*", 0},
+	}
+	for _, test := range tests {
+		if length := referenceLength(test.cigar); length != test.expected {
+			t.Errorf("referenceLength(%q) = %d, expected %d", test.cigar, length, test.expected)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package binning
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A TableColumn is a column in a UCSC database table, with a MySQL type such
+// as "varchar(255) NOT NULL".
+type TableColumn struct {
+	Name string
+	Type string
+}
+
+// WriteTableSchema writes the .sql part of a UCSC-style table dump to w: a
+// MySQL statement creating table with the bin, chrom, chromStart and
+// chromEnd columns followed by columns, and an index on chromosome and bin
+// as created by the UCSC loaders.
+func WriteTableSchema(w io.Writer, table string, columns []TableColumn) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE `%s` (\n", table)
+	all := append([]TableColumn{
+		{"bin", "smallint(5) unsigned NOT NULL"},
+		{"chrom", "varchar(255) NOT NULL"},
+		{"chromStart", "int(10) unsigned NOT NULL"},
+		{"chromEnd", "int(10) unsigned NOT NULL"},
+	}, columns...)
+	for _, c := range all {
+		if c.Name == "" || strings.ContainsRune(c.Name, '`') {
+			return errors.New(fmt.Sprintf("invalid column name: %q", c.Name))
+		}
+		fmt.Fprintf(&b, "  `%s` %s,\n", c.Name, c.Type)
+	}
+	b.WriteString("  KEY `chrom` (`chrom`(16),`bin`)\n")
+	b.WriteString(") ENGINE=MyISAM DEFAULT CHARSET=latin1;\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// A TableWriter writes the .txt part of a UCSC-style table dump: one
+// tab-separated row per interval, starting with the bin assigned by a
+// binning scheme.
+type TableWriter struct {
+	scheme Binning
+	writer *bufio.Writer
+}
+
+// NewTableWriter creates a new table writer writing to w with bins assigned
+// by binning scheme b. The bin column in UCSC tables is a smallint, so b
+// should be the standard or extended binning scheme. The caller must call
+// Flush when done.
+func NewTableWriter(w io.Writer, b Binning) *TableWriter {
+	return &TableWriter{scheme: b, writer: bufio.NewWriter(w)}
+}
+
+// Write writes a row for interval start:stop on chromosome chrom with the
+// values for additional columns in fields.
+func (t *TableWriter) Write(chrom string, start, stop int, fields ...string) error {
+	bin, err := t.scheme.Assign(start, stop)
+	if err != nil {
+		return err
+	}
+	if bin > 1<<16-1 {
+		return errors.New(fmt.Sprintf("bin %d does not fit in smallint column", bin))
+	}
+	if strings.ContainsAny(chrom, "\t\n") {
+		return errors.New(fmt.Sprintf("invalid chromosome: %q", chrom))
+	}
+	for _, field := range fields {
+		if strings.ContainsAny(field, "\t\n") {
+			return errors.New(fmt.Sprintf("invalid field: %q", field))
+		}
+	}
+	fmt.Fprintf(t.writer, "%d\t%s\t%d\t%d", bin, chrom, start, stop)
+	for _, field := range fields {
+		t.writer.WriteString("\t" + field)
+	}
+	_, err = t.writer.WriteString("\n")
+	return err
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (t *TableWriter) Flush() error {
+	return t.writer.Flush()
+}
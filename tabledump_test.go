@@ -0,0 +1,65 @@
+package binning
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteTableSchema(t *testing.T) {
+	var b strings.Builder
+	error := WriteTableSchema(&b, "genes", []TableColumn{{"name", "varchar(255) NOT NULL"}, {"score", "int(10) unsigned NOT NULL"}})
+	if error != nil {
+		t.Fatalf("WriteTableSchema error: %v", error)
+	}
+	expected := "CREATE TABLE `genes` (\n" +
+		"  `bin` smallint(5) unsigned NOT NULL,\n" +
+		"  `chrom` varchar(255) NOT NULL,\n" +
+		"  `chromStart` int(10) unsigned NOT NULL,\n" +
+		"  `chromEnd` int(10) unsigned NOT NULL,\n" +
+		"  `name` varchar(255) NOT NULL,\n" +
+		"  `score` int(10) unsigned NOT NULL,\n" +
+		"  KEY `chrom` (`chrom`(16),`bin`)\n" +
+		") ENGINE=MyISAM DEFAULT CHARSET=latin1;\n"
+	if b.String() != expected {
+		t.Errorf("WriteTableSchema = %q, expected %q", b.String(), expected)
+	}
+
+	if error := WriteTableSchema(&b, "genes", []TableColumn{{"a`b", "int"}}); error == nil {
+		t.Errorf("WriteTableSchema did not return error for invalid column name")
+	}
+}
+
+func TestTableWriter(t *testing.T) {
+	var b strings.Builder
+	w := NewTableWriter(&b, StandardBinning())
+	if error := w.Write("chr1", 74012, 173034, "gene1", "0"); error != nil {
+		t.Fatalf("Write error: %v", error)
+	}
+	if error := w.Write("chr2", 0, 10); error != nil {
+		t.Fatalf("Write error: %v", error)
+	}
+	w.Flush()
+
+	expected := "73\tchr1\t74012\t173034\tgene1\t0\n585\tchr2\t0\t10\n"
+	if b.String() != expected {
+		t.Errorf("TableWriter = %q, expected %q", b.String(), expected)
+	}
+
+	tests := []struct {
+		chrom       string
+		start, stop int
+		fields      []string
+	}{
+		{"chr1", 0, 1 << 30, nil},
+		{"chr1", 0, 10, []string{"a\tb"}},
+		{"chr\n1", 0, 10, nil},
+	}
+	for _, test := range tests {
+		if error := w.Write(test.chrom, test.start, test.stop, test.fields...); error == nil {
+			t.Errorf("Write(%q, %d, %d, %q) did not return error", test.chrom, test.start, test.stop, test.fields)
+		}
+	}
+	if error := NewTableWriter(&b, NewCSIBinning(4, 7)).Write("chr1", 0, 1); error == nil {
+		t.Errorf("Write did not return error for bin beyond smallint")
+	}
+}
@@ -0,0 +1,99 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// Tune recommends a binning scheme covering positions >= 0 and <= maxPosition
+// for count intervals with lengths distributed like the given sample of
+// lengths.
+//
+// Schemes are compared on the expected cost of a query with a length drawn
+// from the same sample, defined as the number of bins in the query plus the
+// number of intervals stored in those bins, assuming intervals are uniformly
+// distributed. Considered are all schemes with shifts to the next larger bin
+// of 1 to 4 and the smallest number of levels needed to cover maxPosition.
+func Tune(lengths []int, count, maxPosition int) (Binning, error) {
+	if len(lengths) == 0 {
+		return Binning{}, errors.New("no interval lengths")
+	}
+	if count < 0 {
+		return Binning{}, errors.New(fmt.Sprintf("invalid number of intervals: %d", count))
+	}
+	if maxPosition < 0 {
+		return Binning{}, errors.New(fmt.Sprintf("invalid maximum position: %d", maxPosition))
+	}
+	for _, length := range lengths {
+		if length < 0 || length > maxPosition+1 {
+			return Binning{}, errors.New(fmt.Sprintf("invalid interval length: %d (maximum position is %d)", length, maxPosition))
+		}
+	}
+
+	positionBits := uint(bits.Len(uint(maxPosition)))
+
+	var best Binning
+	bestCost := -1.0
+
+	for shiftNext := uint(1); shiftNext <= 4; shiftNext++ {
+		for shiftFirst := uint(0); shiftFirst <= positionBits; shiftFirst++ {
+			levels := 1
+			if positionBits > shiftFirst {
+				levels += int((positionBits - shiftFirst + shiftNext - 1) / shiftNext)
+			}
+			b, err := NewBinningFromShifts(levels, shiftFirst, shiftNext, maxPosition)
+			if err != nil {
+				// Positions covered by the largest bins overflow.
+				continue
+			}
+			if cost := queryCost(b, lengths, count); bestCost < 0 || cost < bestCost {
+				best, bestCost = b, cost
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// queryCost returns for binning scheme b the expected number of bins plus
+// the expected number of stored intervals in those bins for a query, for
+// count uniformly distributed intervals and queries with lengths drawn from
+// the sample of lengths.
+func queryCost(b Binning, lengths []int, count int) float64 {
+	levels := len(b.binOffsets)
+	positions := float64(b.MaxPosition) + 1
+
+	sizes := make([]float64, levels)
+	for level, shift := range b.shifts {
+		sizes[level] = float64(int(1) << shift)
+	}
+
+	// Fraction of intervals assigned to each level, using that an interval
+	// of length l fits in a bin of size s with probability (s-l+1)/s.
+	fractions := make([]float64, levels)
+	for _, length := range lengths {
+		l := float64(max(length, 1))
+		previous := 0.0
+		for level, size := range sizes {
+			fit := 1.0
+			if level < levels-1 {
+				fit = max(0, (size-l+1)/size)
+			}
+			fractions[level] += (fit - previous) / float64(len(lengths))
+			previous = fit
+		}
+	}
+
+	cost := 0.0
+	for _, length := range lengths {
+		q := float64(max(length, 1))
+		for level, size := range sizes {
+			bins := min((q+size-1)/size, positions/size+1)
+			intervals := float64(count) * fractions[level] * min((q+size-1)/positions, 1)
+			cost += (bins + intervals) / float64(len(lengths))
+		}
+	}
+
+	return cost
+}
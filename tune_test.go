@@ -0,0 +1,48 @@
+package binning
+
+import "testing"
+
+func TestTune(t *testing.T) {
+	short := []int{1, 1, 2, 50, 100, 300}
+	long := []int{1000000, 2000000, 5000000, 10000000}
+
+	shortScheme, error := Tune(short, 10000000, 1<<29-1)
+	if error != nil {
+		t.Fatalf("Tune(%v, %d, %d) returned error: %v", short, 10000000, 1<<29-1, error)
+	}
+	longScheme, error := Tune(long, 10000, 1<<29-1)
+	if error != nil {
+		t.Fatalf("Tune(%v, %d, %d) returned error: %v", long, 10000, 1<<29-1, error)
+	}
+
+	for _, b := range []Binning{shortScheme, longScheme} {
+		if b.MaxPosition != 1<<29-1 {
+			t.Errorf("Tune().MaxPosition = %d, expected %d", b.MaxPosition, 1<<29-1)
+		}
+		if error := checkScheme(b.MaxPosition, b.binOffsets, b.shifts); error != nil {
+			t.Errorf("Tune() returned invalid scheme: %v", error)
+		}
+	}
+
+	if shortScheme.shifts[0] >= longScheme.shifts[0] {
+		t.Errorf("Tune() for short intervals has smallest shift %d, expected less than %d for long intervals",
+			shortScheme.shifts[0], longScheme.shifts[0])
+	}
+}
+
+func TestTuneInvalid(t *testing.T) {
+	for _, v := range []struct {
+		lengths            []int
+		count, maxPosition int
+	}{
+		{[]int{}, 100, 1<<29 - 1},
+		{[]int{100}, -1, 1<<29 - 1},
+		{[]int{100}, 100, -1},
+		{[]int{-1}, 100, 1<<29 - 1},
+		{[]int{1<<29 + 1}, 100, 1<<29 - 1},
+	} {
+		if b, error := Tune(v.lengths, v.count, v.maxPosition); error == nil {
+			t.Errorf("Tune(%v, %d, %d) = %v, expected error", v.lengths, v.count, v.maxPosition, b)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A Partitioning assigns intervals to partitions of a wide-row store such as
+// Cassandra or ScyllaDB. The partition of an interval is the bin assigned to
+// it, raised to a minimum level so partitions are not too small. Rows would
+// then be stored in a table like
+//
+//	CREATE TABLE intervals (
+//	  chrom text, bin bigint, start bigint, stop bigint, id text, ...,
+//	  PRIMARY KEY ((chrom, bin), start, id)
+//	)
+//
+// with partition key (chrom, bin) and clustering on start, and queried per
+// partition with a restriction on start.
+type Partitioning[T Integer] struct {
+	scheme Scheme[T]
+	level  int
+}
+
+// NewPartitioning returns a partitioning with partitions from binning scheme
+// b at level and larger, with level 0 having the smallest bins.
+func NewPartitioning[T Integer](b Scheme[T], level int) (Partitioning[T], error) {
+	if level < 0 || level >= len(b.binOffsets) {
+		return Partitioning[T]{}, errors.New(fmt.Sprintf("invalid level for binning scheme with %d levels: %d", len(b.binOffsets), level))
+	}
+	return Partitioning[T]{scheme: b, level: level}, nil
+}
+
+// PartitionLevel recommends a level for a partitioning with binning scheme b
+// of records intervals evenly spread over a genome of size length. It returns
+// the level with the largest bins for which partitions are expected to hold
+// at most target intervals, or the level with the smallest bins if there is
+// no such level.
+func (b Scheme[T]) PartitionLevel(length T, records, target int) int {
+	for level := len(b.shifts) - 1; level > 0; level-- {
+		size := min(float64(uint64(1)<<b.shifts[level]), float64(length))
+		if float64(records)*size/max(float64(length), 1) <= float64(target) {
+			return level
+		}
+	}
+	return 0
+}
+
+// Partition returns the partition for the interval start:stop.
+func (p Partitioning[T]) Partition(start, stop T) (T, error) {
+	nextRange, err := p.scheme.ranges(start, stop)
+	if err != nil {
+		return 0, err
+	}
+	for level := 0; ; level++ {
+		startBin, stopBin, ok := nextRange()
+		if !ok {
+			break
+		}
+		if level >= p.level && startBin == stopBin {
+			return startBin, nil
+		}
+	}
+	return 0, &InvalidSchemeError{fmt.Sprintf("no level has a single bin for interval %d-%d", start, stop)}
+}
+
+// Overlapping returns the partitions with intervals overlapping the interval
+// start:stop, starting with the smallest partitions. Within a partition, only
+// intervals starting before stop need to be read.
+func (p Partitioning[T]) Overlapping(start, stop T) ([]T, error) {
+	nextRange, err := p.scheme.ranges(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	var partitions []T
+	for level := 0; ; level++ {
+		startBin, stopBin, ok := nextRange()
+		if !ok {
+			break
+		}
+		if level < p.level {
+			continue
+		}
+		for bin := startBin; bin <= stopBin; bin++ {
+			partitions = append(partitions, bin)
+		}
+	}
+	return partitions, nil
+}
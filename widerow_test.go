@@ -0,0 +1,73 @@
+package binning
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewPartitioning(t *testing.T) {
+	for _, level := range []int{-1, 5} {
+		if _, error := NewPartitioning(StandardBinning(), level); error == nil {
+			t.Errorf("NewPartitioning(%d) did not return error", level)
+		}
+	}
+}
+
+func TestPartitionLevel(t *testing.T) {
+	b := StandardBinning()
+	tests := []struct {
+		records, target int
+		expected        int
+	}{
+		{1000, 1000, 4},
+		{1000000, 1000, 0},
+		{1000000, 100000, 2},
+		{100000000, 1, 0},
+	}
+	for _, test := range tests {
+		if level := b.PartitionLevel(250000000, test.records, test.target); level != test.expected {
+			t.Errorf("PartitionLevel(%d, %d) = %d, expected %d", test.records, test.target, level, test.expected)
+		}
+	}
+}
+
+func TestPartitioning(t *testing.T) {
+	p, error := NewPartitioning(StandardBinning(), 1)
+	if error != nil {
+		t.Fatalf("NewPartitioning error: %v", error)
+	}
+
+	tests := []struct {
+		start, stop int
+		expected    int
+	}{
+		{10, 20, 73},
+		{1 << 20, 1<<20 + 1, 74},
+		{1<<20 - 1, 1<<20 + 1, 9},
+		{0, 1 << 29, 0},
+	}
+	for _, test := range tests {
+		partition, error := p.Partition(test.start, test.stop)
+		if error != nil {
+			t.Errorf("Partition(%d, %d) error: %v", test.start, test.stop, error)
+		} else if partition != test.expected {
+			t.Errorf("Partition(%d, %d) = %d, expected %d", test.start, test.stop, partition, test.expected)
+		}
+	}
+
+	partitions, error := p.Overlapping(10, 20)
+	if expected := []int{73, 9, 1, 0}; error != nil || !slices.Equal(partitions, expected) {
+		t.Errorf("Overlapping(10, 20) = %v, %v, expected %v", partitions, error, expected)
+	}
+	partitions, _ = p.Overlapping(1<<20-1, 1<<20+1)
+	if expected := []int{73, 74, 9, 1, 0}; !slices.Equal(partitions, expected) {
+		t.Errorf("Overlapping = %v, expected %v", partitions, expected)
+	}
+
+	if _, error := p.Partition(0, 1<<30); error == nil {
+		t.Errorf("Partition(0, 1<<30) did not return error")
+	}
+	if _, error := p.Overlapping(0, 1<<30); error == nil {
+		t.Errorf("Overlapping(0, 1<<30) did not return error")
+	}
+}
@@ -0,0 +1,61 @@
+package binning
+
+import "container/heap"
+
+// A WindowedBinIndex is a BinIndex in which each entry has a time, such as a
+// sequence number or timestamp, so entries can be evicted in bulk by time.
+// This supports sliding-window analyses over streaming interval data. All
+// query methods of BinIndex are available.
+type WindowedBinIndex[T any] struct {
+	*BinIndex[T]
+	times timedHandles
+}
+
+// timedHandle is the time of an entry in a WindowedBinIndex.
+type timedHandle struct {
+	time   int64
+	handle Handle
+}
+
+// timedHandles implements heap.Interface, ordering handles by time.
+type timedHandles []timedHandle
+
+func (h timedHandles) Len() int           { return len(h) }
+func (h timedHandles) Less(i, j int) bool { return h[i].time < h[j].time }
+func (h timedHandles) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *timedHandles) Push(x any)        { *h = append(*h, x.(timedHandle)) }
+func (h *timedHandles) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// NewWindowedBinIndex creates a new empty windowed index using binning scheme
+// b.
+func NewWindowedBinIndex[T any](b Binning) *WindowedBinIndex[T] {
+	return &WindowedBinIndex[T]{BinIndex: NewBinIndex[T](b)}
+}
+
+// Insert stores value for the interval start:stop at time t and returns a
+// handle for the new entry. Times need not be increasing.
+func (x *WindowedBinIndex[T]) Insert(start, stop int, t int64, value T) (Handle, error) {
+	h, err := x.BinIndex.Insert(start, stop, value)
+	if err != nil {
+		return 0, err
+	}
+	heap.Push(&x.times, timedHandle{time: t, handle: h})
+	return h, nil
+}
+
+// EvictBefore removes all entries with a time before t and returns the number
+// of entries removed. Entries already removed with Delete are not counted.
+func (x *WindowedBinIndex[T]) EvictBefore(t int64) int {
+	n := 0
+	for len(x.times) > 0 && x.times[0].time < t {
+		if x.Delete(heap.Pop(&x.times).(timedHandle).handle) == nil {
+			n++
+		}
+	}
+	return n
+}
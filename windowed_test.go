@@ -0,0 +1,44 @@
+package binning
+
+import "testing"
+
+func TestWindowedBinIndex(t *testing.T) {
+	x := NewWindowedBinIndex[string](StandardBinning())
+	for _, v := range []struct {
+		start, stop int
+		time        int64
+		name        string
+	}{
+		{0, 100, 3, "a"},
+		{50, 150, 1, "b"},
+		{60, 70, 2, "c"},
+		{80, 90, 5, "d"},
+	} {
+		if _, error := x.Insert(v.start, v.stop, v.time, v.name); error != nil {
+			t.Fatalf("Insert(%d, %d, %d, %q) returned error: %v", v.start, v.stop, v.time, v.name, error)
+		}
+	}
+	if h, error := x.Insert(-1, 10, 4, "e"); error == nil {
+		t.Errorf("Insert(%d, %d, %d, %q) = %d, expected error", -1, 10, 4, "e", h)
+	}
+	for _, v := range []struct {
+		time    int64
+		evicted int
+		names   []string
+	}{
+		{1, 0, []string{"a", "b", "c", "d"}},
+		{3, 2, []string{"a", "d"}},
+		{3, 0, []string{"a", "d"}},
+		{10, 2, []string{}},
+	} {
+		if n := x.EvictBefore(v.time); n != v.evicted {
+			t.Errorf("EvictBefore(%d) = %d, expected %d", v.time, n, v.evicted)
+		}
+		if names, _ := x.Overlapping(0, 200); !sameElements(names, v.names) {
+			t.Errorf("Overlapping(%d, %d) = %v, expected %v", 0, 200, names, v.names)
+		}
+		if n := x.Len(); n != len(v.names) {
+			t.Errorf("Len() = %d, expected %d", n, len(v.names))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package binning
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// A Summary holds statistics of scores over the interval Start:Stop covered
+// by a bin, as in the zoom levels of bigWig files. Bases is the number of
+// positions in the interval with a score, and Sum and SumSquares are
+// weighted by the number of positions.
+type Summary struct {
+	Bin        int
+	Start      int
+	Stop       int
+	Bases      int
+	Min        float64
+	Max        float64
+	Sum        float64
+	SumSquares float64
+}
+
+// Mean returns the mean score over the positions with a score.
+func (s Summary) Mean() float64 {
+	if s.Bases == 0 {
+		return math.NaN()
+	}
+	return s.Sum / float64(s.Bases)
+}
+
+// ZoomLevels aggregates scored intervals into summaries per bin on each
+// level of a binning scheme, so data can be shown at a resolution matching
+// the viewport. Levels are numbered starting with 0 for the smallest bins.
+type ZoomLevels struct {
+	scheme Binning
+	levels []map[int]Summary
+}
+
+// NewZoomLevels creates empty zoom levels for the levels of binning scheme
+// b.
+func NewZoomLevels(b Binning) *ZoomLevels {
+	levels := make([]map[int]Summary, len(b.shifts))
+	for level := range levels {
+		levels[level] = make(map[int]Summary)
+	}
+	return &ZoomLevels{scheme: b, levels: levels}
+}
+
+// Add adds score for the positions in interval start:stop to the summaries
+// of all bins overlapping the interval. Empty intervals are ignored.
+func (z *ZoomLevels) Add(start, stop int, score float64) error {
+	nextRange, err := z.scheme.ranges(start, stop)
+	if err != nil || stop <= start {
+		return err
+	}
+
+	for level := 0; ; level++ {
+		first, last, ok := nextRange()
+		if !ok {
+			break
+		}
+		for bin := first; bin <= last; bin++ {
+			binStart, binStop, _ := z.scheme.Covered(bin)
+			bases := min(stop, binStop) - max(start, binStart)
+			s, ok := z.levels[level][bin]
+			if !ok {
+				s = Summary{Bin: bin, Start: binStart, Stop: binStop, Min: score, Max: score}
+			}
+			s.Bases += bases
+			s.Min, s.Max = min(s.Min, score), max(s.Max, score)
+			s.Sum += score * float64(bases)
+			s.SumSquares += score * score * float64(bases)
+			z.levels[level][bin] = s
+		}
+	}
+	return nil
+}
+
+// Level returns the level with the largest bins that still has at least one
+// bin per pixel when showing width positions in the given number of pixels.
+// If even the smallest bins are larger than a pixel, it returns false and
+// the scored intervals should be shown instead of summaries.
+func (z *ZoomLevels) Level(width, pixels int) (int, bool) {
+	if width <= 0 || pixels <= 0 {
+		return 0, false
+	}
+	resolution := width / pixels
+	for level := len(z.scheme.shifts) - 1; level >= 0; level-- {
+		if 1<<z.scheme.shifts[level] <= resolution {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// Summaries returns the summaries for bins on level overlapping the interval
+// start:stop, ordered by position. Bins without scores are left out.
+func (z *ZoomLevels) Summaries(level, start, stop int) ([]Summary, error) {
+	if level < 0 || level >= len(z.levels) {
+		return nil, errors.New(fmt.Sprintf("invalid level: %d", level))
+	}
+	nextRange, err := z.scheme.ranges(start, stop)
+	if err != nil {
+		return nil, err
+	}
+	first, last, _ := nextRange()
+	for l := 0; l < level; l++ {
+		first, last, _ = nextRange()
+	}
+
+	var summaries []Summary
+	if last-first+1 <= len(z.levels[level]) {
+		for bin := first; bin <= last; bin++ {
+			if s, ok := z.levels[level][bin]; ok {
+				summaries = append(summaries, s)
+			}
+		}
+		return summaries, nil
+	}
+	for bin, s := range z.levels[level] {
+		if bin >= first && bin <= last {
+			summaries = append(summaries, s)
+		}
+	}
+	slices.SortFunc(summaries, func(a, b Summary) int { return a.Bin - b.Bin })
+	return summaries, nil
+}
@@ -0,0 +1,104 @@
+package binning
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZoomLevelsAdd(t *testing.T) {
+	z := NewZoomLevels(StandardBinning())
+	if error := z.Add(1<<17-10, 1<<17+30, 2); error != nil {
+		t.Fatalf("Add error: %v", error)
+	}
+	if error := z.Add(1<<17+20, 1<<17+40, 4); error != nil {
+		t.Fatalf("Add error: %v", error)
+	}
+	if error := z.Add(100, 100, 8); error != nil {
+		t.Fatalf("Add error for empty interval: %v", error)
+	}
+
+	summaries, error := z.Summaries(0, 0, 1<<18)
+	if error != nil {
+		t.Fatalf("Summaries error: %v", error)
+	}
+	expected := []Summary{
+		{Bin: 585, Start: 0, Stop: 1 << 17, Bases: 10, Min: 2, Max: 2, Sum: 20, SumSquares: 40},
+		{Bin: 586, Start: 1 << 17, Stop: 1 << 18, Bases: 50, Min: 2, Max: 4, Sum: 140, SumSquares: 440},
+	}
+	if len(summaries) != len(expected) {
+		t.Fatalf("Summaries(0, 0, 1<<18) = %v, expected %v", summaries, expected)
+	}
+	for i := range expected {
+		if summaries[i] != expected[i] {
+			t.Errorf("Summaries(0, 0, 1<<18)[%d] = %v, expected %v", i, summaries[i], expected[i])
+		}
+	}
+
+	top, error := z.Summaries(4, 0, 1<<29)
+	if error != nil || len(top) != 1 || top[0].Bases != 60 || top[0].Mean() != 160.0/60 {
+		t.Errorf("Summaries(4, 0, 1<<29) = %v, %v", top, error)
+	}
+
+	if error := z.Add(0, 1<<30, 1); error == nil {
+		t.Errorf("Add(0, 1<<30, 1) did not return error")
+	}
+}
+
+func TestZoomLevelsSummaries(t *testing.T) {
+	z := NewZoomLevels(StandardBinning())
+	for i := 0; i < 100; i++ {
+		z.Add(i<<17, i<<17+1, float64(i))
+	}
+
+	// Few bins in the range compared to bins with summaries, and the other
+	// way around.
+	for _, region := range [][2]int{{10 << 17, 13 << 17}, {0, 1 << 29}} {
+		summaries, error := z.Summaries(0, region[0], region[1])
+		if error != nil {
+			t.Fatalf("Summaries(0, %d, %d) error: %v", region[0], region[1], error)
+		}
+		expected := min(region[1]>>17, 100) - region[0]>>17
+		if len(summaries) != expected {
+			t.Errorf("Summaries(0, %d, %d) = %d summaries, expected %d", region[0], region[1], len(summaries), expected)
+		}
+		for i, s := range summaries {
+			if s.Start != region[0]+i<<17 || s.Mean() != float64(s.Start>>17) {
+				t.Errorf("Summaries(0, %d, %d)[%d] = %v", region[0], region[1], i, s)
+			}
+		}
+	}
+
+	for _, level := range []int{-1, 5} {
+		if _, error := z.Summaries(level, 0, 100); error == nil {
+			t.Errorf("Summaries(%d, 0, 100) did not return error", level)
+		}
+	}
+}
+
+func TestZoomLevelsLevel(t *testing.T) {
+	z := NewZoomLevels(StandardBinning())
+	tests := []struct {
+		width, pixels int
+		level         int
+		ok            bool
+	}{
+		{1 << 29, 1, 4, true},
+		{1 << 29, 100, 1, true},
+		{1 << 29, 1000, 0, true},
+		{1 << 20, 8, 0, true},
+		{1 << 20, 1000, 0, false},
+		{0, 1000, 0, false},
+	}
+	for _, test := range tests {
+		level, ok := z.Level(test.width, test.pixels)
+		if level != test.level || ok != test.ok {
+			t.Errorf("Level(%d, %d) = %d, %v, expected %d, %v", test.width, test.pixels, level, ok, test.level, test.ok)
+		}
+	}
+}
+
+func TestSummaryMean(t *testing.T) {
+	if mean := (Summary{}).Mean(); !math.IsNaN(mean) {
+		t.Errorf("Summary{}.Mean() = %v, expected NaN", mean)
+	}
+}